@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/telegramfake"
+)
+
+func main() {
+	logger := log.New(os.Stdout, "fake-telegram: ", log.LstdFlags)
+	logger.Println("Starting fake Telegram Bot API server...")
+
+	addr := getEnv("FAKE_TELEGRAM_ADDR", ":8081")
+	token := getEnv("FAKE_TELEGRAM_TOKEN", "")
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: telegramfake.NewServer(token),
+	}
+
+	go func() {
+		logger.Printf("Fake Telegram API listening on %s", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("Failed to start fake Telegram API: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-quit
+	logger.Printf("Received signal %s, shutting down...", sig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Fatalf("Error during server shutdown: %v", err)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -10,8 +11,13 @@ import (
 	"time"
 
 	"github.com/4Amangel1/smart-house-automate/internal/api"
+	"github.com/4Amangel1/smart-house-automate/internal/automation"
+	"github.com/4Amangel1/smart-house-automate/internal/climate"
 	"github.com/4Amangel1/smart-house-automate/internal/config"
 	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/devices"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/factory"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
 )
 
 func main() {
@@ -32,8 +38,56 @@ func main() {
 	defer repo.Close()
 	logger.Println("Connected to database")
 
+	// Создание исполнительных устройств
+	deviceFactory, err := factory.New(cfg.Devices)
+	if err != nil {
+		logger.Fatalf("Failed to create device factory: %v", err)
+	}
+
+	deviceManager := devices.NewManager(deviceFactory.GetAllDevices(), repo, logger)
+	deviceManager.RecordStates(devices.SourceStartup)
+	logger.Printf("Initialized %d devices", len(deviceManager.List()))
+
+	// Загрузка правил и запуск движка автоматизации
+	rules, err := automation.LoadRules(cfg.Automation.RulesFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Fatalf("Failed to load automation rules: %v", err)
+		}
+		logger.Printf("Automation rules file %s not found, automation is disabled", cfg.Automation.RulesFile)
+	}
+
+	engine := automation.NewEngine(rules, repo, deviceManager, automation.NewAlertNotifier(repo), cfg.Automation, logger)
+	engine.Start()
+	defer engine.Stop()
+
+	// Запуск регулирования температуры
+	climateService, err := climate.NewService(cfg.Climate, repo, deviceManager, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create climate control: %v", err)
+	}
+	climateService.Start()
+	defer climateService.Stop()
+
 	// Создание и запуск API сервера
-	apiServer := api.NewServer(repo, logger, cfg.API)
+	apiServer := api.NewServer(repo, deviceManager, engine, climateService, logger, cfg.API)
+
+	// Датчики устройств, отправляющих показания в API, заносятся в реестр
+	if err := apiServer.RegisterIngestSensors(); err != nil {
+		logger.Fatalf("Failed to register ingest sensors: %v", err)
+	}
+	if len(cfg.API.Ingest.Devices) > 0 {
+		logger.Printf("Accepting readings from %d devices", len(cfg.API.Ingest.Devices))
+
+		// Показания устройств калибруются так же, как в сборщике
+		if len(cfg.Processing) > 0 {
+			processor, err := processing.New(cfg.Processing, cfg.SensorTypes())
+			if err != nil {
+				logger.Fatalf("Failed to create reading processing: %v", err)
+			}
+			apiServer.SetProcessor(processor)
+		}
+	}
 
 	// Запускаем сервер в отдельной горутине
 	go func() {
@@ -10,6 +10,7 @@ import (
 	"github.com/4Amangel1/smart-house-automate/internal/bot"
 	"github.com/4Amangel1/smart-house-automate/internal/config"
 	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/report"
 )
 
 func main() {
@@ -49,12 +50,28 @@ func main() {
 		tgBot.StartNotificationService()
 	}()
 
+	var mailer report.Mailer
+	if cfg.SMTP.Enabled() {
+		mailer = report.NewSMTPMailer(cfg.SMTP)
+	}
+
+	reportScheduler, err := report.NewScheduler(cfg.Reports, repo, tgBot, mailer, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create report scheduler: %v", err)
+	}
+
+	go func() {
+		logger.Println("Starting report scheduler...")
+		reportScheduler.Start()
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	sig := <-quit
 	logger.Printf("Received signal %s, shutting down Telegram bot...", sig)
 
+	reportScheduler.Stop()
 	tgBot.Stop()
 
 	time.Sleep(1 * time.Second)
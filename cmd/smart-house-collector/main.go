@@ -5,11 +5,17 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/4Amangel1/smart-house-automate/internal/collector"
 	"github.com/4Amangel1/smart-house-automate/internal/config"
 	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/occupancy"
 	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/factory"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
+	"github.com/4Amangel1/smart-house-automate/internal/remote"
 )
 
 func main() {
@@ -28,20 +34,63 @@ func main() {
 	defer repo.Close()
 	logger.Println("Connected to database")
 
-	sensorFactory, err := factory.New(cfg.Sensors)
+	// Датчики эмулятора: в процессе сборщика или в отдельном процессе эмулятора
+	receiver := remote.NewReceiver(cfg.Collector.RemoteToken)
+	var allSensors []models.Sensor
+	if cfg.Collector.Sensors == "remote" {
+		infos, err := discoverSensors(cfg.Collector.EmulatorURL, logger)
+		if err != nil {
+			logger.Fatalf("Failed to discover emulator sensors: %v", err)
+		}
+		allSensors = remote.FromDiscovery(infos, cfg.Collector.RemoteMode, receiver)
+		logger.Printf("Using %d sensors of emulator %s (%s)", len(allSensors), cfg.Collector.EmulatorURL, cfg.Collector.RemoteMode)
+	} else {
+		local, stop := localSensors(cfg, repo, logger)
+		defer stop()
+		allSensors = local
+	}
+
+	// Сетевые датчики из конфигурации
+	remoteSensors, err := remote.NewSensors(cfg.Sensors.Remote, receiver)
 	if err != nil {
-		logger.Fatalf("Failed to create sensor factory: %v", err)
+		logger.Fatalf("Failed to create remote sensors: %v", err)
 	}
+	allSensors = append(allSensors, remoteSensors...)
 
-	allSensors := sensorFactory.GetAllSensors()
-	if len(allSensors) == 0 {
+	if len(allSensors) == 0 && !cfg.MQTT.Enabled() && !cfg.Collector.LineProtocol {
 		logger.Fatal("No sensors configured")
 	}
 	logger.Printf("Initialized %d sensors", len(allSensors))
 
 	dataCollector := collector.New(allSensors, repo, logger)
+	dataCollector.Handle(remote.ReadingsPath, receiver)
+
+	// Калибровка и сглаживание показаний перед сохранением
+	if len(cfg.Processing) > 0 {
+		processor, err := processing.New(cfg.Processing, cfg.SensorTypes())
+		if err != nil {
+			logger.Fatalf("Failed to create reading processing: %v", err)
+		}
+		dataCollector.SetProcessor(processor)
+		logger.Printf("Processing readings of %d sensors", processor.Len())
+	}
+
+	// Точки InfluxDB line protocol от Telegraf
+	if cfg.Collector.LineProtocol {
+		handler := collector.NewLineProtocolHandler(dataCollector, cfg.Collector.LineProtocolTag, cfg.Collector.LineProtocolToken, logger)
+		dataCollector.Handle(collector.LineProtocolPath, handler)
+		dataCollector.Handle(collector.LineProtocolV2Path, handler)
+		logger.Printf("Accepting line protocol on %s and %s", collector.LineProtocolPath, collector.LineProtocolV2Path)
+	}
 	dataCollector.Start()
 
+	// Показания готовых устройств из MQTT (Zigbee2MQTT, Tasmota, ESPHome)
+	if cfg.MQTT.Enabled() {
+		mqttSource := collector.NewMQTTSource(cfg.MQTT, dataCollector, logger)
+		mqttSource.Start()
+		defer mqttSource.Stop()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -52,3 +101,64 @@ func main() {
 
 	logger.Println("Collector shutdown complete")
 }
+
+// localSensors создает эмулируемые датчики в процессе сборщика; stop
+// останавливает модели эмулятора
+func localSensors(cfg *config.Config, repo *database.Repository, logger *log.Logger) ([]models.Sensor, func()) {
+	stop := func() {}
+
+	// Модель присутствия жильцов
+	var people *occupancy.Simulation
+	if len(cfg.Occupancy.People) > 0 {
+		var err error
+		people, err = occupancy.New(cfg.Occupancy, nil, cfg.Emulator.Seed)
+		if err != nil {
+			logger.Fatalf("Failed to create occupancy model: %v", err)
+		}
+		logger.Printf("Simulating %d occupants", len(cfg.Occupancy.People))
+	}
+
+	// Физическая модель комнат: состояния устройств берутся из БД,
+	// куда их записывает API
+	var env *environment.Environment
+	if len(cfg.Environment.Rooms) > 0 {
+		var err error
+		env, err = environment.New(cfg.Environment, environment.NewRepositoryStates(repo), nil, logger)
+		if err != nil {
+			logger.Fatalf("Failed to create environment model: %v", err)
+		}
+		if people != nil {
+			env.UseOccupancy(people)
+		}
+		env.Start()
+		stop = env.Stop
+	}
+
+	sensorFactory, err := factory.NewWithOptions(cfg.Sensors, factory.Options{
+		Environment: env,
+		Occupancy:   people,
+		Seed:        cfg.Emulator.Seed,
+		Readings:    repo,
+	})
+	if err != nil {
+		logger.Fatalf("Failed to create sensor factory: %v", err)
+	}
+
+	return sensorFactory.GetAllSensors(), stop
+}
+
+// discoverSensors запрашивает список датчиков эмулятора, дожидаясь его запуска
+func discoverSensors(url string, logger *log.Logger) ([]remote.SensorInfo, error) {
+	const attempts = 10
+
+	var err error
+	for i := 1; i <= attempts; i++ {
+		var infos []remote.SensorInfo
+		if infos, err = remote.Discover(url, 0); err == nil {
+			return infos, nil
+		}
+		logger.Printf("Emulator is not available (attempt %d/%d): %v", i, attempts, err)
+		time.Sleep(3 * time.Second)
+	}
+	return nil, err
+}
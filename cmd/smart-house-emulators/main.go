@@ -1,16 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	actuatorfactory "github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/factory"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/batch"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/occupancy"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/publisher"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/replay"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/scenario"
 	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/factory"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/server"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/remote"
 )
 
 func main() {
@@ -22,8 +37,106 @@ func main() {
 		logger.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Модельные часы: ручные в пакетном режиме, ускоренные или реальные иначе
+	emu := cfg.Emulator
+	var (
+		clock       sim.Clock
+		manualClock *sim.ManualClock
+		batchFrom   time.Time
+	)
+	switch {
+	case emu.BatchDays > 0:
+		batchFrom = emu.Start
+		if batchFrom.IsZero() {
+			batchFrom = time.Now().UTC().Truncate(time.Second).AddDate(0, 0, -emu.BatchDays)
+		}
+		manualClock = sim.NewManualClock(batchFrom)
+		clock = manualClock
+		logger.Printf("Batch mode: generating %d days of readings from %s", emu.BatchDays, batchFrom.Format(time.RFC3339))
+	case emu.Speed != 1 || !emu.Start.IsZero():
+		clock = sim.NewScaledClock(emu.Start, emu.Speed)
+		logger.Printf("Simulated time: x%g starting at %s", emu.Speed, clock.Now().Format(time.RFC3339))
+	}
+	if emu.Seed != 0 {
+		logger.Printf("Using random seed %d", emu.Seed)
+	}
+
+	// Модель присутствия жильцов
+	var people *occupancy.Simulation
+	if len(cfg.Occupancy.People) > 0 {
+		people, err = occupancy.New(cfg.Occupancy, clock, emu.Seed)
+		if err != nil {
+			logger.Fatalf("Failed to create occupancy model: %v", err)
+		}
+		logger.Printf("Simulating %d occupants", len(cfg.Occupancy.People))
+	}
+
+	// Устройства этого процесса: их учитывает модель комнат, им адресованы шаги сценария
+	deviceFactory, err := actuatorfactory.New(cfg.Devices)
+	if err != nil {
+		logger.Fatalf("Failed to create device factory: %v", err)
+	}
+
+	// База данных нужна для воспроизведения показаний за период и для
+	// состояний устройств, которыми управляют другие сервисы
+	var repo *database.Repository
+	openRepository := func() *database.Repository {
+		if repo == nil {
+			var err error
+			if repo, err = database.NewRepository(cfg.Database.ConnectionString()); err != nil {
+				logger.Fatalf("Failed to connect to database: %v", err)
+			}
+		}
+		return repo
+	}
+	defer func() {
+		if repo != nil {
+			repo.Close()
+		}
+	}()
+
+	// Физическая модель комнат учитывает устройства, созданные в этом
+	// процессе (им адресованы шаги сценария), а при EMULATOR_DEVICE_STATES=db
+	// и команды, которые API, автоматизация и климат сохраняют в БД: в
+	// развертывании с отдельным эмулятором устройствами управляет API
+	var states environment.DeviceStates = environment.ActuatorStates(deviceFactory.GetAllDevices())
+	if emu.DeviceStates == "db" {
+		states = environment.OverlayStates{states, environment.NewRepositoryStates(openRepository())}
+		logger.Println("Reading device states from the database")
+	}
+
+	var env *environment.Environment
+	if len(cfg.Environment.Rooms) > 0 {
+		env, err = environment.New(cfg.Environment, states, clock, logger)
+		if err != nil {
+			logger.Fatalf("Failed to create environment model: %v", err)
+		}
+		if people != nil {
+			env.UseOccupancy(people)
+		}
+		// В пакетном режиме модель продвигается перед каждым опросом
+		if manualClock == nil {
+			env.Start()
+			defer env.Stop()
+		}
+	}
+
+	var readings replay.ReadingSource
+	for _, c := range cfg.Sensors.Replay {
+		if c.File == "" {
+			readings = openRepository()
+			break
+		}
+	}
+
 	// Создаем фабрику датчиков
-	sensorFactory, err := factory.New(cfg.Sensors)
+	sensorFactory, err := factory.NewWithOptions(cfg.Sensors, factory.Options{
+		Environment: env,
+		Occupancy:   people,
+		Clock:       clock,
+		Seed:        emu.Seed,
+		Readings:    readings,
+	})
 	if err != nil {
 		logger.Fatalf("Failed to create sensor factory: %v", err)
 	}
@@ -37,6 +150,77 @@ func main() {
 
 	logger.Printf("Initialized %d sensors", len(allSensors))
 
+	// Сценарий подменяет показания датчиков по таймлайну
+	var player *scenario.Player
+	if emu.Scenario != "" {
+		sc, err := scenario.Load(emu.Scenario)
+		if err != nil {
+			logger.Fatalf("Failed to load scenario: %v", err)
+		}
+		player, err = scenario.NewPlayer(sc, clock, allSensors, deviceFactory.GetAllDevices(), logger)
+		if err != nil {
+			logger.Fatalf("Failed to load scenario: %v", err)
+		}
+		allSensors = player.Wrap(allSensors)
+	}
+
+	if manualClock != nil {
+		// Модели продвигаются перед каждым опросом
+		var hooks []func(time.Time)
+		if env != nil {
+			env.Reset(batchFrom)
+			hooks = append(hooks, env.AdvanceTo)
+		}
+		if player != nil {
+			player.Begin(batchFrom)
+			hooks = append(hooks, func(now time.Time) { player.AdvanceTo(now) })
+		}
+		before := func(now time.Time) {
+			for _, hook := range hooks {
+				hook(now)
+			}
+		}
+
+		if err := runBatch(allSensors, manualClock, batchFrom, before, emu, cfg.Database, logger); err != nil {
+			logger.Fatalf("Batch generation failed: %v", err)
+		}
+		return
+	}
+
+	if player != nil {
+		player.Start()
+		defer player.Stop()
+	}
+
+	// При ускоренном времени датчики опрашиваются чаще в реальном времени
+	realInterval := func(d time.Duration) time.Duration { return d }
+	if scaled, ok := clock.(*sim.ScaledClock); ok {
+		realInterval = scaled.RealDuration
+	}
+
+	// Датчики доступны по сети: сборщик опрашивает последние показания
+	// или получает их от эмулятора (EMULATOR_PUSH_URL)
+	var sensorServer *server.Server
+	if emu.Listen != "" {
+		sensorServer = server.New(emu.Listen, allSensors, logger)
+		sensorServer.Start()
+	}
+	var pusher *remote.Pusher
+	if emu.PushURL != "" {
+		pusher = remote.NewPusher(emu.PushURL, emu.PushToken, 0)
+		logger.Printf("Pushing readings to %s", emu.PushURL)
+	}
+
+	// Показания публикуются в MQTT в формате Zigbee2MQTT или Tasmota
+	var mqttPublisher *publisher.Publisher
+	if emu.MQTT.Enabled() {
+		mqttPublisher, err = publisher.New(emu.MQTT, allSensors, logger)
+		if err != nil {
+			logger.Fatalf("Failed to create MQTT publisher: %v", err)
+		}
+		mqttPublisher.Start()
+	}
+
 	done := make(chan struct{})
 	var wg sync.WaitGroup
 
@@ -45,15 +229,28 @@ func main() {
 		go func(s models.Sensor) {
 			defer wg.Done()
 
-			ticker := time.NewTicker(s.Interval())
+			ticker := time.NewTicker(realInterval(s.Interval()))
 			defer ticker.Stop()
 
 			for {
 				select {
 				case <-ticker.C:
 					reading, err := s.Read()
+					if sensorServer != nil {
+						sensorServer.Record(s.ID(), reading, err)
+					}
+					if pusher != nil && !errors.Is(err, models.ErrNotReady) {
+						if pushErr := pusher.Push(server.ToRemote(s, reading, err)); pushErr != nil {
+							logger.Printf("Failed to push reading of sensor %s: %v", s.ID(), pushErr)
+						}
+					}
+					if mqttPublisher != nil {
+						if pubErr := mqttPublisher.Publish(s.ID(), reading, err); pubErr != nil {
+							logger.Printf("Failed to publish reading of sensor %s: %v", s.ID(), pubErr)
+						}
+					}
 					if err != nil {
-						if err != models.ErrNotReady {
+						if !errors.Is(err, models.ErrNotReady) {
 							logger.Printf("Sensor %s error: %v", s.ID(), err)
 						}
 						continue
@@ -76,5 +273,58 @@ func main() {
 	close(done)
 
 	wg.Wait()
+	if mqttPublisher != nil {
+		mqttPublisher.Stop()
+	}
+	if sensorServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := sensorServer.Stop(ctx); err != nil {
+			logger.Printf("Error stopping sensor server: %v", err)
+		}
+	}
 	logger.Println("All sensors stopped. Shutting down.")
 }
+
+// runBatch генерирует показания за emu.BatchDays дней и записывает их в файл
+// NDJSON или в базу данных (emu.Output = "db")
+func runBatch(sensors []models.Sensor, clock *sim.ManualClock, from time.Time, before func(time.Time), emu config.EmulatorConfig, dbCfg config.DatabaseConfig, logger *log.Logger) error {
+	// Порядок опроса не должен зависеть от порядка обхода карты в фабрике
+	sort.Slice(sensors, func(i, j int) bool { return sensors[i].ID() < sensors[j].ID() })
+
+	generator := batch.New(sensors, clock)
+	generator.Before = before
+	to := from.AddDate(0, 0, emu.BatchDays)
+
+	if emu.Output == "db" {
+		repo, err := database.NewRepository(dbCfg.ConnectionString())
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer repo.Close()
+
+		result, err := generator.Run(from, to, batch.NewRepositorySink(repo))
+		if err != nil {
+			return err
+		}
+		logger.Printf("Saved %d readings to database (%d failed reads)", result.Written, result.Failed)
+		return nil
+	}
+
+	f, err := os.Create(emu.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	sink := batch.NewNDJSONSink(f)
+	result, err := generator.Run(from, to, sink)
+	if err != nil {
+		return err
+	}
+	if err := sink.Flush(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	logger.Printf("Wrote %d readings to %s (%d failed reads)", result.Written, emu.Output, result.Failed)
+	return nil
+}
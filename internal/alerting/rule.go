@@ -0,0 +1,156 @@
+// Package alerting разбирает, проверяет и вычисляет правила оповещений
+// вида "temp_kitchen > 27 for 5m"
+package alerting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+)
+
+// Операторы сравнения
+var operators = map[string]func(value, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"=":  func(v, t float64) bool { return v == t },
+}
+
+// Parse разбирает правило "sensor_id [field] op threshold [for duration]".
+// Для датчиков движения порог задается как true/false
+func Parse(s string) (models.AlertRule, error) {
+	fields := strings.Fields(s)
+
+	var rule models.AlertRule
+	if len(fields) >= 2 && fields[len(fields)-2] == "for" {
+		d, err := time.ParseDuration(fields[len(fields)-1])
+		if err != nil || d < 0 {
+			return rule, fmt.Errorf("invalid duration %q", fields[len(fields)-1])
+		}
+		rule.Duration = d
+		fields = fields[:len(fields)-2]
+	}
+
+	switch len(fields) {
+	case 3:
+		rule.SensorID, rule.Operator = fields[0], fields[1]
+	case 4:
+		rule.SensorID, rule.Field, rule.Operator = fields[0], strings.ToLower(fields[1]), fields[2]
+	default:
+		return rule, fmt.Errorf("expected \"sensor_id [field] op value [for duration]\"")
+	}
+
+	if rule.Operator == "==" {
+		rule.Operator = "="
+	}
+	if _, ok := operators[rule.Operator]; !ok {
+		return rule, fmt.Errorf("unknown operator %q", rule.Operator)
+	}
+
+	threshold, err := parseThreshold(fields[len(fields)-1])
+	if err != nil {
+		return rule, err
+	}
+	rule.Threshold = threshold
+
+	return rule, nil
+}
+
+func parseThreshold(s string) (float64, error) {
+	switch strings.ToLower(s) {
+	case "true", "on", "yes":
+		return 1, nil
+	case "false", "off", "no":
+		return 0, nil
+	}
+
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid threshold %q", s)
+	}
+	return v, nil
+}
+
+// Validate проверяет, что правило применимо к датчику указанного типа
+func Validate(rule models.AlertRule, sensorType string) error {
+	// Поля значения, доступные в правилах, задает схема типа датчика
+	t, ok := sensortype.Lookup(sensorType)
+	if !ok {
+		return fmt.Errorf("alerts are not supported for sensor type %q", sensorType)
+	}
+	allowed := t.Value.FieldNames()
+
+	fieldOK := false
+	for _, f := range allowed {
+		if f == rule.Field {
+			fieldOK = true
+		}
+	}
+	if !fieldOK {
+		if allowed[0] == "" {
+			return fmt.Errorf("sensor type %q has no field %q", sensorType, rule.Field)
+		}
+		return fmt.Errorf("sensor type %q requires one of fields: %s", sensorType, strings.Join(allowed, ", "))
+	}
+
+	if _, ok := operators[rule.Operator]; !ok {
+		return fmt.Errorf("unknown operator %q", rule.Operator)
+	}
+
+	if t.Value.Kind == models.KindBool {
+		if rule.Operator != "=" {
+			return fmt.Errorf("%s rules support only the = operator", sensorType)
+		}
+		if rule.Threshold != 0 && rule.Threshold != 1 {
+			return fmt.Errorf("%s rules require true or false", sensorType)
+		}
+	}
+
+	if rule.Duration < 0 {
+		return fmt.Errorf("duration cannot be negative")
+	}
+
+	return nil
+}
+
+// Value извлекает из показания значение поля правила
+func Value(reading models.SensorData, field string) (float64, bool) {
+	value, err := reading.Value.Decode()
+	if err != nil {
+		return 0, false
+	}
+	return value.Field(field)
+}
+
+// Matches проверяет условие правила для одного показания
+func Matches(rule models.AlertRule, reading models.SensorData) bool {
+	value, ok := Value(reading, rule.Field)
+	if !ok {
+		return false
+	}
+	compare, ok := operators[rule.Operator]
+	return ok && compare(value, rule.Threshold)
+}
+
+// Holds проверяет, что условие выполняется непрерывно не меньше rule.Duration
+// к моменту now: все показания от самого нового до показания не позже
+// now-rule.Duration удовлетворяют условию. Показания - в хронологическом
+// порядке. После пропуска в данных одного подходящего показания мало: правило
+// сработает, только когда условие продержится всю длительность
+func Holds(rule models.AlertRule, readings []models.SensorData, now time.Time) bool {
+	since := now.Add(-rule.Duration)
+	for i := len(readings) - 1; i >= 0; i-- {
+		if !Matches(rule, readings[i]) {
+			return false
+		}
+		if !readings[i].Timestamp.After(since) {
+			return true
+		}
+	}
+	return false
+}
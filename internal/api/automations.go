@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/4Amangel1/smart-house-automate/internal/automation"
+	"github.com/gin-gonic/gin"
+)
+
+// automationResponse - представление правила автоматизации в API
+type automationResponse struct {
+	automation.Rule
+	Enabled  bool   `json:"enabled"`
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+func (s *Server) getAutomations(c *gin.Context) {
+	rules := s.automation.Rules()
+
+	response := make([]automationResponse, 0, len(rules))
+	for _, rule := range rules {
+		item := automationResponse{Rule: rule, Enabled: rule.IsEnabled()}
+		if rule.Cooldown > 0 {
+			item.Cooldown = rule.Cooldown.String()
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dryRun":      s.automation.DryRun(),
+		"automations": response,
+	})
+}
+
+func (s *Server) getAutomationRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	runs, err := s.repo.GetAutomationRuns(c.Query("rule"), limit)
+	if err != nil {
+		s.logger.Printf("Error getting automation runs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get automation runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+func (s *Server) runAutomation(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run"})
+		return
+	}
+
+	run, err := s.automation.RunNow(c.Param("id"), dryRun)
+	if errors.Is(err, automation.ErrRuleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Automation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
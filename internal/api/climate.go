@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) getClimate(c *gin.Context) {
+	c.JSON(http.StatusOK, s.climate.Status())
+}
+
+func (s *Server) getClimateRoom(c *gin.Context) {
+	status, err := s.climate.RoomStatus(c.Param("room"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/4Amangel1/smart-house-automate/internal/devices"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// deviceResponse - представление устройства в API
+type deviceResponse struct {
+	ID           string               `json:"id"`
+	Type         string               `json:"type"`
+	Capabilities []string             `json:"capabilities"`
+	State        models.ActuatorState `json:"state"`
+}
+
+func newDeviceResponse(d models.Actuator) deviceResponse {
+	return deviceResponse{
+		ID:           d.ID(),
+		Type:         d.Type(),
+		Capabilities: d.Capabilities(),
+		State:        d.State(),
+	}
+}
+
+func (s *Server) getDevices(c *gin.Context) {
+	list := s.devices.List()
+
+	response := make([]deviceResponse, 0, len(list))
+	for _, d := range list {
+		response = append(response, newDeviceResponse(d))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *Server) getDevice(c *gin.Context) {
+	d, err := s.devices.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newDeviceResponse(d))
+}
+
+func (s *Server) executeDeviceCommand(c *gin.Context) {
+	deviceID := c.Param("id")
+
+	var cmd models.Command
+	if err := c.ShouldBindJSON(&cmd); err != nil || cmd.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid command"})
+		return
+	}
+
+	state, err := s.devices.Execute(deviceID, cmd, devices.SourceAPI)
+	switch {
+	case errors.Is(err, devices.ErrDeviceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	case errors.Is(err, models.ErrUnsupportedCommand), errors.Is(err, models.ErrInvalidCommandValue):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.logger.Printf("Error executing command %s on device %s: %v", cmd.Name, deviceID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to execute command"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": deviceID, "state": state})
+}
+
+func (s *Server) getDeviceEvents(c *gin.Context) {
+	deviceID := c.Param("id")
+	if _, err := s.devices.Get(deviceID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	events, err := s.repo.GetDeviceEvents(deviceID, limit)
+	if err != nil {
+		s.logger.Printf("Error getting events of device %s: %v", deviceID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get device events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
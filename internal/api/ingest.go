@@ -0,0 +1,322 @@
+package api
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+	"github.com/gin-gonic/gin"
+)
+
+// Ограничения приема показаний
+const (
+	defaultIngestRate  = 1  // Показаний в секунду на устройство
+	defaultIngestBurst = 10 // Показаний в одном запросе или серии запросов
+	maxIngestBody      = 1 << 20
+	maxClockSkew       = 5 * time.Minute // Насколько показание может опережать часы сервера
+)
+
+// ingestReading - показание, отправленное устройством
+type ingestReading struct {
+	SensorID   string                 `json:"sensorId"`
+	SensorType string                 `json:"sensorType,omitempty"`
+	Timestamp  time.Time              `json:"timestamp,omitempty"`
+	Value      interface{}            `json:"value"`
+	Unit       string                 `json:"unit,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ingestDevice - устройство, которому разрешено отправлять показания
+type ingestDevice struct {
+	id      string
+	token   []byte
+	sensors map[string]bool
+	limiter *rateLimiter
+}
+
+// ingestDevices находит устройство по токену
+type ingestDevices struct {
+	devices []*ingestDevice
+}
+
+func newIngestDevices(cfg config.IngestConfig) *ingestDevices {
+	rate := cfg.RateLimit
+	if rate == 0 {
+		rate = defaultIngestRate
+	}
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = defaultIngestBurst
+	}
+
+	d := &ingestDevices{}
+	for _, device := range cfg.Devices {
+		sensors := make(map[string]bool, len(device.Sensors))
+		for _, sensor := range device.Sensors {
+			sensors[sensor.ID] = true
+		}
+		d.devices = append(d.devices, &ingestDevice{
+			id:      device.ID,
+			token:   []byte(device.Token),
+			sensors: sensors,
+			limiter: newRateLimiter(rate, burst),
+		})
+	}
+	return d
+}
+
+// authenticate возвращает устройство по токену из заголовка
+// Authorization: Bearer <token> или X-Device-Token
+func (d *ingestDevices) authenticate(r *http.Request) *ingestDevice {
+	token := r.Header.Get("X-Device-Token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return nil
+	}
+
+	for _, device := range d.devices {
+		if subtle.ConstantTimeCompare(device.token, []byte(token)) == 1 {
+			return device
+		}
+	}
+	return nil
+}
+
+// RegisterIngestSensors заносит датчики устройств в реестр датчиков
+func (s *Server) RegisterIngestSensors() error {
+	for _, device := range s.config.Ingest.Devices {
+		for _, sensor := range device.Sensors {
+			err := s.repo.SaveSensor(models.SensorInfo{
+				ID:       sensor.ID,
+				Type:     sensor.Type,
+				Name:     sensor.Name,
+				Location: sensor.Location,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to register sensor %s of device %s: %w", sensor.ID, device.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ingestReadings принимает одно показание или массив показаний от устройства.
+// Пакет сохраняется целиком в одной транзакции и только если все показания
+// прошли проверку
+func (s *Server) ingestReadings(c *gin.Context) {
+	device := s.ingest.authenticate(c.Request)
+	if device == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid device token"})
+		return
+	}
+
+	readings, err := decodeIngestReadings(c.Request.Body)
+	if err != nil {
+		ingestedReadings.WithLabelValues(device.id, "invalid").Inc()
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(readings) > int(device.limiter.burst) {
+		ingestedReadings.WithLabelValues(device.id, "rate_limited").Add(float64(len(readings)))
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Too many readings in one request, max %d", int(device.limiter.burst))})
+		return
+	}
+	if wait := device.limiter.take(len(readings)); wait > 0 {
+		ingestedReadings.WithLabelValues(device.id, "rate_limited").Add(float64(len(readings)))
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+		return
+	}
+
+	// Реестр читается один раз на пакет, а не на каждое показание
+	ids := make([]string, 0, len(readings))
+	seen := make(map[string]bool, len(readings))
+	for _, reading := range readings {
+		if reading.SensorID != "" && device.sensors[reading.SensorID] && !seen[reading.SensorID] {
+			seen[reading.SensorID] = true
+			ids = append(ids, reading.SensorID)
+		}
+	}
+	sensors, err := s.repo.GetSensors(ids)
+	if err != nil {
+		s.logger.Printf("Error loading sensors for device %s: %v", device.id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate readings"})
+		return
+	}
+
+	now := time.Now().UTC()
+	data := make([]models.SensorData, 0, len(readings))
+	var problems []string
+	for i, reading := range readings {
+		item, err := validateIngestReading(device, sensors, reading, now)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("reading %d: %v", i, err))
+			continue
+		}
+		data = append(data, item)
+	}
+	if len(problems) > 0 {
+		ingestedReadings.WithLabelValues(device.id, "invalid").Add(float64(len(readings)))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid readings", "details": problems})
+		return
+	}
+
+	// Обработка идет после проверки всего пакета, а состояние сглаживания
+	// фиксируется только после сохранения: отклоненный или не сохраненный
+	// пакет его не меняет. Выбросы пропускаются, остальное сохраняется
+	var batch *processing.Batch
+	if s.processor != nil {
+		batch = s.processor.Batch()
+	}
+	accepted := make([]models.SensorData, 0, len(data))
+	for _, item := range data {
+		if batch != nil {
+			processed, err := batch.Process(item)
+			if errors.Is(err, processing.ErrRejected) {
+				ingestedReadings.WithLabelValues(device.id, "rejected").Inc()
+				continue
+			}
+			if err != nil {
+				ingestedReadings.WithLabelValues(device.id, "invalid").Inc()
+				s.logger.Printf("Error processing reading of sensor %s from device %s: %v", item.SensorID, device.id, err)
+				continue
+			}
+			item = processed
+		}
+		accepted = append(accepted, item)
+	}
+
+	// Пакет сохраняется в одной транзакции, чтобы повтор запроса устройством
+	// после ошибки не создавал дубликатов
+	if err := s.repo.SaveReadings(accepted); err != nil {
+		ingestedReadings.WithLabelValues(device.id, "error").Add(float64(len(accepted)))
+		s.logger.Printf("Error saving readings from device %s: %v", device.id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save readings"})
+		return
+	}
+	if batch != nil {
+		batch.Commit()
+	}
+	ingestedReadings.WithLabelValues(device.id, "ok").Add(float64(len(accepted)))
+
+	c.JSON(http.StatusCreated, gin.H{"accepted": len(accepted)})
+}
+
+// decodeIngestReadings разбирает тело запроса: объект или массив объектов
+func decodeIngestReadings(body io.Reader) ([]ingestReading, error) {
+	raw, err := io.ReadAll(io.LimitReader(body, maxIngestBody))
+	if err != nil {
+		return nil, errors.New("failed to read body")
+	}
+
+	var readings []ingestReading
+	if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '[' {
+		err = json.Unmarshal(raw, &readings)
+	} else {
+		var reading ingestReading
+		err = json.Unmarshal(raw, &reading)
+		readings = []ingestReading{reading}
+	}
+	if err != nil {
+		return nil, errors.New("invalid JSON")
+	}
+	if len(readings) == 0 {
+		return nil, errors.New("no readings")
+	}
+	return readings, nil
+}
+
+// validateIngestReading проверяет показание по датчикам реестра, загруженным
+// для пакета, и готовит его к сохранению
+func validateIngestReading(device *ingestDevice, sensors map[string]*models.SensorInfo, reading ingestReading, now time.Time) (models.SensorData, error) {
+	if reading.SensorID == "" {
+		return models.SensorData{}, errors.New("sensorId is required")
+	}
+	if !device.sensors[reading.SensorID] {
+		return models.SensorData{}, fmt.Errorf("sensor %s does not belong to the device", reading.SensorID)
+	}
+
+	sensor, ok := sensors[reading.SensorID]
+	if !ok {
+		return models.SensorData{}, fmt.Errorf("sensor %s is not registered", reading.SensorID)
+	}
+	if reading.SensorType != "" && reading.SensorType != sensor.Type {
+		return models.SensorData{}, fmt.Errorf("sensor %s has type %s, not %s", sensor.ID, sensor.Type, reading.SensorType)
+	}
+
+	timestamp := reading.Timestamp.UTC()
+	if reading.Timestamp.IsZero() {
+		timestamp = now
+	}
+	if timestamp.After(now.Add(maxClockSkew)) {
+		return models.SensorData{}, errors.New("timestamp is in the future")
+	}
+
+	metadata := make(map[string]interface{}, len(reading.Metadata)+2)
+	for key, value := range reading.Metadata {
+		metadata[key] = value
+	}
+	metadata["source"] = "ingest"
+	metadata["device"] = device.id
+
+	// Значение проверяется по схеме типа датчика и переводится в его
+	// каноническую единицу
+	return sensortype.Normalize(models.SensorData{
+		SensorID:   sensor.ID,
+		SensorType: sensor.Type,
+		Timestamp:  timestamp,
+		Value:      models.SensorValue{Data: reading.Value},
+		Unit:       reading.Unit,
+		Metadata:   models.Metadata{Data: metadata},
+	})
+}
+
+// rateLimiter - ограничитель частоты по алгоритму token bucket
+type rateLimiter struct {
+	rate  float64 // Пополнение в секунду
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// take списывает n токенов (n не больше burst) и возвращает 0 или время
+// ожидания, если токенов не хватает
+func (l *rateLimiter) take(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	need := float64(n)
+	if l.tokens < need {
+		return time.Duration((need - l.tokens) / l.rate * float64(time.Second))
+	}
+	l.tokens -= need
+	return 0
+}
@@ -26,6 +26,14 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	ingestedReadings = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_ingested_readings_total",
+			Help: "Количество показаний, отправленных устройствами (ok, invalid, rejected, rate_limited, error)",
+		},
+		[]string{"device", "result"},
+	)
 )
 
 // Добавьте middleware для подсчета метрик
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
@@ -8,8 +9,16 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/4Amangel1/smart-house-automate/internal/automation"
+	"github.com/4Amangel1/smart-house-automate/internal/chart"
+	"github.com/4Amangel1/smart-house-automate/internal/climate"
 	"github.com/4Amangel1/smart-house-automate/internal/config"
 	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/devices"
+	"github.com/4Amangel1/smart-house-automate/internal/lineprotocol"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -18,12 +27,17 @@ import (
 type Server struct {
 	router     *gin.Engine
 	repo       *database.Repository
+	devices    *devices.Manager
+	automation *automation.Engine
+	climate    *climate.Service
 	logger     *log.Logger
 	httpServer *http.Server
 	config     config.APIConfig
+	ingest     *ingestDevices
+	processor  *processing.Processor
 }
 
-func NewServer(repo *database.Repository, logger *log.Logger, cfg config.APIConfig) *Server {
+func NewServer(repo *database.Repository, deviceManager *devices.Manager, engine *automation.Engine, climateService *climate.Service, logger *log.Logger, cfg config.APIConfig) *Server {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
@@ -45,10 +59,14 @@ func NewServer(repo *database.Repository, logger *log.Logger, cfg config.APIConf
 	}))
 
 	server := &Server{
-		router: r,
-		repo:   repo,
-		logger: logger,
-		config: cfg,
+		router:     r,
+		repo:       repo,
+		devices:    deviceManager,
+		automation: engine,
+		climate:    climateService,
+		logger:     logger,
+		config:     cfg,
+		ingest:     newIngestDevices(cfg.Ingest),
 	}
 
 	server.httpServer = &http.Server{
@@ -64,6 +82,14 @@ func NewServer(repo *database.Repository, logger *log.Logger, cfg config.APIConf
 	return server
 }
 
+// SetProcessor задает обработку (калибровку и сглаживание) показаний,
+// принятых от устройств. Вызывается до Run. У API свой обработчик: состояние
+// сглаживания не общее со сборщиком, поэтому датчик с конвейером должен
+// присылать показания только одним путем - через сборщик или через /ingest
+func (s *Server) SetProcessor(p *processing.Processor) {
+	s.processor = p
+}
+
 func (s *Server) setupRoutes() {
 	s.router.Use(metricsMiddleware())
 
@@ -72,9 +98,23 @@ func (s *Server) setupRoutes() {
 	api := s.router.Group("/api/v1")
 	{
 		api.GET("/sensors", s.getAllSensors)
+		api.GET("/sensors/:id/chart.png", s.getSensorChart)
 		api.GET("/readings/latest", s.getLatestReadings)
 		api.GET("/readings/latest/:type", s.getLatestReadingsByType)
 		api.GET("/readings/history", s.getReadingHistory)
+		api.POST("/ingest", s.ingestReadings)
+
+		api.GET("/devices", s.getDevices)
+		api.GET("/devices/:id", s.getDevice)
+		api.GET("/devices/:id/events", s.getDeviceEvents)
+		api.POST("/devices/:id/commands", s.executeDeviceCommand)
+
+		api.GET("/automations", s.getAutomations)
+		api.GET("/automations/runs", s.getAutomationRuns)
+		api.POST("/automations/:id/run", s.runAutomation)
+
+		api.GET("/climate", s.getClimate)
+		api.GET("/climate/:room", s.getClimateRoom)
 	}
 }
 
@@ -93,6 +133,11 @@ func (s *Server) getAllSensors(c *gin.Context) {
 }
 
 func (s *Server) getLatestReadings(c *gin.Context) {
+	preference, ok := unitsPreference(c)
+	if !ok {
+		return
+	}
+
 	readings, err := s.repo.GetLatestReadings()
 	if err != nil {
 		s.logger.Printf("Error getting latest readings: %v", err)
@@ -100,7 +145,7 @@ func (s *Server) getLatestReadings(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, readings)
+	c.JSON(http.StatusOK, sensortype.ConvertReadings(readings, preference))
 }
 
 func (s *Server) getLatestReadingsByType(c *gin.Context) {
@@ -111,6 +156,11 @@ func (s *Server) getLatestReadingsByType(c *gin.Context) {
 		return
 	}
 
+	preference, ok := unitsPreference(c)
+	if !ok {
+		return
+	}
+
 	readings, err := s.repo.GetLatestReadingsByType(sensorType)
 	if err != nil {
 		s.logger.Printf("Error getting latest readings by type %s: %v", sensorType, err)
@@ -118,7 +168,7 @@ func (s *Server) getLatestReadingsByType(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, readings)
+	c.JSON(http.StatusOK, sensortype.ConvertReadings(readings, preference))
 }
 
 func (s *Server) getReadingHistory(c *gin.Context) {
@@ -135,12 +185,106 @@ func (s *Server) getReadingHistory(c *gin.Context) {
 		return
 	}
 
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "line" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+		return
+	}
+
+	preference, ok := unitsPreference(c)
+	if !ok {
+		return
+	}
+
 	readings, err := s.repo.GetReadingHistory(sensorID, limit)
 	if err != nil {
 		s.logger.Printf("Error getting reading history for sensor %s: %v", sensorID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reading history"})
 		return
 	}
+	readings = sensortype.ConvertReadings(readings, preference)
+
+	if format == "line" {
+		s.writeLineProtocol(c, readings)
+		return
+	}
 
 	c.JSON(http.StatusOK, readings)
 }
+
+// writeLineProtocol отдает показания в формате InfluxDB line protocol, чтобы
+// историю можно было загрузить в Influx (influx write, Telegraf)
+func (s *Server) writeLineProtocol(c *gin.Context, readings []models.SensorData) {
+	var buf bytes.Buffer
+	for _, reading := range readings {
+		point, err := lineprotocol.FromSensorData(reading)
+		if err == nil {
+			err = lineprotocol.Encode(&buf, point)
+		}
+		if err != nil {
+			s.logger.Printf("Skipping reading %d in line protocol export: %v", reading.ID, err)
+		}
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", buf.Bytes())
+}
+
+func (s *Server) getSensorChart(c *gin.Context) {
+	sensorID := c.Param("id")
+
+	period, err := chart.ParsePeriod(c.DefaultQuery("period", "24h"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid period, must be positive and at most %dd", chart.MaxPeriodDays)})
+		return
+	}
+
+	width, err := strconv.Atoi(c.DefaultQuery("width", strconv.Itoa(chart.DefaultWidth)))
+	if err != nil || width <= 0 || width > chart.MaxSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid width, must be between 1 and %d", chart.MaxSize)})
+		return
+	}
+
+	height, err := strconv.Atoi(c.DefaultQuery("height", strconv.Itoa(chart.DefaultHeight)))
+	if err != nil || height <= 0 || height > chart.MaxSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid height, must be between 1 and %d", chart.MaxSize)})
+		return
+	}
+
+	preference, ok := unitsPreference(c)
+	if !ok {
+		return
+	}
+
+	to := time.Now().UTC()
+	from := to.Add(-period)
+
+	readings, err := s.repo.GetReadingsInRange(sensorID, from, to)
+	if err != nil {
+		s.logger.Printf("Error getting readings for chart of sensor %s: %v", sensorID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get readings"})
+		return
+	}
+
+	if len(readings) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No readings for the period"})
+		return
+	}
+
+	readings = sensortype.ConvertReadings(readings, preference)
+	sensorChart, err := chart.FromReadings(sensorID, readings[len(readings)-1].SensorType, readings, from, to)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	sensorChart.Width = width
+	sensorChart.Height = height
+
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, sensorChart); err != nil {
+		s.logger.Printf("Error rendering chart for sensor %s: %v", sensorID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", buf.Bytes())
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+	"github.com/gin-gonic/gin"
+)
+
+// unitsPreference разбирает параметр units - единицы, в которых нужно отдать
+// показания, например units=°F,mg/m³ или units=F. Без параметра показания
+// отдаются в канонических единицах. false - параметр некорректен, ответ уже
+// отправлен
+func unitsPreference(c *gin.Context) (units.Preference, bool) {
+	p, err := units.ParsePreference(c.Query("units"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid units: " + err.Error()})
+		return nil, false
+	}
+	return p, true
+}
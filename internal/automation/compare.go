@@ -0,0 +1,68 @@
+package automation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operators - поддерживаемые операторы сравнения
+var operators = map[string]bool{
+	">": true, ">=": true, "<": true, "<=": true, "=": true, "!=": true,
+}
+
+// compare сравнивает фактическое значение с ожидаемым. Числа и булевы значения
+// сравниваются как числа (true = 1), остальные значения - как строки
+func compare(actual interface{}, operator string, expected interface{}) bool {
+	a, aok := toFloat(actual)
+	e, eok := toFloat(expected)
+
+	if aok && eok {
+		switch operator {
+		case ">":
+			return a > e
+		case ">=":
+			return a >= e
+		case "<":
+			return a < e
+		case "<=":
+			return a <= e
+		case "=":
+			return a == e
+		case "!=":
+			return a != e
+		}
+		return false
+	}
+
+	as, es := fmt.Sprint(actual), fmt.Sprint(expected)
+	switch operator {
+	case "=":
+		return strings.EqualFold(as, es)
+	case "!=":
+		return !strings.EqualFold(as, es)
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case bool:
+		if value {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,376 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/alerting"
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/devices"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// SourceAutomation - источник команд, выполненных правилами автоматизации
+const SourceAutomation = "automation"
+
+// TriggerManual - описание триггера при ручном запуске правила
+const TriggerManual = "manual"
+
+// ErrRuleNotFound возвращается для неизвестного правила
+var ErrRuleNotFound = errors.New("automation rule not found")
+
+// Notifier доставляет уведомления правил автоматизации
+type Notifier interface {
+	Notify(ruleID, message string) error
+}
+
+// Engine периодически вычисляет правила автоматизации и выполняет их действия
+type Engine struct {
+	rules    []Rule
+	repo     *database.Repository
+	devices  *devices.Manager
+	notifier Notifier
+	config   config.AutomationConfig
+	logger   *log.Logger
+
+	mu       sync.Mutex
+	active   map[string]bool      // состояние edge-триггеров: "<rule>/<index>" -> условие истинно
+	lastRun  map[string]time.Time // время последнего выполнения правила (для cooldown)
+	lastTick time.Time
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewEngine создает движок автоматизации
+func NewEngine(rules []Rule, repo *database.Repository, deviceManager *devices.Manager, notifier Notifier, cfg config.AutomationConfig, logger *log.Logger) *Engine {
+	return &Engine{
+		rules:    rules,
+		repo:     repo,
+		devices:  deviceManager,
+		notifier: notifier,
+		config:   cfg,
+		logger:   logger,
+		active:   make(map[string]bool),
+		lastRun:  make(map[string]time.Time),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Rules возвращает загруженные правила
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// DryRun сообщает, включен ли глобальный пробный режим
+func (e *Engine) DryRun() bool {
+	return e.config.DryRun
+}
+
+// Start запускает периодическое вычисление правил
+func (e *Engine) Start() {
+	go func() {
+		ticker := time.NewTicker(e.config.Interval)
+		defer ticker.Stop()
+
+		e.logger.Printf("Starting automation engine with %d rules, interval %s, dry run %t",
+			len(e.rules), e.config.Interval, e.config.DryRun)
+
+		e.Evaluate(time.Now())
+		for {
+			select {
+			case now := <-ticker.C:
+				e.Evaluate(now)
+			case <-e.stopChan:
+				e.logger.Println("Stopping automation engine")
+				return
+			}
+		}
+	}()
+}
+
+// Stop останавливает движок
+func (e *Engine) Stop() {
+	e.stopOnce.Do(func() { close(e.stopChan) })
+}
+
+// Evaluate вычисляет все правила на момент now и выполняет сработавшие
+func (e *Engine) Evaluate(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	snapshot, err := e.snapshot(now)
+	if err != nil {
+		e.logger.Printf("Error getting automation snapshot: %v", err)
+		return
+	}
+
+	now = now.In(e.config.Location)
+	prevTick := e.lastTick
+	e.lastTick = now
+
+	for _, rule := range e.rules {
+		if !rule.IsEnabled() {
+			continue
+		}
+
+		trigger, fired := e.checkTriggers(rule, snapshot, prevTick, now)
+		if !fired {
+			continue
+		}
+
+		if ok, reason := e.checkConditions(rule, snapshot, now); !ok {
+			e.logger.Printf("Automation %s triggered by %s, conditions not met: %s", rule.ID, trigger, reason)
+			continue
+		}
+
+		if last, ok := e.lastRun[rule.ID]; ok && rule.Cooldown > 0 && now.Sub(last) < rule.Cooldown {
+			e.logger.Printf("Automation %s triggered by %s, skipped due to cooldown", rule.ID, trigger)
+			continue
+		}
+
+		e.lastRun[rule.ID] = now
+		e.run(rule, trigger, e.config.DryRun || rule.DryRun)
+	}
+}
+
+// RunNow выполняет действия правила немедленно, без проверки триггеров и условий
+func (e *Engine) RunNow(ruleID string, dryRun bool) (models.AutomationRun, error) {
+	for _, rule := range e.rules {
+		if rule.ID == ruleID {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			return e.run(rule, TriggerManual, dryRun || e.config.DryRun || rule.DryRun), nil
+		}
+	}
+	return models.AutomationRun{}, fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+}
+
+// snapshot содержит последние показания датчиков и состояния устройств.
+// Устаревшие показания в него не попадают: замолчавший датчик не должен
+// запускать правила своим последним значением
+type snapshot struct {
+	readings map[string]models.SensorData
+	devices  map[string]models.ActuatorState
+}
+
+func (e *Engine) snapshot(now time.Time) (snapshot, error) {
+	readings, err := e.repo.GetLatestReadings()
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed to get latest readings: %w", err)
+	}
+
+	s := snapshot{
+		readings: make(map[string]models.SensorData, len(readings)),
+		devices:  make(map[string]models.ActuatorState),
+	}
+	for _, r := range readings {
+		if e.config.MaxReadingAge > 0 && now.Sub(r.Timestamp) > e.config.MaxReadingAge {
+			continue
+		}
+		s.readings[r.SensorID] = r
+	}
+	if e.devices != nil {
+		for _, d := range e.devices.List() {
+			s.devices[d.ID()] = d.State()
+		}
+	}
+	return s, nil
+}
+
+// checkTriggers возвращает описание первого сработавшего триггера. Триггеры по
+// показаниям и состоянию устройств срабатывают только при переходе условия в
+// истину, триггеры по времени - при пересечении заданного времени суток
+func (e *Engine) checkTriggers(rule Rule, s snapshot, prevTick, now time.Time) (string, bool) {
+	var description string
+	fired := false
+
+	for i, t := range rule.Triggers {
+		key := fmt.Sprintf("%s/%d", rule.ID, i)
+
+		switch t.Type {
+		case TriggerReading:
+			reading, ok := s.readings[t.Sensor]
+			if !ok {
+				continue
+			}
+			value, ok := alerting.Value(reading, t.Field)
+			matches := ok && compare(value, t.Operator, t.Value)
+			if matches && !e.active[key] && !fired {
+				description = fmt.Sprintf("%s %s %s %v (value %.2f)", t.Sensor, t.Field, t.Operator, t.Value, value)
+				fired = true
+			}
+			e.active[key] = matches
+
+		case TriggerDeviceState:
+			state, ok := s.devices[t.Device]
+			if !ok {
+				continue
+			}
+			actual, ok := state[t.Key]
+			matches := ok && compare(actual, t.Operator, t.Value)
+			if matches && !e.active[key] && !fired {
+				description = fmt.Sprintf("%s.%s %s %v", t.Device, t.Key, t.Operator, t.Value)
+				fired = true
+			}
+			e.active[key] = matches
+
+		case TriggerTime:
+			if prevTick.IsZero() || fired || !dayAllowed(t.Days, now) {
+				continue
+			}
+			offset, _ := config.ParseClock(t.At)
+			at := midnight(now).Add(offset)
+			if at.After(prevTick) && !at.After(now) {
+				description = "time " + t.At
+				fired = true
+			}
+		}
+	}
+
+	return description, fired
+}
+
+// checkConditions проверяет все условия правила и возвращает причину отказа
+func (e *Engine) checkConditions(rule Rule, s snapshot, now time.Time) (bool, string) {
+	for _, c := range rule.Conditions {
+		switch c.Type {
+		case ConditionSensor:
+			reading, ok := s.readings[c.Sensor]
+			if !ok {
+				return false, fmt.Sprintf("no recent readings from %s", c.Sensor)
+			}
+			value, ok := alerting.Value(reading, c.Field)
+			if !ok || !compare(value, c.Operator, c.Value) {
+				return false, fmt.Sprintf("%s %s %s %v", c.Sensor, c.Field, c.Operator, c.Value)
+			}
+
+		case ConditionRoom:
+			if !roomMatches(c, s) {
+				return false, fmt.Sprintf("room %s %s %s %s %v", c.Room, c.SensorType, c.Field, c.Operator, c.Value)
+			}
+
+		case ConditionDeviceState:
+			actual, ok := s.devices[c.Device][c.Key]
+			if !ok || !compare(actual, c.Operator, c.Value) {
+				return false, fmt.Sprintf("%s.%s %s %v", c.Device, c.Key, c.Operator, c.Value)
+			}
+
+		case ConditionTime:
+			if !timeMatches(c, now) {
+				return false, fmt.Sprintf("time outside %s-%s %v", c.After, c.Before, c.Days)
+			}
+		}
+	}
+	return true, ""
+}
+
+// roomMatches проверяет, что хотя бы один датчик комнаты заданного типа
+// удовлетворяет условию
+func roomMatches(c Condition, s snapshot) bool {
+	for _, reading := range s.readings {
+		if reading.SensorType != c.SensorType || models.SensorLocation(reading.SensorID) != c.Room {
+			continue
+		}
+		if value, ok := alerting.Value(reading, c.Field); ok && compare(value, c.Operator, c.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeMatches проверяет попадание в интервал времени суток; интервал, у которого
+// after позже before, переходит через полночь
+func timeMatches(c Condition, now time.Time) bool {
+	if !dayAllowed(c.Days, now) {
+		return false
+	}
+
+	offset := now.Sub(midnight(now))
+	after, before := time.Duration(0), 24*time.Hour
+	if c.After != "" {
+		after, _ = config.ParseClock(c.After)
+	}
+	if c.Before != "" {
+		before, _ = config.ParseClock(c.Before)
+	}
+
+	if after <= before {
+		return offset >= after && offset < before
+	}
+	return offset >= after || offset < before
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// run выполняет действия правила и сохраняет запись в истории
+func (e *Engine) run(rule Rule, trigger string, dryRun bool) models.AutomationRun {
+	run := models.AutomationRun{
+		RuleID:    rule.ID,
+		Trigger:   trigger,
+		DryRun:    dryRun,
+		Success:   true,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, action := range rule.Actions {
+		result := models.ActionResult{Type: action.Type}
+
+		switch action.Type {
+		case ActionDevice:
+			result.Target = action.Device
+			result.Command = action.Command
+			result.Value = action.Value
+			if dryRun {
+				e.logger.Printf("Automation %s (dry run): would send %s %v to %s", rule.ID, action.Command, action.Value, action.Device)
+				break
+			}
+			if e.devices == nil {
+				result.Error = "devices are not available"
+				break
+			}
+			cmd := models.Command{Name: action.Command, Value: action.Value}
+			if _, err := e.devices.Execute(action.Device, cmd, SourceAutomation); err != nil {
+				result.Error = err.Error()
+			}
+
+		case ActionNotify:
+			result.Target = action.Message
+			if dryRun {
+				e.logger.Printf("Automation %s (dry run): would notify %q", rule.ID, action.Message)
+				break
+			}
+			if e.notifier == nil {
+				result.Error = "notifier is not configured"
+				break
+			}
+			if err := e.notifier.Notify(rule.ID, action.Message); err != nil {
+				result.Error = err.Error()
+			}
+		}
+
+		if result.Error != "" {
+			run.Success = false
+			e.logger.Printf("Automation %s: %s action on %s failed: %s", rule.ID, action.Type, result.Target, result.Error)
+		}
+		run.Actions = append(run.Actions, result)
+	}
+
+	if !run.Success {
+		run.Error = "one or more actions failed"
+	}
+
+	e.logger.Printf("Automation %s executed (trigger: %s, dry run: %t, success: %t)", rule.ID, trigger, dryRun, run.Success)
+
+	if err := e.repo.SaveAutomationRun(run); err != nil {
+		e.logger.Printf("Error saving automation run of %s: %v", rule.ID, err)
+	}
+
+	return run
+}
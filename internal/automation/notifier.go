@@ -0,0 +1,32 @@
+package automation
+
+import (
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// AlertType - тип оповещения, которым сохраняются уведомления автоматизации
+const AlertType = "automation"
+
+// AlertNotifier сохраняет уведомления в таблицу оповещений, откуда их
+// доставляет Telegram-бот
+type AlertNotifier struct {
+	repo *database.Repository
+}
+
+// NewAlertNotifier создает уведомитель, сохраняющий оповещения в БД
+func NewAlertNotifier(repo *database.Repository) *AlertNotifier {
+	return &AlertNotifier{repo: repo}
+}
+
+// Notify сохраняет уведомление правила
+func (n *AlertNotifier) Notify(ruleID, message string) error {
+	return n.repo.SaveAlert(models.Alert{
+		SensorID:  ruleID,
+		AlertType: AlertType,
+		Message:   message,
+		CreatedAt: time.Now().UTC(),
+	})
+}
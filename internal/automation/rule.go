@@ -0,0 +1,257 @@
+// Package automation выполняет декларативные правила "триггер → условие → действие":
+// триггеры по показаниям датчиков, времени и состоянию устройств, условия по
+// датчикам, комнатам и времени суток, действия - команды устройств и уведомления
+package automation
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Типы триггеров, условий и действий
+const (
+	TriggerReading     = "reading"
+	TriggerTime        = "time"
+	TriggerDeviceState = "device_state"
+
+	ConditionSensor      = "sensor"
+	ConditionRoom        = "room"
+	ConditionTime        = "time"
+	ConditionDeviceState = "device_state"
+
+	ActionDevice = "device"
+	ActionNotify = "notify"
+)
+
+// RulesFile - формат файла правил автоматизации
+type RulesFile struct {
+	Automations []Rule `yaml:"automations"`
+}
+
+// Rule описывает правило автоматизации; правило срабатывает, когда срабатывает
+// любой из триггеров и выполняются все условия
+type Rule struct {
+	ID          string        `yaml:"id" json:"id"`
+	Description string        `yaml:"description" json:"description,omitempty"`
+	Enabled     *bool         `yaml:"enabled" json:"-"`
+	DryRun      bool          `yaml:"dry_run" json:"dryRun"`
+	Cooldown    time.Duration `yaml:"cooldown" json:"-"`
+	Triggers    []Trigger     `yaml:"triggers" json:"triggers,omitempty"`
+	Conditions  []Condition   `yaml:"conditions" json:"conditions,omitempty"`
+	Actions     []Action      `yaml:"actions" json:"actions,omitempty"`
+}
+
+// Trigger описывает событие, запускающее правило. Триггеры по показаниям и
+// состоянию устройств срабатывают в момент, когда сравнение становится истинным
+type Trigger struct {
+	Type     string      `yaml:"type" json:"type"`
+	Sensor   string      `yaml:"sensor" json:"sensor,omitempty"`     // reading: ID датчика
+	Field    string      `yaml:"field" json:"field,omitempty"`       // reading: поле значения (co2, nh3)
+	Device   string      `yaml:"device" json:"device,omitempty"`     // device_state: ID устройства
+	Key      string      `yaml:"key" json:"key,omitempty"`           // device_state: ключ состояния (on, speed, ...)
+	Operator string      `yaml:"operator" json:"operator,omitempty"` // reading, device_state: оператор сравнения
+	Value    interface{} `yaml:"value" json:"value,omitempty"`       // reading, device_state: значение для сравнения
+	At       string      `yaml:"at" json:"at,omitempty"`             // time: время срабатывания "HH:MM"
+	Days     []string    `yaml:"days" json:"days,omitempty"`         // time: дни недели (mon, tue, ...), пусто - каждый день
+}
+
+// Condition описывает дополнительное условие выполнения правила
+type Condition struct {
+	Type       string      `yaml:"type" json:"type"`
+	Sensor     string      `yaml:"sensor" json:"sensor,omitempty"`          // sensor: ID датчика
+	Room       string      `yaml:"room" json:"room,omitempty"`              // room: комната (часть ID датчика после типа)
+	SensorType string      `yaml:"sensor_type" json:"sensorType,omitempty"` // room: тип датчиков комнаты
+	Field      string      `yaml:"field" json:"field,omitempty"`            // sensor, room: поле значения
+	Device     string      `yaml:"device" json:"device,omitempty"`          // device_state: ID устройства
+	Key        string      `yaml:"key" json:"key,omitempty"`                // device_state: ключ состояния
+	Operator   string      `yaml:"operator" json:"operator,omitempty"`      // оператор сравнения
+	Value      interface{} `yaml:"value" json:"value,omitempty"`            // значение для сравнения
+	After      string      `yaml:"after" json:"after,omitempty"`            // time: начало интервала "HH:MM"
+	Before     string      `yaml:"before" json:"before,omitempty"`          // time: конец интервала "HH:MM" (может переходить через полночь)
+	Days       []string    `yaml:"days" json:"days,omitempty"`              // time: дни недели
+}
+
+// Action описывает действие правила
+type Action struct {
+	Type    string      `yaml:"type" json:"type"`
+	Device  string      `yaml:"device" json:"device,omitempty"`   // device: ID устройства
+	Command string      `yaml:"command" json:"command,omitempty"` // device: команда
+	Value   interface{} `yaml:"value" json:"value,omitempty"`     // device: аргумент команды
+	Message string      `yaml:"message" json:"message,omitempty"` // notify: текст уведомления
+}
+
+// IsEnabled сообщает, включено ли правило (по умолчанию включено)
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// LoadRules загружает правила из YAML-файла
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read automation rules: %w", err)
+	}
+
+	var file RulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode automation rules: %w", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, rule := range file.Automations {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid automation rule %q: %w", rule.ID, err)
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("duplicate automation rule ID %q", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+
+	return file.Automations, nil
+}
+
+// Validate проверяет правило
+func (r Rule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule ID cannot be empty")
+	}
+	if len(r.Triggers) == 0 {
+		return fmt.Errorf("at least one trigger is required")
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("at least one action is required")
+	}
+	if r.Cooldown < 0 {
+		return fmt.Errorf("cooldown cannot be negative")
+	}
+
+	for i, t := range r.Triggers {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("trigger %d: %w", i+1, err)
+		}
+	}
+	for i, c := range r.Conditions {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("condition %d: %w", i+1, err)
+		}
+	}
+	for i, a := range r.Actions {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("action %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate проверяет триггер
+func (t Trigger) Validate() error {
+	switch t.Type {
+	case TriggerReading:
+		if t.Sensor == "" {
+			return fmt.Errorf("reading trigger requires a sensor")
+		}
+		return validateComparison(t.Operator, t.Value)
+	case TriggerDeviceState:
+		if t.Device == "" || t.Key == "" {
+			return fmt.Errorf("device_state trigger requires a device and a key")
+		}
+		return validateComparison(t.Operator, t.Value)
+	case TriggerTime:
+		if _, err := config.ParseClock(t.At); err != nil {
+			return err
+		}
+		return validateDays(t.Days)
+	default:
+		return fmt.Errorf("unknown trigger type %q", t.Type)
+	}
+}
+
+// Validate проверяет условие
+func (c Condition) Validate() error {
+	switch c.Type {
+	case ConditionSensor:
+		if c.Sensor == "" {
+			return fmt.Errorf("sensor condition requires a sensor")
+		}
+		return validateComparison(c.Operator, c.Value)
+	case ConditionRoom:
+		if c.Room == "" || c.SensorType == "" {
+			return fmt.Errorf("room condition requires a room and a sensor_type")
+		}
+		return validateComparison(c.Operator, c.Value)
+	case ConditionDeviceState:
+		if c.Device == "" || c.Key == "" {
+			return fmt.Errorf("device_state condition requires a device and a key")
+		}
+		return validateComparison(c.Operator, c.Value)
+	case ConditionTime:
+		if c.After == "" && c.Before == "" && len(c.Days) == 0 {
+			return fmt.Errorf("time condition requires after, before or days")
+		}
+		for _, clock := range []string{c.After, c.Before} {
+			if clock == "" {
+				continue
+			}
+			if _, err := config.ParseClock(clock); err != nil {
+				return err
+			}
+		}
+		return validateDays(c.Days)
+	default:
+		return fmt.Errorf("unknown condition type %q", c.Type)
+	}
+}
+
+// Validate проверяет действие
+func (a Action) Validate() error {
+	switch a.Type {
+	case ActionDevice:
+		if a.Device == "" || a.Command == "" {
+			return fmt.Errorf("device action requires a device and a command")
+		}
+	case ActionNotify:
+		if a.Message == "" {
+			return fmt.Errorf("notify action requires a message")
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+	return nil
+}
+
+func validateComparison(operator string, value interface{}) error {
+	if _, ok := operators[operator]; !ok {
+		return fmt.Errorf("unknown operator %q", operator)
+	}
+	if value == nil {
+		return fmt.Errorf("comparison value is required")
+	}
+	return nil
+}
+
+func validateDays(days []string) error {
+	for _, day := range days {
+		if _, ok := config.ParseWeekday(day); !ok {
+			return fmt.Errorf("unknown weekday %q", day)
+		}
+	}
+	return nil
+}
+
+func dayAllowed(days []string, t time.Time) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, day := range days {
+		if weekday, ok := config.ParseWeekday(day); ok && weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
@@ -1,47 +1,93 @@
 package bot
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/4Amangel1/smart-house-automate/internal/automation"
+	"github.com/4Amangel1/smart-house-automate/internal/chart"
 	"github.com/4Amangel1/smart-house-automate/internal/config"
-	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/report"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+const (
+	// defaultHistoryPeriod - период /history, если он не указан явно
+	defaultHistoryPeriod = 24 * time.Hour
+	// historyTextLimit - количество показаний в текстовой истории
+	historyTextLimit = 10
+)
+
 type Bot struct {
-	api             *tgbotapi.BotAPI
-	repo            *database.Repository
+	api             Transport
+	name            string
+	repo            Repository
 	authorizedUsers map[int64]bool
 	logger          *log.Logger
 	stopChan        chan struct{}
 	config          config.TelegramBotConfig
+
+	mu            sync.Mutex
+	polling       bool
+	webhookServer *http.Server
+	languages     map[int64]i18n.Lang
+	unitPrefs     map[int64]units.Preference
+	firingRules   map[int64]bool
+	activeAlerts  map[string]bool
+
+	// lastAutomationAlert - ID последнего разосланного уведомления автоматизации
+	// (используется только горутиной сервиса уведомлений); до первой проверки
+	// automationAlertsLoaded = false
+	lastAutomationAlert    int64
+	automationAlertsLoaded bool
 }
 
-func NewBot(cfg config.TelegramBotConfig, repo *database.Repository, logger *log.Logger) (*Bot, error) {
+func NewBot(cfg config.TelegramBotConfig, repo Repository, logger *log.Logger) (*Bot, error) {
 	if cfg.Token == "" {
 		return nil, fmt.Errorf("telegram bot token is required")
 	}
 
 	logger.Printf("Bot starting with token length: %d", len(cfg.Token))
-	logger.Printf("Authorized users: %v", cfg.AuthorizedUserIDs)
 
-	api, err := tgbotapi.NewBotAPI(cfg.Token)
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.Token, cfg.APIEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bot: %w", err)
 	}
 
+	return NewBotWithTransport(cfg, api, api.Self.UserName, repo, logger)
+}
+
+// NewBotWithTransport создает бота поверх произвольного транспорта
+func NewBotWithTransport(cfg config.TelegramBotConfig, api Transport, name string, repo Repository, logger *log.Logger) (*Bot, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid telegram bot config: %w", err)
+	}
+
+	logger.Printf("Authorized users: %v", cfg.AuthorizedUserIDs)
+
 	bot := &Bot{
 		api:             api,
+		name:            name,
 		repo:            repo,
 		authorizedUsers: make(map[int64]bool),
 		logger:          logger,
 		stopChan:        make(chan struct{}),
 		config:          cfg,
+		languages:       make(map[int64]i18n.Lang),
+		unitPrefs:       make(map[int64]units.Preference),
+		firingRules:     make(map[int64]bool),
+		activeAlerts:    make(map[string]bool),
 	}
 
 	for _, id := range cfg.AuthorizedUserIDs {
@@ -52,85 +98,134 @@ func NewBot(cfg config.TelegramBotConfig, repo *database.Repository, logger *log
 	return bot, nil
 }
 
+// Start запускает прием обновлений в режиме, выбранном в конфигурации,
+// и блокируется до остановки бота
 func (b *Bot) Start() error {
-
 	b.logger.Printf("Bot starting with token length: %d", len(b.config.Token))
 	b.logger.Printf("Authorized users map: %v", b.authorizedUsers)
 
+	if b.config.Mode == ModeWebhook {
+		return b.startWebhook()
+	}
+	return b.startPolling()
+}
+
+func (b *Bot) startPolling() error {
+	// Telegram не отдает обновления через getUpdates, пока зарегистрирован вебхук
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		b.logger.Printf("Error deleting webhook: %v", err)
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
+	b.mu.Lock()
 	updates := b.api.GetUpdatesChan(u)
+	b.polling = true
+	b.mu.Unlock()
 
 	b.logger.Printf("Authorized users: %d", len(b.authorizedUsers))
-	b.logger.Printf("Bot %s started successfully", b.api.Self.UserName)
+	b.logger.Printf("Bot %s started successfully in polling mode", b.name)
 
 	for update := range updates {
-		if update.Message == nil {
-			continue
-		}
-
-		chatID := update.Message.Chat.ID
-		userID := update.Message.From.ID
+		b.HandleUpdate(update)
+	}
 
-		b.logger.Printf("Received message from user %d in chat %d: %s",
-			userID, chatID, update.Message.Text)
+	return nil
+}
 
-		if update.Message.IsCommand() {
-			b.handleCommand(update.Message)
-		}
+// HandleUpdate обрабатывает одно обновление Telegram; используется
+// и в режиме long polling, и в режиме вебхука
+func (b *Bot) HandleUpdate(update tgbotapi.Update) {
+	if update.Message == nil || update.Message.From == nil {
+		return
 	}
 
-	return nil
+	chatID := update.Message.Chat.ID
+	userID := update.Message.From.ID
+
+	b.logger.Printf("Received message from user %d in chat %d: %s",
+		userID, chatID, update.Message.Text)
+
+	if update.Message.IsCommand() {
+		b.handleCommand(update.Message)
+	}
 }
 
 func (b *Bot) handleCommand(message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	userID := message.From.ID
+	l := b.localizer(userID, message.From.LanguageCode)
 
 	b.logger.Printf("Received command from user %d: %s", userID, message.Command())
 
 	switch message.Command() {
 	case "start":
+		b.mu.Lock()
 		if len(b.authorizedUsers) == 0 {
 			b.authorizedUsers[userID] = true
 			b.logger.Printf("Added first user as authorized: %d", userID)
 		}
+		b.mu.Unlock()
 
-		text := "Добро пожаловать в систему управления умным домом!\n\n" +
-			"Доступные команды:\n" +
-			"/status - текущие показания датчиков\n" +
-			"/history [sensor_id] - история показаний конкретного датчика"
+		b.sendMessage(chatID, l.T("start.welcome"))
 
-		b.sendMessage(chatID, text)
+	case "lang":
+		b.handleLanguage(chatID, userID, message.CommandArguments(), l)
+
+	case "units":
+		b.handleUnits(chatID, userID, message.CommandArguments(), l)
+
+	case "thresholds", "rules":
+		if !b.isAuthorized(userID) {
+			b.sendMessage(chatID, l.T("error.unauthorized"))
+			return
+		}
+
+		if message.Command() == "rules" {
+			b.handleRules(chatID, l)
+		} else {
+			b.handleThresholds(chatID, l)
+		}
+
+	case "setthreshold", "deleterule":
+		if !b.isAdmin(userID) {
+			b.sendMessage(chatID, l.T("rules.admin_only"))
+			return
+		}
+
+		if message.Command() == "setthreshold" {
+			b.handleSetThreshold(chatID, userID, message.CommandArguments(), l)
+		} else {
+			b.handleDeleteRule(chatID, userID, message.CommandArguments(), l)
+		}
 
 	case "status":
 		if !b.isAuthorized(userID) {
-			b.sendMessage(chatID, "У вас нет прав для использования этой команды.")
+			b.sendMessage(chatID, l.T("error.unauthorized"))
 			return
 		}
 
 		readings, err := b.repo.GetLatestReadings()
 		if err != nil {
 			b.logger.Printf("Error getting latest readings: %v", err)
-			b.sendMessage(chatID, "Ошибка при получении данных.")
+			b.sendMessage(chatID, l.T("error.readings"))
 			return
 		}
 
 		if len(readings) == 0 {
-			b.sendMessage(chatID, "Нет доступных показаний.")
+			b.sendMessage(chatID, l.T("status.empty"))
 			return
 		}
 
 		var msgBuilder strings.Builder
-		msgBuilder.WriteString("📊 *Текущие показания датчиков:*\n\n")
+		msgBuilder.WriteString(l.T("status.title"))
 
 		for _, reading := range readings {
-			formattedValue := b.formatSensorValue(reading)
-			msgBuilder.WriteString(fmt.Sprintf("🔹 *%s* (%s):\n%s\n\n",
+			msgBuilder.WriteString(l.T("status.item",
 				reading.SensorID,
-				b.translateSensorType(reading.SensorType),
-				formattedValue,
+				b.translateSensorType(reading.SensorType, l),
+				b.formatSensorValue(reading, l),
 			))
 		}
 
@@ -138,48 +233,111 @@ func (b *Bot) handleCommand(message *tgbotapi.Message) {
 
 	case "history":
 		if !b.isAuthorized(userID) {
-			b.sendMessage(chatID, "У вас нет прав для использования этой команды.")
+			b.sendMessage(chatID, l.T("error.unauthorized"))
 			return
 		}
 
-		args := message.CommandArguments()
-		if args == "" {
-			b.sendMessage(chatID, "Пожалуйста, укажите ID датчика.\nПример: /history temp_sensor_1")
+		args := strings.Fields(message.CommandArguments())
+		if len(args) == 0 {
+			b.sendMessage(chatID, l.T("history.usage"))
 			return
 		}
 
-		sensorID := args
-		readings, err := b.repo.GetReadingHistory(sensorID, 10)
-		if err != nil {
-			b.logger.Printf("Error getting reading history: %v", err)
-			b.sendMessage(chatID, "Ошибка при получении истории показаний.")
+		period := defaultHistoryPeriod
+		if len(args) > 1 {
+			p, err := chart.ParsePeriod(args[1])
+			if err != nil {
+				b.sendMessage(chatID, l.T("history.invalid_period"))
+				return
+			}
+			period = p
+		}
+
+		b.sendHistory(chatID, args[0], period, l)
+
+	case "report":
+		if !b.isAuthorized(userID) {
+			b.sendMessage(chatID, l.T("error.unauthorized"))
 			return
 		}
 
-		if len(readings) == 0 {
-			b.sendMessage(chatID, fmt.Sprintf("История показаний для датчика %s не найдена.", sensorID))
+		kind := report.Daily
+		if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+			k, err := report.ParseKind(args)
+			if err != nil {
+				b.sendMessage(chatID, l.T("report.unknown_kind"))
+				return
+			}
+			kind = k
+		}
+
+		r, err := report.Build(b.repo, kind, time.Now().UTC())
+		if err != nil {
+			b.logger.Printf("Error building report: %v", err)
+			b.sendMessage(chatID, l.T("report.error"))
 			return
 		}
 
-		var msgBuilder strings.Builder
-		msgBuilder.WriteString(fmt.Sprintf("📈 *История показаний датчика %s:*\n\n", sensorID))
+		b.sendMarkdownMessage(chatID, report.Format(r, time.Local, l))
+
+	default:
+		b.sendMessage(chatID, l.T("command.unknown"))
+	}
+}
 
-		for i, reading := range readings {
-			formattedTime := reading.Timestamp.Format("02.01.2006 15:04:05")
-			formattedValue := b.formatSensorValue(reading)
+// sendHistory отправляет график показаний датчика за период,
+// а если график построить нельзя - последние показания текстом
+func (b *Bot) sendHistory(chatID int64, sensorID string, period time.Duration, l *i18n.Localizer) {
+	to := time.Now().UTC()
+	from := to.Add(-period)
 
-			msgBuilder.WriteString(fmt.Sprintf("%d. *%s*\n%s\n\n",
-				i+1,
-				formattedTime,
-				formattedValue,
-			))
+	readings, err := b.repo.GetReadingsInRange(sensorID, from, to)
+	if err != nil {
+		b.logger.Printf("Error getting reading history: %v", err)
+		b.sendMessage(chatID, l.T("history.error"))
+		return
+	}
+
+	if len(readings) == 0 {
+		b.sendMessage(chatID, l.T("history.not_found", sensorID, l.Period(period)))
+		return
+	}
+
+	latest := readings[len(readings)-1]
+
+	c, err := chart.FromReadings(sensorID, latest.SensorType, sensortype.ConvertReadings(readings, l.Units()), from, to)
+	if err == nil {
+		var buf bytes.Buffer
+		if err = chart.Render(&buf, c); err == nil {
+			caption := l.T("history.caption",
+				sensorID,
+				l.Period(period),
+				l.DateTime(latest.Timestamp.Local()),
+				b.formatSensorValue(latest, l),
+			)
+			b.sendPhoto(chatID, sensorID+".png", buf.Bytes(), caption)
+			return
 		}
+	}
+	b.logger.Printf("Error rendering chart for sensor %s: %v", sensorID, err)
 
-		b.sendMarkdownMessage(chatID, msgBuilder.String())
+	var msgBuilder strings.Builder
+	msgBuilder.WriteString(l.T("history.title", sensorID))
 
-	default:
-		b.sendMessage(chatID, "Неизвестная команда. Используйте /start для получения списка доступных команд.")
+	start := 0
+	if len(readings) > historyTextLimit {
+		start = len(readings) - historyTextLimit
+	}
+	for i := len(readings) - 1; i >= start; i-- {
+		reading := readings[i]
+		msgBuilder.WriteString(l.T("history.item",
+			len(readings)-i,
+			l.DateTime(reading.Timestamp.Local()),
+			b.formatSensorValue(reading, l),
+		))
 	}
+
+	b.sendMarkdownMessage(chatID, msgBuilder.String())
 }
 
 func (b *Bot) LoadAuthorizedUsers(userIDsString string) {
@@ -197,7 +355,9 @@ func (b *Bot) LoadAuthorizedUsers(userIDsString string) {
 			continue
 		}
 
+		b.mu.Lock()
 		b.authorizedUsers[id] = true
+		b.mu.Unlock()
 		b.logger.Printf("Added authorized user: %d", id)
 	}
 }
@@ -207,13 +367,21 @@ func (b *Bot) StartNotificationService() {
 	defer ticker.Stop()
 
 	b.logger.Printf("Starting notification service with interval %s", b.config.AlertCheckInterval)
+	b.deliverAutomationNotifications()
 
 	for {
 		select {
 		case <-ticker.C:
-			b.checkTemperatureAlerts()
+			rules, err := b.repo.GetAlertRules()
+			if err != nil {
+				b.logger.Printf("Error getting alert rules: %v", err)
+			}
+
+			b.checkTemperatureAlerts(overriddenSensors(rules, ""))
 			b.checkMotionAlerts()
-			b.checkAirQualityAlerts()
+			b.checkAirQualityAlerts(overriddenSensors(rules, "co2"))
+			b.checkRuleAlerts(rules)
+			b.deliverAutomationNotifications()
 		case <-b.stopChan:
 			b.logger.Println("Stopping notification service")
 			return
@@ -221,7 +389,7 @@ func (b *Bot) StartNotificationService() {
 	}
 }
 
-func (b *Bot) checkTemperatureAlerts() {
+func (b *Bot) checkTemperatureAlerts(overridden map[string]bool) {
 	readings, err := b.repo.GetLatestReadingsByType("temperature")
 	if err != nil {
 		b.logger.Printf("Error getting temperature readings: %v", err)
@@ -229,12 +397,13 @@ func (b *Bot) checkTemperatureAlerts() {
 	}
 
 	for _, reading := range readings {
-		if value, ok := reading.Value.Data.(float64); ok {
-			if value > b.config.TemperatureAlertThreshold {
-				message := fmt.Sprintf("🔥 *ВНИМАНИЕ!* Высокая температура (%0.1f°C) на датчике %s",
-					value, reading.SensorID)
-				b.notifyAllUsers(message)
-			}
+		if overridden[reading.SensorID] {
+			continue
+		}
+		if value, err := sensortype.Decode(reading.SensorType, reading.Value.Data); err == nil {
+			b.builtinAlert(reading.SensorID, "high_temperature", value.Number > b.config.TemperatureAlertThreshold, func(l *i18n.Localizer) string {
+				return l.T("alert.high_temperature", l.Quantity(value.Number, units.Celsius, ""), reading.SensorID)
+			})
 		}
 	}
 }
@@ -247,14 +416,15 @@ func (b *Bot) checkMotionAlerts() {
 	}
 
 	for _, reading := range readings {
-		if value, ok := reading.Value.Data.(bool); ok && value {
-			message := fmt.Sprintf("👤 *Обнаружено движение* на датчике %s", reading.SensorID)
-			b.notifyAllUsers(message)
+		if value, err := sensortype.Decode(reading.SensorType, reading.Value.Data); err == nil {
+			b.builtinAlert(reading.SensorID, "motion", value.Bool, func(l *i18n.Localizer) string {
+				return l.T("alert.motion", reading.SensorID)
+			})
 		}
 	}
 }
 
-func (b *Bot) checkAirQualityAlerts() {
+func (b *Bot) checkAirQualityAlerts(overridden map[string]bool) {
 	readings, err := b.repo.GetLatestReadingsByType("air_quality")
 	if err != nil {
 		b.logger.Printf("Error getting air quality readings: %v", err)
@@ -262,26 +432,130 @@ func (b *Bot) checkAirQualityAlerts() {
 	}
 
 	for _, reading := range readings {
-		if valueMap, ok := reading.Value.Data.(map[string]interface{}); ok {
-			if co2, co2ok := valueMap["co2"].(float64); co2ok && co2 > 1000 {
-				message := fmt.Sprintf("⚠️ *ВНИМАНИЕ!* Высокий уровень CO₂ (%0.0f ppm) на датчике %s",
-					co2, reading.SensorID)
-				b.notifyAllUsers(message)
-			}
+		if overridden[reading.SensorID] {
+			continue
+		}
+		if value, err := sensortype.Decode(reading.SensorType, reading.Value.Data); err == nil {
+			co2 := value.Fields["co2"]
+			b.builtinAlert(reading.SensorID, "high_co2", co2 > defaultCO2Threshold, func(l *i18n.Localizer) string {
+				return l.T("alert.high_co2", l.Quantity(co2, units.PPM, "co2"), reading.SensorID)
+			})
+		}
+	}
+}
+
+// deliverAutomationNotifications рассылает уведомления, сохраненные движком
+// автоматизации после последнего разосланного. Уведомления отслеживаются по
+// ID, а не по времени создания: запись может зафиксироваться позже, чем
+// указано в created_at, и выпасть из окна проверки
+func (b *Bot) deliverAutomationNotifications() {
+	// Уведомления, сохраненные до запуска сервиса, не рассылаются
+	if !b.automationAlertsLoaded {
+		id, err := b.repo.GetLastAlertID()
+		if err != nil {
+			b.logger.Printf("Error getting last alert: %v", err)
+			return
 		}
+		b.lastAutomationAlert, b.automationAlertsLoaded = id, true
+		return
+	}
+
+	alerts, err := b.repo.GetAlertsAfter(b.lastAutomationAlert, automation.AlertType)
+	if err != nil {
+		b.logger.Printf("Error getting automation notifications: %v", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		alert := alert
+		b.notifyAllUsers(func(l *i18n.Localizer) string {
+			return l.T("alert.automation", alert.SensorID, alert.Message)
+		})
+		b.lastAutomationAlert = alert.ID
+	}
+}
+
+// raiseAlert сохраняет оповещение для отчетов и рассылает его пользователям
+// на их языке
+func (b *Bot) raiseAlert(sensorID, alertType string, message func(l *i18n.Localizer) string) {
+	b.saveAlert(sensorID, alertType, message)
+	b.notifyAllUsers(message)
+}
+
+// builtinAlert рассылает оповещение встроенной проверки на каждой проверке,
+// пока выполняется ее условие, а сохраняет его только при переходе в
+// сработавшее состояние: отчеты считают срабатывания, а не проверки
+func (b *Bot) builtinAlert(sensorID, alertType string, active bool, message func(l *i18n.Localizer) string) {
+	key := alertType + "/" + sensorID
+
+	b.mu.Lock()
+	wasActive := b.activeAlerts[key]
+	if active {
+		b.activeAlerts[key] = true
+	} else {
+		delete(b.activeAlerts, key)
+	}
+	b.mu.Unlock()
+
+	if !active {
+		return
+	}
+	if !wasActive {
+		b.saveAlert(sensorID, alertType, message)
+	}
+	b.notifyAllUsers(message)
+}
+
+// saveAlert сохраняет оповещение для отчетов; текст в БД сохраняется на
+// языке по умолчанию
+func (b *Bot) saveAlert(sensorID, alertType string, message func(l *i18n.Localizer) string) {
+	alert := models.Alert{
+		SensorID:  sensorID,
+		AlertType: alertType,
+		Message:   message(i18n.New(i18n.Default)),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := b.repo.SaveAlert(alert); err != nil {
+		b.logger.Printf("Error saving alert: %v", err)
+	}
+}
+
+func (b *Bot) notifyAllUsers(message func(l *i18n.Localizer) string) {
+	for _, userID := range b.authorizedUserIDs() {
+		b.sendMarkdownMessage(userID, message(b.localizer(userID, "")))
 	}
 }
 
-func (b *Bot) notifyAllUsers(message string) {
-	for userID := range b.authorizedUsers {
-		b.sendMarkdownMessage(userID, message)
+// SendReport отправляет сводный отчет пользователю
+func (b *Bot) SendReport(userID int64, text string) error {
+	msg := tgbotapi.NewMessage(userID, text)
+	msg.ParseMode = "Markdown"
+	if _, err := b.api.Send(msg); err != nil {
+		msg.ParseMode = ""
+		if _, err := b.api.Send(msg); err != nil {
+			return fmt.Errorf("failed to send report: %w", err)
+		}
 	}
+	return nil
 }
 
 func (b *Bot) isAuthorized(userID int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.authorizedUsers[userID]
 }
 
+func (b *Bot) authorizedUserIDs() []int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make([]int64, 0, len(b.authorizedUsers))
+	for id := range b.authorizedUsers {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (b *Bot) sendMessage(chatID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	_, err := b.api.Send(msg)
@@ -300,50 +574,57 @@ func (b *Bot) sendMarkdownMessage(chatID int64, text string) {
 	}
 }
 
-func (b *Bot) formatSensorValue(reading models.SensorData) string {
-	switch reading.SensorType {
-	case "temperature":
-		value, ok := reading.Value.Data.(float64)
-		if ok {
-			return fmt.Sprintf("🌡 Температура: *%.1f°C*", value)
-		}
-	case "motion":
-		value, ok := reading.Value.Data.(bool)
-		if ok {
-			if value {
-				return "🔴 *Обнаружено движение*"
-			} else {
-				return "🟢 *Движение не обнаружено*"
-			}
+func (b *Bot) sendPhoto(chatID int64, name string, data []byte, caption string) {
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	photo.Caption = caption
+	photo.ParseMode = "Markdown"
+	_, err := b.api.Send(photo)
+	if err != nil {
+		b.logger.Printf("Error sending photo to %d: %v", chatID, err)
+		photo.ParseMode = ""
+		if _, err := b.api.Send(photo); err != nil {
+			b.logger.Printf("Error sending photo to %d: %v", chatID, err)
+			b.sendMessage(chatID, caption)
 		}
-	case "air_quality":
-		if valueMap, ok := reading.Value.Data.(map[string]interface{}); ok {
-			co2, co2ok := valueMap["co2"].(float64)
-			nh3, nh3ok := valueMap["nh3"].(float64)
+	}
+}
 
-			if co2ok && nh3ok {
-				return fmt.Sprintf("💨 CO₂: *%.0f ppm*\n💨 NH₃: *%.0f ppm*", co2, nh3)
-			}
+func (b *Bot) formatSensorValue(reading models.SensorData, l *i18n.Localizer) string {
+	if t, ok := sensortype.Lookup(reading.SensorType); ok && t.Format != nil {
+		if value, err := t.Value.Decode(reading.Value.Data); err == nil {
+			return t.Format(value, l)
 		}
 	}
 
 	return fmt.Sprintf("%v", reading.Value.Data)
 }
 
-func (b *Bot) translateSensorType(sensorType string) string {
-	switch sensorType {
-	case "temperature":
-		return "Температура"
-	case "motion":
-		return "Движение"
-	case "air_quality":
-		return "Качество воздуха"
-	default:
-		return sensorType
+func (b *Bot) translateSensorType(sensorType string, l *i18n.Localizer) string {
+	key := "sensor." + sensorType
+	if text := l.T(key); text != key {
+		return text
 	}
+	return sensorType
 }
 
 func (b *Bot) Stop() {
 	b.logger.Println("Stopping bot...")
 	close(b.stopChan)
+
+	b.mu.Lock()
+	polling, webhookServer := b.polling, b.webhookServer
+	b.polling, b.webhookServer = false, nil
+	b.mu.Unlock()
+
+	if polling {
+		b.api.StopReceivingUpdates()
+	}
+
+	if webhookServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := webhookServer.Shutdown(ctx); err != nil {
+			b.logger.Printf("Error shutting down webhook server: %v", err)
+		}
+	}
 }
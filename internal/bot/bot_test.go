@@ -0,0 +1,363 @@
+package bot
+
+import (
+	"io"
+	"log"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/telegramfake"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// memoryRepository - хранилище бота в памяти
+type memoryRepository struct {
+	readings []models.SensorData
+	rules    []models.AlertRule
+	alerts   []models.Alert
+	prefs    map[int64]models.UserPreferences
+}
+
+func (r *memoryRepository) GetLatestReadings() ([]models.SensorData, error) {
+	return r.readings, nil
+}
+
+func (r *memoryRepository) GetLatestReadingsByType(sensorType string) ([]models.SensorData, error) {
+	var result []models.SensorData
+	for _, reading := range r.readings {
+		if reading.SensorType == sensorType {
+			result = append(result, reading)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetReadingHistory(sensorID string, limit int) ([]models.SensorData, error) {
+	var result []models.SensorData
+	for i := len(r.readings) - 1; i >= 0 && len(result) < limit; i-- {
+		if r.readings[i].SensorID == sensorID {
+			result = append(result, r.readings[i])
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetReadingsInRange(sensorID string, from, to time.Time) ([]models.SensorData, error) {
+	var result []models.SensorData
+	for _, reading := range r.readings {
+		if reading.SensorID == sensorID && !reading.Timestamp.Before(from) && !reading.Timestamp.After(to) {
+			result = append(result, reading)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetReadingsByTypeInRange(sensorType string, from, to time.Time) ([]models.SensorData, error) {
+	var result []models.SensorData
+	for _, reading := range r.readings {
+		if reading.SensorType == sensorType && !reading.Timestamp.Before(from) && !reading.Timestamp.After(to) {
+			result = append(result, reading)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetSensorType(sensorID string) (string, error) {
+	for _, reading := range r.readings {
+		if reading.SensorID == sensorID {
+			return reading.SensorType, nil
+		}
+	}
+	return "", database.ErrNotFound
+}
+
+func (r *memoryRepository) SaveAlert(alert models.Alert) error {
+	alert.ID = int64(len(r.alerts) + 1)
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *memoryRepository) GetAlertsInRange(from, to time.Time) ([]models.Alert, error) {
+	return r.alerts, nil
+}
+
+func (r *memoryRepository) GetLastAlertID() (int64, error) {
+	return int64(len(r.alerts)), nil
+}
+
+func (r *memoryRepository) GetAlertsAfter(afterID int64, alertType string) ([]models.Alert, error) {
+	var result []models.Alert
+	for _, alert := range r.alerts {
+		if alert.ID > afterID && alert.AlertType == alertType {
+			result = append(result, alert)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetAlertRules() ([]models.AlertRule, error) {
+	return r.rules, nil
+}
+
+func (r *memoryRepository) SaveAlertRule(rule models.AlertRule) (int64, bool, error) {
+	for i, existing := range r.rules {
+		if existing.SensorID == rule.SensorID && existing.Field == rule.Field && existing.Operator == rule.Operator {
+			rule.ID = existing.ID
+			r.rules[i] = rule
+			return rule.ID, false, nil
+		}
+	}
+	rule.ID = int64(len(r.rules) + 1)
+	r.rules = append(r.rules, rule)
+	return rule.ID, true, nil
+}
+
+func (r *memoryRepository) DeleteAlertRule(id int64) error {
+	for i, rule := range r.rules {
+		if rule.ID == id {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return database.ErrNotFound
+}
+
+func (r *memoryRepository) GetUserPreferences(userID int64) (*models.UserPreferences, error) {
+	prefs, ok := r.prefs[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &prefs, nil
+}
+
+func (r *memoryRepository) SaveUserPreferences(prefs models.UserPreferences) error {
+	r.prefs[prefs.UserID] = prefs
+	return nil
+}
+
+const testUserID = 100
+
+// newTestBot создает бота поверх фейкового Telegram и хранилища в памяти
+func newTestBot(t *testing.T, repo *memoryRepository) (*Bot, *telegramfake.Server) {
+	t.Helper()
+
+	fake := telegramfake.NewServer("")
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", telegramfake.Endpoint(server.URL))
+	if err != nil {
+		t.Fatalf("failed to connect to fake Telegram: %v", err)
+	}
+
+	cfg := config.TelegramBotConfig{AuthorizedUserIDs: []int64{testUserID}}
+	b, err := NewBotWithTransport(cfg, api, api.Self.UserName, repo, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("failed to create bot: %v", err)
+	}
+	return b, fake
+}
+
+// command передает боту команду пользователя и возвращает ответы бота
+func command(b *Bot, fake *telegramfake.Server, userID int64, text string) []telegramfake.SentMessage {
+	fake.Reset()
+	b.HandleUpdate(tgbotapi.Update{Message: telegramfake.NewMessage(telegramfake.IncomingMessage{
+		UserID:       userID,
+		Text:         text,
+		LanguageCode: "en",
+	})})
+	return fake.Sent()
+}
+
+func TestHandleUpdateStatus(t *testing.T) {
+	repo := &memoryRepository{
+		readings: []models.SensorData{{
+			SensorID:   "temp_kitchen",
+			SensorType: "temperature",
+			Timestamp:  time.Now().UTC(),
+			Value:      models.SensorValue{Data: 21.5},
+			Unit:       "°C",
+		}},
+		prefs: make(map[int64]models.UserPreferences),
+	}
+	b, fake := newTestBot(t, repo)
+
+	tests := []struct {
+		name   string
+		userID int64
+		want   string
+	}{
+		{name: "authorized", userID: testUserID, want: "21.5°C"},
+		{name: "unauthorized", userID: testUserID + 1, want: "not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := command(b, fake, tt.userID, "/status")
+			if len(sent) != 1 {
+				t.Fatalf("got %d messages, want 1", len(sent))
+			}
+			if sent[0].ChatID != tt.userID {
+				t.Errorf("message sent to chat %d, want %d", sent[0].ChatID, tt.userID)
+			}
+			if !strings.Contains(sent[0].Text, tt.want) {
+				t.Errorf("message %q does not contain %q", sent[0].Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateSetThresholdInUserUnits(t *testing.T) {
+	repo := &memoryRepository{
+		readings: []models.SensorData{{
+			SensorID:   "temp_kitchen",
+			SensorType: "temperature",
+			Timestamp:  time.Now().UTC(),
+			Value:      models.SensorValue{Data: 21.5},
+			Unit:       "°C",
+		}},
+		prefs: map[int64]models.UserPreferences{
+			testUserID: {UserID: testUserID, Language: "en", Units: "°F"},
+		},
+	}
+	b, fake := newTestBot(t, repo)
+
+	sent := command(b, fake, testUserID, "/setthreshold temp_kitchen > 80")
+	if len(sent) != 1 {
+		t.Fatalf("got %d messages, want 1", len(sent))
+	}
+	if !strings.Contains(sent[0].Text, "80.0°F") {
+		t.Errorf("confirmation %q does not show the threshold in °F", sent[0].Text)
+	}
+
+	if len(repo.rules) != 1 {
+		t.Fatalf("got %d saved rules, want 1", len(repo.rules))
+	}
+	if got, want := repo.rules[0].Threshold, (80.0-32)*5/9; math.Abs(got-want) > 1e-9 {
+		t.Errorf("saved threshold %v, want %v °C", got, want)
+	}
+}
+
+func TestCheckRuleAlertsNotifiesOnce(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &memoryRepository{
+		readings: []models.SensorData{{
+			SensorID:   "temp_kitchen",
+			SensorType: "temperature",
+			Timestamp:  now.Add(-time.Minute),
+			Value:      models.SensorValue{Data: 30.0},
+			Unit:       "°C",
+		}},
+		prefs: make(map[int64]models.UserPreferences),
+	}
+	b, fake := newTestBot(t, repo)
+	rules := []models.AlertRule{{ID: 1, SensorID: "temp_kitchen", Operator: ">", Threshold: 27}}
+
+	b.checkRuleAlerts(rules)
+	sent := fake.Sent()
+	if len(sent) != 1 || sent[0].ChatID != testUserID {
+		t.Fatalf("got messages %+v, want one alert to user %d", sent, testUserID)
+	}
+	if len(repo.alerts) != 1 {
+		t.Errorf("got %d saved alerts, want 1", len(repo.alerts))
+	}
+
+	// Пока правило остается сработавшим, повторных оповещений нет
+	fake.Reset()
+	b.checkRuleAlerts(rules)
+	if sent := fake.Sent(); len(sent) != 0 {
+		t.Errorf("got %d repeated alerts, want none", len(sent))
+	}
+}
+
+func TestCheckTemperatureAlertsStoresRisingEdge(t *testing.T) {
+	repo := &memoryRepository{
+		readings: []models.SensorData{{
+			SensorID:   "temp_kitchen",
+			SensorType: "temperature",
+			Timestamp:  time.Now().UTC(),
+			Value:      models.SensorValue{Data: 35.0},
+			Unit:       "°C",
+		}},
+		prefs: make(map[int64]models.UserPreferences),
+	}
+	b, fake := newTestBot(t, repo)
+	b.config.TemperatureAlertThreshold = 30
+
+	// Пока температура выше порога, оповещение повторяется, но сохраняется один раз
+	b.checkTemperatureAlerts(nil)
+	b.checkTemperatureAlerts(nil)
+	if sent := fake.Sent(); len(sent) != 2 {
+		t.Errorf("got %d notifications, want 2", len(sent))
+	}
+	if len(repo.alerts) != 1 {
+		t.Fatalf("got %d saved alerts, want 1", len(repo.alerts))
+	}
+
+	// После возврата ниже порога новое превышение сохраняется снова
+	repo.readings[0].Value.Data = 25.0
+	b.checkTemperatureAlerts(nil)
+	repo.readings[0].Value.Data = 35.0
+	b.checkTemperatureAlerts(nil)
+	if len(repo.alerts) != 2 {
+		t.Errorf("got %d saved alerts, want 2", len(repo.alerts))
+	}
+}
+
+func TestNotificationsUseDetectedLanguage(t *testing.T) {
+	repo := &memoryRepository{prefs: make(map[int64]models.UserPreferences)}
+	b, fake := newTestBot(t, repo)
+
+	// До первого сообщения язык пользователя неизвестен
+	b.notifyAllUsers(func(l *i18n.Localizer) string { return l.T("alert.motion", "motion_hall") })
+	if sent := fake.Sent(); len(sent) != 1 || sent[0].Text != i18n.New(i18n.Default).T("alert.motion", "motion_hall") {
+		t.Fatalf("got messages %+v, want one alert in the default language", sent)
+	}
+
+	command(b, fake, testUserID, "/status")
+	if got := repo.prefs[testUserID].Language; got != string(i18n.English) {
+		t.Errorf("saved language %q, want %q", got, i18n.English)
+	}
+
+	fake.Reset()
+	b.notifyAllUsers(func(l *i18n.Localizer) string { return l.T("alert.motion", "motion_hall") })
+	if sent := fake.Sent(); len(sent) != 1 || sent[0].Text != i18n.New(i18n.English).T("alert.motion", "motion_hall") {
+		t.Errorf("got messages %+v, want one alert in English", sent)
+	}
+}
+
+func TestSetThresholdResetsFiringRule(t *testing.T) {
+	repo := &memoryRepository{
+		readings: []models.SensorData{{
+			SensorID:   "temp_kitchen",
+			SensorType: "temperature",
+			Timestamp:  time.Now().UTC(),
+			Value:      models.SensorValue{Data: 30.0},
+			Unit:       "°C",
+		}},
+		prefs: make(map[int64]models.UserPreferences),
+	}
+	b, fake := newTestBot(t, repo)
+
+	command(b, fake, testUserID, "/setthreshold temp_kitchen > 27")
+	fake.Reset()
+	b.checkRuleAlerts(repo.rules)
+	if sent := fake.Sent(); len(sent) != 1 {
+		t.Fatalf("got %d alerts, want 1", len(sent))
+	}
+
+	// Новый порог - новое условие: оно оповещает, не дожидаясь сброса прежнего
+	command(b, fake, testUserID, "/setthreshold temp_kitchen > 28")
+	fake.Reset()
+	b.checkRuleAlerts(repo.rules)
+	if sent := fake.Sent(); len(sent) != 1 {
+		t.Errorf("got %d alerts after changing the threshold, want 1", len(sent))
+	}
+}
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+// localizer возвращает локализатор для пользователя: сохраненный язык и
+// единицы измерения, а если язык не сохранен - язык из профиля Telegram
+// (languageCode). Язык из профиля сохраняется при первом обращении, чтобы
+// рассылки, для которых languageCode неизвестен, шли на том же языке.
+// Настройки кэшируются: пустой язык в кэше означает, что в БД их нет
+func (b *Bot) localizer(userID int64, languageCode string) *i18n.Localizer {
+	b.mu.Lock()
+	lang, ok := b.languages[userID]
+	preference := b.unitPrefs[userID]
+	b.mu.Unlock()
+
+	if !ok {
+		prefs, err := b.repo.GetUserPreferences(userID)
+		if err != nil {
+			// Не кэшируем: при следующем обращении попробуем еще раз
+			b.logger.Printf("Error getting preferences of user %d: %v", userID, err)
+			return i18n.New(i18n.FromLanguageCode(languageCode))
+		}
+		lang, preference = b.cachePreferences(userID, prefs)
+	}
+
+	if lang != "" {
+		return i18n.New(lang).WithUnits(preference)
+	}
+	if languageCode == "" {
+		return i18n.New(i18n.Default)
+	}
+
+	lang = i18n.FromLanguageCode(languageCode)
+	if err := b.savePreferences(userID, lang, nil); err != nil {
+		b.logger.Printf("Error saving detected language of user %d: %v", userID, err)
+	}
+	return i18n.New(lang)
+}
+
+// cachePreferences запоминает настройки пользователя из БД (prefs может
+// быть nil) и возвращает его язык и единицы
+func (b *Bot) cachePreferences(userID int64, prefs *models.UserPreferences) (i18n.Lang, units.Preference) {
+	var lang i18n.Lang
+	var preference units.Preference
+	if prefs != nil {
+		if parsed, ok := i18n.Parse(prefs.Language); ok {
+			lang = parsed
+			var err error
+			if preference, err = units.ParsePreference(prefs.Units); err != nil {
+				b.logger.Printf("Ignoring invalid units of user %d: %v", userID, err)
+			}
+		}
+	}
+
+	b.mu.Lock()
+	b.languages[userID] = lang
+	b.unitPrefs[userID] = preference
+	b.mu.Unlock()
+	return lang, preference
+}
+
+// Localizer возвращает локализатор пользователя для рассылок
+func (b *Bot) Localizer(userID int64) *i18n.Localizer {
+	return b.localizer(userID, "")
+}
+
+// savePreferences сохраняет язык и единицы пользователя
+func (b *Bot) savePreferences(userID int64, lang i18n.Lang, preference units.Preference) error {
+	prefs := models.UserPreferences{UserID: userID, Language: string(lang), Units: preference.String()}
+	if err := b.repo.SaveUserPreferences(prefs); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.languages[userID] = lang
+	b.unitPrefs[userID] = preference
+	b.mu.Unlock()
+	return nil
+}
+
+// handleLanguage показывает или меняет язык интерфейса (/lang [ru|en])
+func (b *Bot) handleLanguage(chatID, userID int64, args string, l *i18n.Localizer) {
+	available := make([]string, 0, len(i18n.Supported()))
+	for _, lang := range i18n.Supported() {
+		available = append(available, string(lang))
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		b.sendMessage(chatID, l.T("lang.current", l.T("lang.name."+string(l.Lang())), strings.Join(available, ", ")))
+		return
+	}
+
+	lang, ok := i18n.Parse(args)
+	if !ok {
+		b.sendMessage(chatID, l.T("lang.unknown", strings.Join(available, ", ")))
+		return
+	}
+
+	if err := b.savePreferences(userID, lang, l.Units()); err != nil {
+		b.logger.Printf("Error saving preferences of user %d: %v", userID, err)
+		b.sendMessage(chatID, l.T("lang.error"))
+		return
+	}
+
+	l = i18n.New(lang).WithUnits(l.Units())
+	b.sendMessage(chatID, l.T("lang.changed", l.T("lang.name."+string(lang))))
+}
+
+// handleUnits показывает или меняет единицы измерения, в которых бот выводит
+// показания (/units [°C|°F] [ppm|mg/m³] или /units default)
+func (b *Bot) handleUnits(chatID, userID int64, args string, l *i18n.Localizer) {
+	available := make([]string, 0, len(units.All()))
+	for _, u := range units.All() {
+		available = append(available, string(u))
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		b.sendMessage(chatID, l.T("units.current", l.Units().For(units.Celsius), l.Units().For(units.PPM), strings.Join(available, ", ")))
+		return
+	}
+
+	var preference units.Preference
+	if !strings.EqualFold(args, "default") {
+		changed, err := units.ParsePreference(args)
+		if err != nil {
+			b.sendMessage(chatID, l.T("units.unknown", args, strings.Join(available, ", ")))
+			return
+		}
+		preference = l.Units().Merge(changed)
+	}
+
+	if err := b.savePreferences(userID, l.Lang(), preference); err != nil {
+		b.logger.Printf("Error saving preferences of user %d: %v", userID, err)
+		b.sendMessage(chatID, l.T("units.error"))
+		return
+	}
+
+	l = l.WithUnits(preference)
+	b.sendMessage(chatID, l.T("units.changed", l.Units().For(units.Celsius), l.Units().For(units.PPM)))
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/report"
+)
+
+// Repository описывает хранилище, с которым работает бот. Реализуется
+// *database.Repository; в тестах бота можно запускать без PostgreSQL.
+// Отсутствующие записи возвращаются как database.ErrNotFound
+type Repository interface {
+	report.Source
+
+	GetLatestReadings() ([]models.SensorData, error)
+	GetLatestReadingsByType(sensorType string) ([]models.SensorData, error)
+	GetReadingHistory(sensorID string, limit int) ([]models.SensorData, error)
+	GetReadingsInRange(sensorID string, from, to time.Time) ([]models.SensorData, error)
+	GetSensorType(sensorID string) (string, error)
+
+	SaveAlert(alert models.Alert) error
+	GetLastAlertID() (int64, error)
+	GetAlertsAfter(afterID int64, alertType string) ([]models.Alert, error)
+
+	GetAlertRules() ([]models.AlertRule, error)
+	SaveAlertRule(rule models.AlertRule) (int64, bool, error)
+	DeleteAlertRule(id int64) error
+
+	GetUserPreferences(userID int64) (*models.UserPreferences, error)
+	SaveUserPreferences(prefs models.UserPreferences) error
+}
+
+var _ Repository = (*database.Repository)(nil)
@@ -0,0 +1,281 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/alerting"
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+// defaultCO2Threshold - порог CO₂ для датчиков без собственных правил
+const defaultCO2Threshold = 1000
+
+// isAdmin проверяет право менять правила оповещений; если администраторы
+// не заданы, правами обладают все авторизованные пользователи
+func (b *Bot) isAdmin(userID int64) bool {
+	if len(b.config.AdminUserIDs) == 0 {
+		return b.isAuthorized(userID)
+	}
+	for _, id := range b.config.AdminUserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSetThreshold создает или изменяет правило (/setthreshold temp_kitchen > 27 for 5m)
+func (b *Bot) handleSetThreshold(chatID, userID int64, args string, l *i18n.Localizer) {
+	if strings.TrimSpace(args) == "" {
+		b.sendMessage(chatID, l.T("rules.usage"))
+		return
+	}
+
+	rule, err := alerting.Parse(args)
+	if err != nil {
+		b.sendMessage(chatID, l.T("rules.invalid", err.Error())+"\n\n"+l.T("rules.usage"))
+		return
+	}
+
+	sensorType, err := b.repo.GetSensorType(rule.SensorID)
+	if errors.Is(err, database.ErrNotFound) {
+		b.sendMessage(chatID, l.T("rules.unknown_sensor", rule.SensorID))
+		return
+	}
+	if err != nil {
+		b.logger.Printf("Error getting type of sensor %s: %v", rule.SensorID, err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	if err := alerting.Validate(rule, sensorType); err != nil {
+		b.sendMessage(chatID, l.T("rules.invalid", err.Error()))
+		return
+	}
+
+	// Порог задан в единицах пользователя, а правила сравнивают его с
+	// показаниями в канонической единице типа
+	if t, ok := sensortype.Lookup(sensorType); ok && t.Unit != "" {
+		rule.Threshold, err = units.Convert(rule.Threshold, l.Units().For(t.Unit), t.Unit, rule.Field)
+		if err != nil {
+			b.sendMessage(chatID, l.T("rules.invalid", err.Error()))
+			return
+		}
+	}
+
+	rule.CreatedBy = userID
+	id, created, err := b.repo.SaveAlertRule(rule)
+	if err != nil {
+		b.logger.Printf("Error saving alert rule: %v", err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	// Состояние срабатывания относится к прежнему порогу: новое условие
+	// оповестит, как только выполнится
+	b.mu.Lock()
+	delete(b.firingRules, id)
+	b.mu.Unlock()
+
+	b.logger.Printf("User %d saved alert rule #%d: %s", userID, id, args)
+
+	key := "rules.updated"
+	if created {
+		key = "rules.created"
+	}
+	b.sendMessage(chatID, l.T(key, id, b.describeRule(rule, sensorType, l)))
+}
+
+// handleRules выводит список правил с номерами (/rules)
+func (b *Bot) handleRules(chatID int64, l *i18n.Localizer) {
+	rules, err := b.repo.GetAlertRules()
+	if err != nil {
+		b.logger.Printf("Error getting alert rules: %v", err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	if len(rules) == 0 {
+		b.sendMessage(chatID, l.T("rules.empty"))
+		return
+	}
+
+	descriptions, err := b.describeRules(rules, l)
+	if err != nil {
+		b.logger.Printf("Error describing alert rules: %v", err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(l.T("rules.title"))
+	for i, rule := range rules {
+		sb.WriteString(l.T("rules.item", rule.ID, descriptions[i]))
+	}
+
+	b.sendMessage(chatID, sb.String())
+}
+
+// handleThresholds выводит действующие пороги по датчикам (/thresholds)
+func (b *Bot) handleThresholds(chatID int64, l *i18n.Localizer) {
+	rules, err := b.repo.GetAlertRules()
+	if err != nil {
+		b.logger.Printf("Error getting alert rules: %v", err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	descriptions, err := b.describeRules(rules, l)
+	if err != nil {
+		b.logger.Printf("Error describing alert rules: %v", err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(l.T("thresholds.title"))
+	for _, description := range descriptions {
+		sb.WriteString(l.T("thresholds.item", description))
+	}
+	sb.WriteString(l.T("thresholds.default_temperature", l.Quantity(b.config.TemperatureAlertThreshold, units.Celsius, "")))
+	sb.WriteString(l.T("thresholds.default_co2", l.Quantity(defaultCO2Threshold, units.PPM, "co2")))
+
+	b.sendMessage(chatID, sb.String())
+}
+
+// handleDeleteRule удаляет правило по номеру (/deleterule 3)
+func (b *Bot) handleDeleteRule(chatID, userID int64, args string, l *i18n.Localizer) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(args), "#"), 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, l.T("rules.delete_usage"))
+		return
+	}
+
+	err = b.repo.DeleteAlertRule(id)
+	if errors.Is(err, database.ErrNotFound) {
+		b.sendMessage(chatID, l.T("rules.not_found", id))
+		return
+	}
+	if err != nil {
+		b.logger.Printf("Error deleting alert rule #%d: %v", id, err)
+		b.sendMessage(chatID, l.T("rules.error"))
+		return
+	}
+
+	b.mu.Lock()
+	delete(b.firingRules, id)
+	b.mu.Unlock()
+
+	b.logger.Printf("User %d deleted alert rule #%d", userID, id)
+	b.sendMessage(chatID, l.T("rules.deleted", id))
+}
+
+// describeRules описывает правила по типам их датчиков; правило датчика
+// без показаний выводится без порога, так как его тип неизвестен
+func (b *Bot) describeRules(rules []models.AlertRule, l *i18n.Localizer) ([]string, error) {
+	sensorTypes := make(map[string]string)
+	descriptions := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		sensorType, ok := sensorTypes[rule.SensorID]
+		if !ok {
+			var err error
+			sensorType, err = b.repo.GetSensorType(rule.SensorID)
+			if err != nil && !errors.Is(err, database.ErrNotFound) {
+				return nil, fmt.Errorf("failed to get type of sensor %s: %w", rule.SensorID, err)
+			}
+			sensorTypes[rule.SensorID] = sensorType
+		}
+
+		if sensorType == "" {
+			descriptions = append(descriptions, l.T("rule.unknown_type", rule.SensorID))
+			continue
+		}
+		descriptions = append(descriptions, b.describeRule(rule, sensorType, l))
+	}
+	return descriptions, nil
+}
+
+// describeRule формирует читаемое описание правила
+func (b *Bot) describeRule(rule models.AlertRule, sensorType string, l *i18n.Localizer) string {
+	suffix := ""
+	if rule.Duration > 0 {
+		suffix = l.T("rule.for", l.Period(rule.Duration))
+	}
+
+	t, _ := sensortype.Lookup(sensorType)
+	if t.Value.Kind == models.KindBool {
+		key := "rule.motion_clear"
+		if rule.Threshold == 1 {
+			key = "rule.motion_detected"
+		}
+		return l.T(key, rule.SensorID) + suffix
+	}
+
+	quantity := l.T("quantity." + sensorType)
+	if rule.Field != "" {
+		quantity = l.T("quantity." + rule.Field)
+	}
+	value := l.Quantity(rule.Threshold, t.Unit, rule.Field)
+
+	return l.T("rule.describe", rule.SensorID, quantity, rule.Operator, value) + suffix
+}
+
+// checkRuleAlerts проверяет правила и оповещает при переходе правила
+// в сработавшее состояние
+func (b *Bot) checkRuleAlerts(rules []models.AlertRule) {
+	now := time.Now().UTC()
+
+	for _, rule := range rules {
+		var readings []models.SensorData
+		var err error
+		if rule.Duration > 0 {
+			// Окно берется с запасом: нужно показание не позже начала
+			// длительности правила
+			readings, err = b.repo.GetReadingsInRange(rule.SensorID, now.Add(-2*rule.Duration), now)
+		} else {
+			readings, err = b.repo.GetReadingHistory(rule.SensorID, 1)
+		}
+		if err != nil {
+			b.logger.Printf("Error getting readings for alert rule #%d: %v", rule.ID, err)
+			continue
+		}
+
+		holds := alerting.Holds(rule, readings, now)
+
+		b.mu.Lock()
+		wasFiring := b.firingRules[rule.ID]
+		b.firingRules[rule.ID] = holds
+		b.mu.Unlock()
+
+		if !holds || wasFiring {
+			continue
+		}
+
+		rule := rule
+		latest := readings[len(readings)-1]
+		sensorType := latest.SensorType
+		b.raiseAlert(rule.SensorID, "rule", func(l *i18n.Localizer) string {
+			return l.T("alert.rule", rule.ID, b.describeRule(rule, sensorType, l), b.formatSensorValue(latest, l))
+		})
+	}
+}
+
+// overriddenSensors возвращает датчики, для которых правила заменяют
+// порог по умолчанию для указанного поля
+func overriddenSensors(rules []models.AlertRule, field string) map[string]bool {
+	sensors := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Field == field {
+			sensors[rule.SensorID] = true
+		}
+	}
+	return sensors
+}
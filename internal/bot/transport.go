@@ -0,0 +1,18 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Transport описывает канал обмена сообщениями с Telegram Bot API.
+// Реализуется *tgbotapi.BotAPI; в тестах и при офлайн-разработке
+// его можно направить на локальный сервер из пакета telegramfake
+type Transport interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	StopReceivingUpdates()
+}
+
+var _ Transport = (*tgbotapi.BotAPI)(nil)
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Режимы получения обновлений
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// secretTokenHeader - заголовок, в котором Telegram передает секрет вебхука
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// startWebhook регистрирует вебхук в Telegram и принимает обновления
+// встроенным HTTP-сервером
+func (b *Bot) startWebhook() error {
+	webhookURL, err := url.Parse(b.config.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	// В tgbotapi v5.5.1 нет поля secret_token, поэтому вызываем метод напрямую
+	params := tgbotapi.Params{"url": webhookURL.String()}
+	params.AddNonEmpty("secret_token", b.config.WebhookSecret)
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	path := webhookURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, b.WebhookHandler())
+
+	server := &http.Server{
+		Addr:    b.config.WebhookListenAddr,
+		Handler: mux,
+	}
+
+	b.mu.Lock()
+	b.webhookServer = server
+	b.mu.Unlock()
+
+	b.logger.Printf("Bot %s started successfully in webhook mode, listening on %s%s",
+		b.name, b.config.WebhookListenAddr, path)
+
+	if b.config.WebhookCertFile != "" {
+		err = server.ListenAndServeTLS(b.config.WebhookCertFile, b.config.WebhookKeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("webhook server error: %w", err)
+	}
+
+	return nil
+}
+
+// WebhookHandler возвращает HTTP-обработчик обновлений, который проверяет
+// секрет вебхука и передает обновление в HandleUpdate
+func (b *Bot) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(b.config.WebhookSecret)) != 1 {
+			b.logger.Printf("Rejected webhook request from %s: invalid secret token", r.RemoteAddr)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			b.logger.Printf("Error decoding webhook update: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		b.HandleUpdate(update)
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,438 @@
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Kind определяет способ отрисовки графика
+type Kind int
+
+const (
+	// Line - линейный график (одна или несколько серий)
+	Line Kind = iota
+	// Occupancy - столбцы занятости для булевых значений (движение)
+	Occupancy
+)
+
+// Размеры изображения по умолчанию
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 400
+)
+
+// MaxSize - наибольшая ширина и высота изображения, которые можно запросить
+const MaxSize = 4000
+
+// Наибольший период истории, который можно запросить
+const (
+	MaxPeriodDays = 365
+	MaxPeriod     = MaxPeriodDays * 24 * time.Hour
+)
+
+// Point представляет одну точку серии
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series представляет именованный набор точек
+type Series struct {
+	Name   string
+	Color  color.RGBA
+	Points []Point
+}
+
+// Chart описывает график для отрисовки
+type Chart struct {
+	Title  string
+	Unit   string
+	Kind   Kind
+	Series []Series
+	From   time.Time
+	To     time.Time
+	Width  int
+	Height int
+}
+
+// Errors
+var (
+	ErrNoData = errors.New("no data to render")
+)
+
+var (
+	colorBackground = color.RGBA{255, 255, 255, 255}
+	colorAxis       = color.RGBA{60, 60, 60, 255}
+	colorGrid       = color.RGBA{225, 225, 225, 255}
+	colorText       = color.RGBA{30, 30, 30, 255}
+
+	// Palette - цвета серий по умолчанию
+	Palette = []color.RGBA{
+		{214, 39, 40, 255},
+		{31, 119, 180, 255},
+		{44, 160, 44, 255},
+		{255, 127, 14, 255},
+	}
+)
+
+const (
+	marginLeft   = 60
+	marginRight  = 20
+	marginTop    = 36
+	marginBottom = 40
+	yTicks       = 5
+	xTicks       = 6
+)
+
+// Render рисует график и записывает его в w в формате PNG
+func Render(w io.Writer, c Chart) error {
+	img, err := Draw(c)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// Draw рисует график в изображение
+func Draw(c Chart) (*image.RGBA, error) {
+	if !c.hasPoints() {
+		return nil, ErrNoData
+	}
+
+	width, height := c.Width, c.Height
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+	if width < marginLeft+marginRight+50 || height < marginTop+marginBottom+50 {
+		return nil, fmt.Errorf("chart size %dx%d is too small", width, height)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+
+	from, to := c.timeRange()
+	minY, maxY := c.valueRange()
+
+	p := &plotter{img: img, area: plot, from: from, to: to, minY: minY, maxY: maxY}
+
+	p.drawGrid(c.Kind == Occupancy)
+
+	switch c.Kind {
+	case Occupancy:
+		for _, s := range c.Series {
+			p.drawOccupancy(s)
+		}
+	default:
+		for _, s := range c.Series {
+			p.drawLine(s)
+		}
+	}
+
+	p.drawAxes()
+
+	title := c.Title
+	if c.Unit != "" {
+		title = fmt.Sprintf("%s, %s", title, c.Unit)
+	}
+	drawText(img, marginLeft, 20, title, colorText)
+
+	if len(c.Series) > 1 {
+		p.drawLegend(c.Series)
+	}
+
+	return img, nil
+}
+
+func (c Chart) hasPoints() bool {
+	for _, s := range c.Series {
+		if len(s.Points) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// timeRange возвращает временной интервал графика
+func (c Chart) timeRange() (time.Time, time.Time) {
+	from, to := c.From, c.To
+	if from.IsZero() || to.IsZero() {
+		for _, s := range c.Series {
+			for _, pt := range s.Points {
+				if c.From.IsZero() && (from.IsZero() || pt.Time.Before(from)) {
+					from = pt.Time
+				}
+				if c.To.IsZero() && (to.IsZero() || pt.Time.After(to)) {
+					to = pt.Time
+				}
+			}
+		}
+	}
+	if !to.After(from) {
+		to = from.Add(time.Minute)
+	}
+	return from, to
+}
+
+// valueRange возвращает диапазон значений с небольшим запасом
+func (c Chart) valueRange() (float64, float64) {
+	if c.Kind == Occupancy {
+		return 0, 1
+	}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, s := range c.Series {
+		for _, pt := range s.Points {
+			minY = math.Min(minY, pt.Value)
+			maxY = math.Max(maxY, pt.Value)
+		}
+	}
+
+	if minY == maxY {
+		return minY - 1, maxY + 1
+	}
+
+	pad := (maxY - minY) * 0.05
+	return minY - pad, maxY + pad
+}
+
+type plotter struct {
+	img        *image.RGBA
+	area       image.Rectangle
+	from, to   time.Time
+	minY, maxY float64
+}
+
+func (p *plotter) x(t time.Time) int {
+	span := p.to.Sub(p.from).Seconds()
+	pos := t.Sub(p.from).Seconds() / span
+	return p.area.Min.X + int(math.Round(pos*float64(p.area.Dx())))
+}
+
+func (p *plotter) y(v float64) int {
+	pos := (v - p.minY) / (p.maxY - p.minY)
+	return p.area.Max.Y - int(math.Round(pos*float64(p.area.Dy())))
+}
+
+func (p *plotter) drawGrid(occupancy bool) {
+	for i := 0; i <= yTicks; i++ {
+		v := p.minY + (p.maxY-p.minY)*float64(i)/yTicks
+		y := p.y(v)
+		hLine(p.img, p.area.Min.X, p.area.Max.X, y, colorGrid)
+
+		if occupancy && i != 0 && i != yTicks {
+			continue
+		}
+		label := formatTick(v, p.maxY-p.minY)
+		if occupancy {
+			label = map[bool]string{true: "on", false: "off"}[i == yTicks]
+		}
+		drawText(p.img, p.area.Min.X-8-textWidth(label), y+4, label, colorText)
+	}
+
+	layout := tickLayout(p.to.Sub(p.from))
+	for i := 0; i <= xTicks; i++ {
+		t := p.from.Add(time.Duration(float64(p.to.Sub(p.from)) * float64(i) / xTicks))
+		x := p.x(t)
+		vLine(p.img, x, p.area.Min.Y, p.area.Max.Y, colorGrid)
+
+		label := t.Format(layout)
+		drawText(p.img, x-textWidth(label)/2, p.area.Max.Y+18, label, colorText)
+	}
+}
+
+func (p *plotter) drawAxes() {
+	hLine(p.img, p.area.Min.X, p.area.Max.X, p.area.Max.Y, colorAxis)
+	vLine(p.img, p.area.Min.X, p.area.Min.Y, p.area.Max.Y, colorAxis)
+}
+
+func (p *plotter) drawLine(s Series) {
+	points := sortedPoints(s.Points)
+	if len(points) == 1 {
+		x, y := p.x(points[0].Time), p.y(points[0].Value)
+		fillRect(p.img, image.Rect(x-2, y-2, x+3, y+3), s.Color)
+		return
+	}
+
+	for i := 1; i < len(points); i++ {
+		x0, y0 := p.x(points[i-1].Time), p.y(points[i-1].Value)
+		x1, y1 := p.x(points[i].Time), p.y(points[i].Value)
+		thickLine(p.img, x0, y0, x1, y1, s.Color)
+	}
+}
+
+// drawOccupancy закрашивает интервалы, в которых значение было ненулевым
+func (p *plotter) drawOccupancy(s Series) {
+	points := sortedPoints(s.Points)
+	fill := s.Color
+	fill.A = 180
+
+	for i, pt := range points {
+		if pt.Value == 0 {
+			continue
+		}
+		end := p.to
+		if i+1 < len(points) {
+			end = points[i+1].Time
+		}
+		x0, x1 := p.x(pt.Time), p.x(end)
+		if x1 <= x0 {
+			x1 = x0 + 1
+		}
+		fillRect(p.img, image.Rect(x0, p.y(1), x1, p.area.Max.Y), fill)
+	}
+}
+
+func (p *plotter) drawLegend(series []Series) {
+	x := p.area.Max.X
+	for i := len(series) - 1; i >= 0; i-- {
+		s := series[i]
+		x -= textWidth(s.Name) + 24
+		fillRect(p.img, image.Rect(x, 12, x+12, 22), s.Color)
+		drawText(p.img, x+16, 21, s.Name, colorText)
+	}
+}
+
+func sortedPoints(points []Point) []Point {
+	sorted := make([]Point, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	return sorted
+}
+
+// tickLayout выбирает формат подписей оси времени в зависимости от периода
+func tickLayout(span time.Duration) string {
+	switch {
+	case span <= 24*time.Hour:
+		return "15:04"
+	case span <= 7*24*time.Hour:
+		return "02.01 15h"
+	default:
+		return "02.01"
+	}
+}
+
+func formatTick(v, span float64) string {
+	switch {
+	case span >= 50:
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	case span >= 5:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	default:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+}
+
+// ParsePeriod разбирает период вида "30m", "24h" или "7d" не длиннее MaxPeriod
+func ParsePeriod(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("invalid period %q", s)
+		}
+		// Проверяем число дней до умножения, чтобы не переполнить Duration
+		if days > MaxPeriodDays {
+			return 0, fmt.Errorf("period %q exceeds %dd", s, MaxPeriodDays)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+	if d > MaxPeriod {
+		return 0, fmt.Errorf("period %q exceeds %dd", s, MaxPeriodDays)
+	}
+	return d, nil
+}
+
+// Примитивы рисования
+
+func hLine(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	for x := x0; x <= x1; x++ {
+		img.SetRGBA(x, y, c)
+	}
+}
+
+func vLine(img *image.RGBA, x, y0, y1 int, c color.RGBA) {
+	for y := y0; y <= y1; y++ {
+		img.SetRGBA(x, y, c)
+	}
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Over)
+}
+
+// thickLine рисует линию толщиной 2 пикселя алгоритмом Брезенхэма
+func thickLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+
+	for {
+		img.SetRGBA(x0, y0, c)
+		img.SetRGBA(x0+1, y0, c)
+		img.SetRGBA(x0, y0+1, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{c},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+func textWidth(text string) int {
+	return font.MeasureString(basicfont.Face7x13, text).Round()
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
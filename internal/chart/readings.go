@@ -0,0 +1,74 @@
+package chart
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+)
+
+// fieldColors - порядок цветов палитры для полей составных значений
+var fieldColors = []int{1, 3, 0, 2}
+
+// unitReplacer заменяет символы единиц измерения, которых нет в шрифте графика
+var unitReplacer = strings.NewReplacer("°", "", "³", "3", "µ", "u")
+
+// FromReadings строит график по показаниям датчика за период [from, to];
+// серии графика задает схема значения типа датчика. Показания должны быть в
+// одной единице
+func FromReadings(sensorID, sensorType string, readings []models.SensorData, from, to time.Time) (Chart, error) {
+	t, ok := sensortype.Lookup(sensorType)
+	if !ok {
+		return Chart{}, fmt.Errorf("unsupported sensor type %q", sensorType)
+	}
+
+	c := Chart{
+		Title: sensorID,
+		From:  from,
+		To:    to,
+	}
+
+	// Единица - каноническая для типа или та, в которую переведены показания
+	unit := string(t.Unit)
+	if len(readings) > 0 && readings[0].Unit != "" {
+		unit = readings[0].Unit
+	}
+	c.Unit = unitReplacer.Replace(unit)
+
+	switch t.Value.Kind {
+	case models.KindNumber:
+		c.Series = []Series{{Name: sensorType, Color: Palette[0]}}
+	case models.KindObject:
+		for i, field := range t.Value.Fields {
+			c.Series = append(c.Series, Series{Name: strings.ToUpper(field), Color: Palette[fieldColors[i%len(fieldColors)]]})
+		}
+	case models.KindBool:
+		c.Kind = Occupancy
+		c.Unit = ""
+		c.Series = []Series{{Name: sensorType, Color: Palette[2]}}
+	default:
+		return Chart{}, fmt.Errorf("unsupported sensor type %q", sensorType)
+	}
+
+	// Показания, не соответствующие схеме типа, пропускаются
+	fields := t.Value.FieldNames()
+	for _, r := range readings {
+		value, err := t.Value.Decode(r.Value.Data)
+		if err != nil {
+			continue
+		}
+		for i, field := range fields {
+			if v, ok := value.Field(field); ok {
+				c.Series[i].Points = append(c.Series[i].Points, Point{Time: r.Timestamp, Value: v})
+			}
+		}
+	}
+
+	if !c.hasPoints() {
+		return Chart{}, ErrNoData
+	}
+
+	return c, nil
+}
@@ -0,0 +1,128 @@
+// Package climate поддерживает заданную температуру в комнатах: по расписанию
+// уставок и показаниям датчиков управляет обогревателями и кондиционерами с
+// помощью гистерезисного или ПИД-регулятора
+package climate
+
+import (
+	"math"
+	"time"
+)
+
+// Режимы работы устройства
+const (
+	ModeIdle = "idle"
+	ModeHeat = "heat"
+	ModeCool = "cool"
+)
+
+// Алгоритмы регулирования
+const (
+	AlgorithmHysteresis = "hysteresis"
+	AlgorithmPID        = "pid"
+)
+
+// Output - управляющее воздействие регулятора
+type Output struct {
+	Mode  string  // Режим устройства (idle, heat, cool)
+	Power float64 // Мощность в процентах от номинальной
+}
+
+// Controller вычисляет управляющее воздействие по температуре и уставке
+type Controller interface {
+	Update(temperature, setpoint float64, dt time.Duration) Output
+	Reset()
+}
+
+// Hysteresis - двухпозиционный регулятор: включает нагрев, когда температура
+// опускается ниже setpoint-Band, и охлаждение выше setpoint+Band; внутри зоны
+// нечувствительности сохраняет текущий режим до достижения уставки
+type Hysteresis struct {
+	Band    float64
+	Heating bool
+	Cooling bool
+
+	mode string
+}
+
+// Update реализует Controller
+func (h *Hysteresis) Update(temperature, setpoint float64, _ time.Duration) Output {
+	switch {
+	case h.Heating && temperature < setpoint-h.Band:
+		h.mode = ModeHeat
+	case h.Cooling && temperature > setpoint+h.Band:
+		h.mode = ModeCool
+	case h.mode == ModeHeat && temperature >= setpoint:
+		h.mode = ModeIdle
+	case h.mode == ModeCool && temperature <= setpoint:
+		h.mode = ModeIdle
+	case h.mode == "":
+		h.mode = ModeIdle
+	}
+
+	if h.mode == ModeIdle {
+		return Output{Mode: ModeIdle}
+	}
+	return Output{Mode: h.mode, Power: 100}
+}
+
+// Reset реализует Controller
+func (h *Hysteresis) Reset() {
+	h.mode = ModeIdle
+}
+
+// PID - ПИД-регулятор. Положительный выход означает нагрев, отрицательный -
+// охлаждение; выход ограничен диапазоном [-100, 100], интегральная
+// составляющая не накапливается при насыщении
+type PID struct {
+	Kp, Ki, Kd float64
+	Heating    bool
+	Cooling    bool
+
+	integral  float64
+	prevError float64
+	hasPrev   bool
+}
+
+// Update реализует Controller
+func (p *PID) Update(temperature, setpoint float64, dt time.Duration) Output {
+	err := setpoint - temperature
+	seconds := dt.Seconds()
+
+	derivative := 0.0
+	if p.hasPrev && seconds > 0 {
+		derivative = (err - p.prevError) / seconds
+	}
+	p.prevError, p.hasPrev = err, true
+
+	integral := p.integral + err*seconds
+	output := p.Kp*err + p.Ki*integral + p.Kd*derivative
+
+	min, max := -100.0, 100.0
+	if !p.Cooling {
+		min = 0
+	}
+	if !p.Heating {
+		max = 0
+	}
+
+	clamped := math.Max(min, math.Min(max, output))
+	// Интеграл обновляется, только если выход не упирается в ограничение
+	// (или интеграл уменьшает насыщение)
+	if clamped == output || (output > max && err < 0) || (output < min && err > 0) {
+		p.integral = integral
+	}
+
+	switch {
+	case clamped > 0.5:
+		return Output{Mode: ModeHeat, Power: math.Round(clamped)}
+	case clamped < -0.5:
+		return Output{Mode: ModeCool, Power: math.Round(-clamped)}
+	default:
+		return Output{Mode: ModeIdle}
+	}
+}
+
+// Reset реализует Controller
+func (p *PID) Reset() {
+	p.integral, p.prevError, p.hasPrev = 0, 0, false
+}
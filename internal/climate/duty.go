@@ -0,0 +1,60 @@
+package climate
+
+import "time"
+
+// dutySample - мощность (доля от 0 до 1), действующая с момента t
+type dutySample struct {
+	t        time.Time
+	fraction float64
+}
+
+// dutyTracker рассчитывает коэффициент заполнения: средневзвешенную по времени
+// долю мощности устройства за скользящее окно
+type dutyTracker struct {
+	window  time.Duration
+	samples []dutySample
+}
+
+func (d *dutyTracker) add(now time.Time, fraction float64) {
+	d.samples = append(d.samples, dutySample{t: now, fraction: fraction})
+
+	// Оставляем последний отсчет перед началом окна: он определяет мощность
+	// в начале окна
+	start := now.Add(-d.window)
+	drop := 0
+	for drop+1 < len(d.samples) && !d.samples[drop+1].t.After(start) {
+		drop++
+	}
+	d.samples = d.samples[drop:]
+}
+
+func (d *dutyTracker) value(now time.Time) float64 {
+	if len(d.samples) == 0 {
+		return 0
+	}
+
+	start := now.Add(-d.window)
+	if d.samples[0].t.After(start) {
+		start = d.samples[0].t
+	}
+	total := now.Sub(start)
+	if total <= 0 {
+		return d.samples[len(d.samples)-1].fraction
+	}
+
+	var weighted float64
+	for i, s := range d.samples {
+		from := s.t
+		if from.Before(start) {
+			from = start
+		}
+		to := now
+		if i+1 < len(d.samples) {
+			to = d.samples[i+1].t
+		}
+		if to.After(from) {
+			weighted += s.fraction * to.Sub(from).Seconds()
+		}
+	}
+	return weighted / total.Seconds()
+}
@@ -0,0 +1,48 @@
+package climate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	setpointGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "climate_setpoint_celsius",
+			Help: "Текущая уставка температуры в комнате",
+		},
+		[]string{"room"},
+	)
+
+	roomTemperatureGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "climate_temperature_celsius",
+			Help: "Температура в комнате, по которой работает регулятор",
+		},
+		[]string{"room"},
+	)
+
+	outputGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "climate_output_percent",
+			Help: "Мощность устройства в процентах (нагрев положительный, охлаждение отрицательное)",
+		},
+		[]string{"room"},
+	)
+
+	dutyCycleGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "climate_duty_cycle",
+			Help: "Коэффициент заполнения устройства за окно усреднения (0..1)",
+		},
+		[]string{"room"},
+	)
+
+	modeGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "climate_mode",
+			Help: "Текущий режим устройства (1 - активен)",
+		},
+		[]string{"room", "mode"},
+	)
+)
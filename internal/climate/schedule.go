@@ -0,0 +1,65 @@
+package climate
+
+import (
+	"sort"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+)
+
+// setpointEntry - разобранная запись расписания уставок
+type setpointEntry struct {
+	offset   time.Duration // Смещение от полуночи
+	days     map[time.Weekday]bool
+	setpoint float64
+}
+
+// Schedule определяет уставку температуры в зависимости от времени
+type Schedule struct {
+	entries  []setpointEntry
+	fallback float64
+}
+
+// NewSchedule создает расписание; fallback действует, пока не началась ни одна запись
+func NewSchedule(cfgs []config.SetpointConfig, fallback float64) Schedule {
+	s := Schedule{fallback: fallback}
+	for _, cfg := range cfgs {
+		offset, _ := config.ParseClock(cfg.At)
+		entry := setpointEntry{
+			offset:   offset,
+			setpoint: cfg.Setpoint,
+		}
+		if len(cfg.Days) > 0 {
+			entry.days = make(map[time.Weekday]bool)
+			for _, day := range cfg.Days {
+				if wd, ok := config.ParseWeekday(day); ok {
+					entry.days[wd] = true
+				}
+			}
+		}
+		s.entries = append(s.entries, entry)
+	}
+
+	// Поздние записи проверяются первыми
+	sort.SliceStable(s.entries, func(i, j int) bool { return s.entries[i].offset > s.entries[j].offset })
+	return s
+}
+
+// Setpoint возвращает уставку, действующую в момент t: последнюю начавшуюся
+// запись за прошедшую неделю
+func (s Schedule) Setpoint(t time.Time) float64 {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for back := 0; back <= 7; back++ {
+		day := midnight.AddDate(0, 0, -back)
+		for _, e := range s.entries {
+			if e.days != nil && !e.days[day.Weekday()] {
+				continue
+			}
+			if back == 0 && day.Add(e.offset).After(t) {
+				continue
+			}
+			return e.setpoint
+		}
+	}
+	return s.fallback
+}
@@ -0,0 +1,348 @@
+package climate
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/devices"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+)
+
+// SourceClimate - источник команд, отправленных регулятором температуры
+const SourceClimate = "climate"
+
+// Значения по умолчанию
+const (
+	defaultInterval   = 30 * time.Second
+	defaultHysteresis = 0.5
+	defaultStaleAfter = 5 * time.Minute
+	defaultDutyWindow = time.Hour
+)
+
+// ErrRoomNotFound возвращается для комнаты без регулятора
+var ErrRoomNotFound = errors.New("climate room not found")
+
+// modeSupporter реализуется устройствами, которые поддерживают не все режимы
+type modeSupporter interface {
+	Supports(mode string) bool
+}
+
+// Status - текущее состояние регулятора комнаты
+type Status struct {
+	Room        string    `json:"room"`
+	Device      string    `json:"device"`
+	Algorithm   string    `json:"algorithm"`
+	Mode        string    `json:"mode"`                  // idle, heat или cool
+	Setpoint    float64   `json:"setpoint"`              // Текущая уставка, °C
+	Temperature *float64  `json:"temperature,omitempty"` // Средняя температура по датчикам комнаты, °C
+	Power       float64   `json:"power"`                 // Мощность устройства, %
+	DutyCycle   float64   `json:"dutyCycle"`             // Коэффициент заполнения за окно усреднения (0..1)
+	Sensors     []string  `json:"sensors"`               // Датчики, использованные в последнем цикле
+	Error       string    `json:"error,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// room - регулятор одной комнаты
+type room struct {
+	cfg        config.ClimateRoomConfig
+	controller Controller
+	schedule   Schedule
+	duty       dutyTracker
+	lastUpdate time.Time
+	status     Status
+}
+
+// Service периодически регулирует температуру во всех настроенных комнатах
+type Service struct {
+	rooms    []*room
+	repo     *database.Repository
+	devices  *devices.Manager
+	interval time.Duration
+	location *time.Location
+	logger   *log.Logger
+
+	mu       sync.Mutex
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService создает регуляторы для комнат из конфигурации
+func NewService(cfg config.ClimateConfig, repo *database.Repository, deviceManager *devices.Manager, logger *log.Logger) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid climate config: %w", err)
+	}
+
+	s := &Service{
+		repo:     repo,
+		devices:  deviceManager,
+		interval: cfg.Interval,
+		location: time.UTC,
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+	if s.interval == 0 {
+		s.interval = defaultInterval
+	}
+	if cfg.Timezone != "" {
+		s.location, _ = time.LoadLocation(cfg.Timezone)
+	}
+
+	for _, rc := range cfg.Rooms {
+		device, err := deviceManager.Get(rc.Device)
+		if err != nil {
+			return nil, fmt.Errorf("climate room %q: %w", rc.Room, err)
+		}
+		if device.Type() != "hvac" {
+			return nil, fmt.Errorf("climate room %q: device %s is a %s, not an hvac device", rc.Room, rc.Device, device.Type())
+		}
+
+		heating, cooling := true, true
+		if ms, ok := device.(modeSupporter); ok {
+			heating, cooling = ms.Supports(ModeHeat), ms.Supports(ModeCool)
+		}
+
+		if rc.Algorithm == "" {
+			rc.Algorithm = AlgorithmHysteresis
+		}
+		if rc.Hysteresis == 0 {
+			rc.Hysteresis = defaultHysteresis
+		}
+		if rc.StaleAfter == 0 {
+			rc.StaleAfter = defaultStaleAfter
+		}
+		if rc.DutyWindow == 0 {
+			rc.DutyWindow = defaultDutyWindow
+		}
+
+		var controller Controller
+		switch rc.Algorithm {
+		case AlgorithmPID:
+			controller = &PID{Kp: rc.PID.Kp, Ki: rc.PID.Ki, Kd: rc.PID.Kd, Heating: heating, Cooling: cooling}
+		default:
+			controller = &Hysteresis{Band: rc.Hysteresis, Heating: heating, Cooling: cooling}
+		}
+
+		s.rooms = append(s.rooms, &room{
+			cfg:        rc,
+			controller: controller,
+			schedule:   NewSchedule(rc.Schedule, rc.DefaultSetpoint),
+			duty:       dutyTracker{window: rc.DutyWindow},
+			status: Status{
+				Room:      rc.Room,
+				Device:    rc.Device,
+				Algorithm: rc.Algorithm,
+				Mode:      ModeIdle,
+			},
+		})
+	}
+
+	return s, nil
+}
+
+// Start запускает цикл регулирования
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.logger.Printf("Starting climate control for %d rooms with interval %s", len(s.rooms), s.interval)
+
+		s.Step(time.Now())
+		for {
+			select {
+			case now := <-ticker.C:
+				s.Step(now)
+			case <-s.stopChan:
+				s.logger.Println("Stopping climate control")
+				return
+			}
+		}
+	}()
+}
+
+// Stop останавливает цикл регулирования
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+}
+
+// Status возвращает состояние регуляторов всех комнат
+func (s *Service) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Status, 0, len(s.rooms))
+	for _, r := range s.rooms {
+		result = append(result, r.status)
+	}
+	return result
+}
+
+// RoomStatus возвращает состояние регулятора комнаты
+func (s *Service) RoomStatus(name string) (Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.rooms {
+		if r.cfg.Room == name {
+			return r.status, nil
+		}
+	}
+	return Status{}, fmt.Errorf("%w: %s", ErrRoomNotFound, name)
+}
+
+// Step выполняет один цикл регулирования для всех комнат
+func (s *Service) Step(now time.Time) {
+	readings, err := s.repo.GetLatestReadingsByType("temperature")
+	if err != nil {
+		s.logger.Printf("Error getting temperature readings for climate control: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.rooms {
+		s.stepRoom(r, readings, now)
+	}
+}
+
+func (s *Service) stepRoom(r *room, readings []models.SensorData, now time.Time) {
+	setpoint := r.schedule.Setpoint(now.In(s.location))
+	temperature, sensors := roomTemperature(r.cfg, readings, now)
+
+	status := Status{
+		Room:      r.cfg.Room,
+		Device:    r.cfg.Device,
+		Algorithm: r.cfg.Algorithm,
+		Setpoint:  setpoint,
+		Sensors:   sensors,
+		UpdatedAt: now.UTC(),
+	}
+
+	var output Output
+	if len(sensors) == 0 {
+		// Без свежих показаний регулировать нельзя: выключаем устройство
+		r.controller.Reset()
+		r.lastUpdate = time.Time{}
+		output = Output{Mode: ModeIdle}
+		status.Error = "no fresh temperature readings"
+	} else {
+		dt := s.interval
+		if !r.lastUpdate.IsZero() {
+			dt = now.Sub(r.lastUpdate)
+		}
+		r.lastUpdate = now
+		output = r.controller.Update(temperature, setpoint, dt)
+		status.Temperature = &temperature
+	}
+
+	if err := s.apply(r, output, setpoint); err != nil {
+		s.logger.Printf("Error driving %s for room %s: %v", r.cfg.Device, r.cfg.Room, err)
+		status.Error = err.Error()
+	}
+
+	status.Mode = output.Mode
+	status.Power = output.Power
+	r.duty.add(now, output.Power/100)
+	status.DutyCycle = math.Round(r.duty.value(now)*1000) / 1000
+	r.status = status
+
+	updateMetrics(status)
+}
+
+// apply переводит устройство в нужный режим, отправляя только изменившиеся параметры
+func (s *Service) apply(r *room, output Output, setpoint float64) error {
+	device, err := s.devices.Get(r.cfg.Device)
+	if err != nil {
+		return err
+	}
+	state := device.State()
+
+	mode := "off"
+	if output.Mode != ModeIdle {
+		mode = output.Mode
+	}
+
+	if output.Mode != ModeIdle {
+		if power, _ := state["power"].(float64); power != output.Power {
+			cmd := models.Command{Name: models.CommandSetPower, Value: output.Power}
+			if _, err := s.devices.Execute(r.cfg.Device, cmd, SourceClimate); err != nil {
+				return err
+			}
+		}
+	}
+
+	if current, _ := state["mode"].(string); current != mode {
+		cmd := models.Command{Name: models.CommandSetMode, Value: mode}
+		if _, err := s.devices.Execute(r.cfg.Device, cmd, SourceClimate); err != nil {
+			return err
+		}
+		s.logger.Printf("Climate %s: %s -> %s (setpoint %.1f)", r.cfg.Room, current, mode, setpoint)
+	}
+
+	return nil
+}
+
+// roomTemperature возвращает среднюю температуру по свежим показаниям датчиков комнаты
+func roomTemperature(cfg config.ClimateRoomConfig, readings []models.SensorData, now time.Time) (float64, []string) {
+	selected := make(map[string]bool, len(cfg.Sensors))
+	for _, id := range cfg.Sensors {
+		selected[id] = true
+	}
+
+	var sum float64
+	var sensors []string
+	for _, reading := range readings {
+		if len(selected) > 0 && !selected[reading.SensorID] {
+			continue
+		}
+		if len(selected) == 0 && models.SensorLocation(reading.SensorID) != cfg.Room {
+			continue
+		}
+		if now.Sub(reading.Timestamp) > cfg.StaleAfter {
+			continue
+		}
+		value, err := sensortype.Decode(reading.SensorType, reading.Value.Data)
+		if err != nil || value.Kind != models.KindNumber {
+			continue
+		}
+		sum += value.Number
+		sensors = append(sensors, reading.SensorID)
+	}
+
+	if len(sensors) == 0 {
+		return 0, nil
+	}
+	sort.Strings(sensors)
+	return sum / float64(len(sensors)), sensors
+}
+
+func updateMetrics(status Status) {
+	setpointGauge.WithLabelValues(status.Room).Set(status.Setpoint)
+	if status.Temperature != nil {
+		roomTemperatureGauge.WithLabelValues(status.Room).Set(*status.Temperature)
+	}
+
+	output := status.Power
+	if status.Mode == ModeCool {
+		output = -output
+	}
+	outputGauge.WithLabelValues(status.Room).Set(output)
+	dutyCycleGauge.WithLabelValues(status.Room).Set(status.DutyCycle)
+
+	for _, mode := range []string{ModeIdle, ModeHeat, ModeCool} {
+		value := 0.0
+		if status.Mode == mode {
+			value = 1
+		}
+		modeGauge.WithLabelValues(status.Room, mode).Set(value)
+	}
+}
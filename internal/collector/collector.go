@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"sync"
@@ -8,15 +10,19 @@ import (
 
 	"github.com/4Amangel1/smart-house-automate/internal/database"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type Collector struct {
-	sensors  []models.Sensor
-	repo     *database.Repository
-	logger   *log.Logger
-	stopChan chan struct{}
-	wg       sync.WaitGroup
+	sensors   []models.Sensor
+	repo      *database.Repository
+	processor *processing.Processor
+	logger    *log.Logger
+	mux       *http.ServeMux
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
 }
 
 func New(sensors []models.Sensor, repo *database.Repository, logger *log.Logger) *Collector {
@@ -24,18 +30,30 @@ func New(sensors []models.Sensor, repo *database.Repository, logger *log.Logger)
 		sensors:  sensors,
 		repo:     repo,
 		logger:   logger,
+		mux:      http.NewServeMux(),
 		stopChan: make(chan struct{}),
 	}
 }
 
+// Handle добавляет обработчик на HTTP-сервер сборщика (порт метрик); вызывается до Start
+func (c *Collector) Handle(pattern string, handler http.Handler) {
+	c.mux.Handle(pattern, handler)
+}
+
+// SetProcessor включает обработку показаний (калибровку, сглаживание)
+// перед сохранением; вызывается до Start
+func (c *Collector) SetProcessor(p *processing.Processor) {
+	c.processor = p
+}
+
 func (c *Collector) Start() {
 	c.logger.Printf("Starting collector for %d sensors", len(c.sensors))
 
 	// Запуск HTTP-сервера для метрик Prometheus
+	c.mux.Handle("/metrics", promhttp.Handler())
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
 		c.logger.Printf("Starting metrics server on :9090")
-		if err := http.ListenAndServe(":9090", nil); err != nil {
+		if err := http.ListenAndServe(":9090", c.mux); err != nil {
 			c.logger.Printf("Error starting metrics server: %v", err)
 		}
 	}()
@@ -68,7 +86,10 @@ func (c *Collector) collectFromSensor(sensor models.Sensor) {
 		case <-ticker.C:
 			reading, err := sensor.Read()
 			if err != nil {
-				if err != models.ErrNotReady {
+				if errors.Is(err, models.ErrNotReady) {
+					sensorReadFailures.WithLabelValues(sensor.ID(), "not_ready").Inc()
+				} else {
+					sensorReadFailures.WithLabelValues(sensor.ID(), "error").Inc()
 					c.logger.Printf("Error reading from sensor %s: %v", sensor.ID(), err)
 				}
 				continue
@@ -79,19 +100,8 @@ func (c *Collector) collectFromSensor(sensor models.Sensor) {
 				SensorType: sensor.Type(),
 				Timestamp:  reading.Timestamp,
 				Value:      models.SensorValue{Data: reading.Value},
-				CreatedAt:  time.Now().UTC(),
 			}
-
-			if err := c.repo.SaveReading(sensorData); err != nil {
-				c.logger.Printf("Error saving reading from sensor %s: %v", sensor.ID(), err)
-				readingErrors.Inc() // Увеличиваем счетчик ошибок
-				continue
-			}
-
-			// Обновляем метрики Prometheus
-			updateMetrics(sensorData)
-
-			c.logger.Printf("Collected and saved data from %s sensor %s", sensor.Type(), sensor.ID())
+			c.store(sensorData)
 
 		case <-c.stopChan:
 			c.logger.Printf("Stopping collection from sensor %s", sensor.ID())
@@ -99,3 +109,51 @@ func (c *Collector) collectFromSensor(sensor models.Sensor) {
 		}
 	}
 }
+
+// errInvalidReading - значение показания не соответствует схеме типа датчика
+// или его единицу нельзя перевести в каноническую
+var errInvalidReading = errors.New("invalid reading")
+
+// store проверяет показание по схеме типа датчика, переводит его в
+// каноническую единицу, обрабатывает (калибровка, сглаживание), сохраняет и
+// обновляет метрики; общий путь для опрашиваемых датчиков и внешних
+// источников (MQTT, line protocol). Некорректное показание возвращает ошибку
+// errInvalidReading, отбракованный выброс - processing.ErrRejected
+func (c *Collector) store(data models.SensorData) error {
+	data, err := sensortype.Normalize(data)
+	if err != nil {
+		c.logger.Printf("Rejected reading: %v", err)
+		invalidReadings.WithLabelValues(data.SensorID).Inc()
+		return fmt.Errorf("%w: %v", errInvalidReading, err)
+	}
+
+	if c.processor != nil {
+		data, err = c.processor.Process(data)
+		if errors.Is(err, processing.ErrRejected) {
+			c.logger.Printf("Rejected outlier from sensor %s", data.SensorID)
+			rejectedReadings.WithLabelValues(data.SensorID).Inc()
+			return err
+		}
+		if err != nil {
+			c.logger.Printf("Rejected reading: %v", err)
+			invalidReadings.WithLabelValues(data.SensorID).Inc()
+			return fmt.Errorf("%w: %v", errInvalidReading, err)
+		}
+	}
+
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now().UTC()
+	}
+
+	if err := c.repo.SaveReading(data); err != nil {
+		c.logger.Printf("Error saving reading from sensor %s: %v", data.SensorID, err)
+		readingErrors.Inc() // Увеличиваем счетчик ошибок
+		return err
+	}
+
+	// Обновляем метрики Prometheus
+	updateMetrics(data)
+
+	c.logger.Printf("Collected and saved data from %s sensor %s", data.SensorType, data.SensorID)
+	return nil
+}
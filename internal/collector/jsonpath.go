@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookup извлекает значение из разобранного JSON по пути в точечной нотации
+// с индексами массивов: "AM2301.Temperature", "readings[0].value". Пустой
+// путь возвращает документ целиком
+func lookup(doc interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return doc, nil
+	}
+
+	current := doc
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		var indexes []int
+
+		// Индексы массивов: key[0][1]
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			key = part[:open]
+			for rest := part[open:]; rest != ""; {
+				end := strings.IndexByte(rest, ']')
+				if rest[0] != '[' || end < 0 {
+					return nil, fmt.Errorf("invalid path element %q", part)
+				}
+				index, err := strconv.Atoi(rest[1:end])
+				if err != nil {
+					return nil, fmt.Errorf("invalid index in %q", part)
+				}
+				indexes = append(indexes, index)
+				rest = rest[end+1:]
+			}
+		}
+
+		if key != "" {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%q is not an object", key)
+			}
+			if current, ok = object[key]; !ok {
+				return nil, fmt.Errorf("field %q not found", key)
+			}
+		}
+
+		for _, index := range indexes {
+			array, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("index %d is out of range in %q", index, part)
+			}
+			current = array[index]
+		}
+	}
+
+	return current, nil
+}
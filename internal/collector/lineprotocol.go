@@ -0,0 +1,160 @@
+package collector
+
+import (
+	"compress/gzip"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/lineprotocol"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
+)
+
+// Пути записи InfluxDB 1.x и 2.x, на которые умеет писать Telegraf
+const (
+	LineProtocolPath   = "/write"
+	LineProtocolV2Path = "/api/v2/write"
+)
+
+// maxLineProtocolBody - максимальный размер тела запроса после распаковки
+const maxLineProtocolBody = 16 << 20
+
+// LineProtocolHandler принимает точки InfluxDB line protocol и сохраняет их
+// как показания датчиков. Ответы повторяют InfluxDB: 204 при успехе, 400 с
+// описанием ошибки, если часть строк не разобрана (остальные сохраняются)
+type LineProtocolHandler struct {
+	collector *Collector
+	sensorTag string
+	token     string
+	logger    *log.Logger
+}
+
+// NewLineProtocolHandler создает обработчик записи; sensorTag - тег с ID
+// датчика, token - токен записи (пусто - без проверки)
+func NewLineProtocolHandler(c *Collector, sensorTag, token string, logger *log.Logger) *LineProtocolHandler {
+	return &LineProtocolHandler{collector: c, sensorTag: sensorTag, token: token, logger: logger}
+}
+
+// ServeHTTP реализует http.Handler
+func (h *LineProtocolHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeInfluxError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !h.authorized(r) {
+		writeInfluxError(w, http.StatusUnauthorized, "authorization failed")
+		return
+	}
+
+	precision, err := lineprotocol.Precision(r.URL.Query().Get("precision"))
+	if err != nil {
+		writeInfluxError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body := io.ReadCloser(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			writeInfluxError(w, http.StatusBadRequest, "invalid gzip body")
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	// Слишком большое тело отклоняется целиком, а не обрезается: иначе часть
+	// точек сохранилась бы, а клиент не узнал бы, какая именно
+	points, errs := lineprotocol.Parse(http.MaxBytesReader(w, body, maxLineProtocolBody), precision)
+	for _, err := range errs {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeInfluxError(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", maxLineProtocolBody))
+			return
+		}
+	}
+	lineProtocolPoints.WithLabelValues("invalid").Add(float64(len(errs)))
+
+	now := time.Now().UTC()
+	failed := 0
+	for _, point := range points {
+		data, err := lineprotocol.ToSensorData(point, h.sensorTag)
+		if err != nil {
+			lineProtocolPoints.WithLabelValues("invalid").Inc()
+			errs = append(errs, err)
+			continue
+		}
+		if data.Timestamp.IsZero() {
+			data.Timestamp = now
+		}
+		if data.Metadata.Data == nil {
+			data.Metadata.Data = make(map[string]interface{})
+		}
+		data.Metadata.Data["source"] = "line_protocol"
+
+		if err := h.collector.store(data); err != nil {
+			// Выброс отбрасывается молча: клиент записал корректную точку
+			if errors.Is(err, processing.ErrRejected) {
+				lineProtocolPoints.WithLabelValues("rejected").Inc()
+				continue
+			}
+			if errors.Is(err, errInvalidReading) {
+				lineProtocolPoints.WithLabelValues("invalid").Inc()
+				errs = append(errs, err)
+				continue
+			}
+			lineProtocolPoints.WithLabelValues("error").Inc()
+			failed++
+			continue
+		}
+		lineProtocolPoints.WithLabelValues("ok").Inc()
+	}
+
+	switch {
+	case failed > 0:
+		writeInfluxError(w, http.StatusInternalServerError, fmt.Sprintf("failed to store %d points", failed))
+	case len(errs) > 0:
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		h.logger.Printf("Rejected %d line protocol points: %s", len(errs), strings.Join(messages, "; "))
+		writeInfluxError(w, http.StatusBadRequest, "partial write: "+strings.Join(messages, "; "))
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// authorized проверяет токен: заголовок Authorization (Token или Bearer, как
+// у InfluxDB 2.x) или параметр p (пароль InfluxDB 1.x)
+func (h *LineProtocolHandler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return true
+	}
+
+	auth := r.Header.Get("Authorization")
+	for _, scheme := range []string{"Token ", "Bearer "} {
+		if strings.HasPrefix(auth, scheme) && tokenEqual(strings.TrimPrefix(auth, scheme), h.token) {
+			return true
+		}
+	}
+	return tokenEqual(r.URL.Query().Get("p"), h.token)
+}
+
+// tokenEqual сравнивает токены за постоянное время
+func tokenEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+func writeInfluxError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
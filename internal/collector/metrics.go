@@ -1,99 +1,108 @@
 package collector
 
 import (
-	"strings"
-
 	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
-	// Метрики температуры
-	temperatureGauge = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "temperature_celsius",
-			Help: "Температура в градусах Цельсия",
+	// Общие метрики
+	readingsCollected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "collector_readings_total",
+			Help: "Общее количество собранных показаний",
 		},
-		[]string{"sensor_id", "location"},
 	)
 
-	// Метрики движения
-	motionGauge = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "motion_detected",
-			Help: "Обнаружено ли движение (1 - да, 0 - нет)",
+	readingErrors = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "collector_reading_errors_total",
+			Help: "Общее количество ошибок при сборе показаний",
 		},
-		[]string{"sensor_id", "location"},
 	)
 
-	// Метрики качества воздуха
-	co2Gauge = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "air_co2_ppm",
-			Help: "Уровень CO2 в частях на миллион",
+	invalidReadings = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "collector_invalid_readings_total",
+			Help: "Количество показаний, отклоненных проверкой по схеме типа датчика",
 		},
-		[]string{"sensor_id", "location"},
+		[]string{"sensor_id"},
 	)
 
-	nh3Gauge = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "air_nh3_ppm",
-			Help: "Уровень NH3 в частях на миллион",
+	rejectedReadings = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "collector_rejected_outliers_total",
+			Help: "Количество показаний, отбракованных обработкой как выбросы",
 		},
-		[]string{"sensor_id", "location"},
+		[]string{"sensor_id"},
 	)
 
-	// Общие метрики
-	readingsCollected = promauto.NewCounter(
+	sensorReadFailures = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "collector_readings_total",
-			Help: "Общее количество собранных показаний",
+			Name: "collector_sensor_read_failures_total",
+			Help: "Количество неудачных опросов датчиков (not_ready - датчик не готов, error - ошибка чтения)",
 		},
+		[]string{"sensor_id", "reason"},
 	)
 
-	readingErrors = promauto.NewCounter(
+	mqttMessages = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "collector_reading_errors_total",
-			Help: "Общее количество ошибок при сборе показаний",
+			Name: "collector_mqtt_messages_total",
+			Help: "Количество сообщений MQTT по подпискам (ok, invalid - не удалось разобрать или значение не соответствует типу, rejected - выброс, error - ошибка сохранения)",
 		},
+		[]string{"subscription", "result"},
+	)
+
+	lineProtocolPoints = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "collector_line_protocol_points_total",
+			Help: "Количество точек InfluxDB line protocol (ok, invalid - не удалось разобрать или значение не соответствует типу, rejected - выброс, error - ошибка сохранения)",
+		},
+		[]string{"result"},
 	)
 )
 
-// Обновление метрик при сборе данных
-func updateMetrics(data models.SensorData) {
-	readingsCollected.Inc()
+// sensorGauges - метрики показаний по типам датчиков из реестра
+var sensorGauges = make(map[string][]sensorGauge)
 
-	// Получите местоположение из ID (например, temp_living_room => living_room)
-	parts := strings.Split(data.SensorID, "_")
-	location := "unknown"
-	if len(parts) > 1 {
-		location = strings.Join(parts[1:], "_")
-	}
+type sensorGauge struct {
+	field string
+	gauge *prometheus.GaugeVec
+}
 
-	switch data.SensorType {
-	case "temperature":
-		if value, ok := data.Value.Data.(float64); ok {
-			temperatureGauge.WithLabelValues(data.SensorID, location).Set(value)
+func init() {
+	for _, t := range sensortype.All() {
+		for _, m := range t.Metrics {
+			sensorGauges[t.Name] = append(sensorGauges[t.Name], sensorGauge{
+				field: m.Field,
+				gauge: promauto.NewGaugeVec(
+					prometheus.GaugeOpts{Name: m.Name, Help: m.Help},
+					[]string{"sensor_id", "location"},
+				),
+			})
 		}
+	}
+}
 
-	case "motion":
-		if value, ok := data.Value.Data.(bool); ok {
-			if value {
-				motionGauge.WithLabelValues(data.SensorID, location).Set(1)
-			} else {
-				motionGauge.WithLabelValues(data.SensorID, location).Set(0)
-			}
-		}
+// Обновление метрик при сборе данных
+func updateMetrics(data models.SensorData) {
+	readingsCollected.Inc()
+
+	location := models.SensorLocation(data.SensorID)
 
-	case "air_quality":
-		if valueMap, ok := data.Value.Data.(map[string]interface{}); ok {
-			if co2, co2ok := valueMap["co2"].(float64); co2ok {
-				co2Gauge.WithLabelValues(data.SensorID, location).Set(co2)
-			}
-			if nh3, nh3ok := valueMap["nh3"].(float64); nh3ok {
-				nh3Gauge.WithLabelValues(data.SensorID, location).Set(nh3)
-			}
+	gauges := sensorGauges[data.SensorType]
+	if len(gauges) == 0 {
+		return
+	}
+	value, err := sensortype.Decode(data.SensorType, data.Value.Data)
+	if err != nil {
+		return
+	}
+	for _, g := range gauges {
+		if number, ok := value.Field(g.field); ok {
+			g.gauge.WithLabelValues(data.SensorID, location).Set(number)
 		}
 	}
 }
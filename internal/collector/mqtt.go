@@ -0,0 +1,218 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/processing"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Значения по умолчанию
+const (
+	defaultMQTTClientID = "smart-house-collector"
+	mqttConnectTimeout  = 10 * time.Second
+)
+
+// MQTTSource принимает показания датчиков, публикуемые в MQTT (Zigbee2MQTT,
+// Tasmota, ESPHome), и сохраняет их тем же путем, что и опрашиваемые датчики
+type MQTTSource struct {
+	cfg       config.MQTTConfig
+	collector *Collector
+	logger    *log.Logger
+	client    mqtt.Client
+}
+
+// NewMQTTSource создает источник показаний MQTT для сборщика
+func NewMQTTSource(cfg config.MQTTConfig, c *Collector, logger *log.Logger) *MQTTSource {
+	return &MQTTSource{cfg: cfg, collector: c, logger: logger}
+}
+
+// Start подключается к брокеру и подписывается на топики. Если брокер
+// недоступен, подключение повторяется в фоне; подписки восстанавливаются
+// после переподключения
+func (s *MQTTSource) Start() {
+	clientID := s.cfg.ClientID
+	if clientID == "" {
+		clientID = defaultMQTTClientID
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(s.cfg.Broker).
+		SetClientID(clientID).
+		SetUsername(s.cfg.Username).
+		SetPassword(s.cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(s.subscribe).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			s.logger.Printf("MQTT connection lost: %v", err)
+		})
+
+	s.client = mqtt.NewClient(opts)
+	s.logger.Printf("Connecting to MQTT broker %s", s.cfg.Broker)
+	if token := s.client.Connect(); !token.WaitTimeout(mqttConnectTimeout) {
+		s.logger.Printf("MQTT broker %s is not available yet, retrying in background", s.cfg.Broker)
+	} else if err := token.Error(); err != nil {
+		s.logger.Printf("Error connecting to MQTT broker: %v", err)
+	}
+}
+
+// Stop отключается от брокера
+func (s *MQTTSource) Stop() {
+	if s.client != nil {
+		s.client.Disconnect(250)
+	}
+}
+
+// subscribe подписывается на все топики конфигурации
+func (s *MQTTSource) subscribe(client mqtt.Client) {
+	s.logger.Printf("Connected to MQTT broker, subscribing to %d topics", len(s.cfg.Subscriptions))
+
+	for _, sub := range s.cfg.Subscriptions {
+		sub := sub
+		token := client.Subscribe(sub.Topic, sub.QoS, func(_ mqtt.Client, msg mqtt.Message) {
+			s.handle(sub, msg.Topic(), msg.Payload())
+		})
+		if token.Wait() && token.Error() != nil {
+			s.logger.Printf("Error subscribing to MQTT topic %s: %v", sub.Topic, token.Error())
+		}
+	}
+}
+
+// handle преобразует сообщение в показание и сохраняет его
+func (s *MQTTSource) handle(sub config.MQTTSubscription, topic string, payload []byte) {
+	data, err := parseMessage(sub, topic, payload, time.Now().UTC())
+	if err != nil {
+		mqttMessages.WithLabelValues(sub.Topic, "invalid").Inc()
+		s.logger.Printf("Error parsing MQTT message on %s: %v", topic, err)
+		return
+	}
+
+	if err := s.collector.store(data); err != nil {
+		switch {
+		case errors.Is(err, errInvalidReading):
+			mqttMessages.WithLabelValues(sub.Topic, "invalid").Inc()
+		case errors.Is(err, processing.ErrRejected):
+			mqttMessages.WithLabelValues(sub.Topic, "rejected").Inc()
+		default:
+			mqttMessages.WithLabelValues(sub.Topic, "error").Inc()
+		}
+		return
+	}
+	mqttMessages.WithLabelValues(sub.Topic, "ok").Inc()
+}
+
+// parseMessage извлекает из сообщения значение, единицу измерения и время
+// измерения согласно подписке
+func parseMessage(sub config.MQTTSubscription, topic string, payload []byte, now time.Time) (models.SensorData, error) {
+	doc := parsePayload(payload)
+
+	data := models.SensorData{
+		SensorID:   sensorID(sub.SensorID, topic),
+		SensorType: sub.SensorType,
+		Timestamp:  now,
+		Unit:       sub.Unit,
+	}
+
+	if len(sub.Fields) > 0 {
+		fields := make(map[string]interface{}, len(sub.Fields))
+		for name, path := range sub.Fields {
+			value, err := lookup(doc, path)
+			if err != nil {
+				return data, fmt.Errorf("field %s: %w", name, err)
+			}
+			fields[name] = normalize(value)
+		}
+		data.Value.Data = fields
+	} else {
+		value, err := lookup(doc, sub.Value)
+		if err != nil {
+			return data, err
+		}
+		data.Value.Data = normalize(value)
+	}
+
+	if sub.UnitPath != "" {
+		if unit, err := lookup(doc, sub.UnitPath); err == nil {
+			data.Unit = fmt.Sprint(unit)
+		}
+	}
+
+	if sub.TimestampPath != "" {
+		raw, err := lookup(doc, sub.TimestampPath)
+		if err != nil {
+			return data, fmt.Errorf("timestamp: %w", err)
+		}
+		if data.Timestamp, err = parseTimestamp(raw); err != nil {
+			return data, err
+		}
+	}
+
+	return data, nil
+}
+
+// parsePayload разбирает сообщение как JSON; простые сообщения (Tasmota
+// публикует "ON", "23.5") возвращаются как значение
+func parsePayload(payload []byte) interface{} {
+	var doc interface{}
+	if err := json.Unmarshal(payload, &doc); err == nil {
+		return doc
+	}
+	return strings.TrimSpace(string(payload))
+}
+
+// normalize приводит строковые значения к числам и логическим значениям
+func normalize(value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	switch strings.ToUpper(text) {
+	case "ON", "TRUE", "OPEN", "DETECTED":
+		return true
+	case "OFF", "FALSE", "CLOSED", "CLEAR":
+		return false
+	}
+	return text
+}
+
+// parseTimestamp принимает время в RFC3339 (или ISO без часового пояса, как
+// у Tasmota) либо Unix-время в секундах или миллисекундах
+func parseTimestamp(raw interface{}) (time.Time, error) {
+	switch v := raw.(type) {
+	case float64:
+		if v > 1e12 {
+			return time.UnixMilli(int64(v)).UTC(), nil
+		}
+		return time.Unix(int64(v), 0).UTC(), nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC(), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %v", raw)
+}
+
+// sensorID подставляет уровни топика в шаблон: {0} - первый уровень
+func sensorID(template, topic string) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+	for i, level := range strings.Split(topic, "/") {
+		template = strings.ReplaceAll(template, "{"+strconv.Itoa(i)+"}", level)
+	}
+	return template
+}
@@ -0,0 +1,236 @@
+package collector
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+)
+
+func TestLookup(t *testing.T) {
+	var doc interface{}
+	payload := `{"AM2301":{"Temperature":22.1},"readings":[{"value":1},{"value":[5,6]}],"n":3}`
+	if err := json.Unmarshal([]byte(payload), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr bool
+	}{
+		{path: "AM2301.Temperature", want: 22.1},
+		{path: "readings[0].value", want: 1.0},
+		{path: "readings[1].value[1]", want: 6.0},
+		{path: "n", want: 3.0},
+		{path: "", want: doc},
+		{path: "missing", wantErr: true},
+		{path: "n.value", wantErr: true},
+		{path: "readings[2]", wantErr: true},
+		{path: "readings[-1]", wantErr: true},
+		{path: "readings[x]", wantErr: true},
+		{path: "readings[0", wantErr: true},
+		{path: "AM2301[0]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := lookup(doc, tt.path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("lookup(%q) = %v, want error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("lookup(%q) error: %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookup(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSensorID(t *testing.T) {
+	tests := []struct {
+		template, topic, want string
+	}{
+		{template: "temp_hall", topic: "zigbee2mqtt/temp_balcony", want: "temp_hall"},
+		{template: "{1}", topic: "zigbee2mqtt/temp_balcony", want: "temp_balcony"},
+		{template: "{0}_{2}", topic: "tele/hall/SENSOR", want: "tele_SENSOR"},
+		{template: "{1}_{1}", topic: "a/b", want: "b_b"},
+		{template: "{5}", topic: "a/b", want: "{5}"},
+	}
+
+	for _, tt := range tests {
+		if got := sensorID(tt.template, tt.topic); got != tt.want {
+			t.Errorf("sensorID(%q, %q) = %q, want %q", tt.template, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2026, 10, 13, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		raw     interface{}
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "RFC3339", raw: "2026-10-13T21:00:00+03:00", want: want},
+		{name: "Tasmota without zone", raw: "2026-10-13T18:00:00", want: want},
+		{name: "unix seconds", raw: float64(want.Unix()), want: want},
+		{name: "unix milliseconds", raw: float64(want.UnixMilli()), want: want},
+		{name: "invalid string", raw: "yesterday", wantErr: true},
+		{name: "boolean", raw: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimestamp(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	now := time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		sub       config.MQTTSubscription
+		topic     string
+		payload   string
+		wantID    string
+		wantValue interface{}
+		wantUnit  string
+		wantTime  time.Time
+		wantErr   bool
+	}{
+		{
+			name: "zigbee2mqtt",
+			sub: config.MQTTSubscription{
+				SensorID: "{1}", SensorType: "temperature", Value: "temperature", Unit: "°C",
+			},
+			topic:     "zigbee2mqtt/temp_balcony",
+			payload:   `{"temperature":21.4,"humidity":48}`,
+			wantID:    "temp_balcony",
+			wantValue: 21.4,
+			wantUnit:  "°C",
+			wantTime:  now,
+		},
+		{
+			name: "tasmota with unit and time",
+			sub: config.MQTTSubscription{
+				SensorID: "temp_hall", SensorType: "temperature",
+				Value: "AM2301.Temperature", UnitPath: "TempUnit", TimestampPath: "Time",
+			},
+			topic:     "tele/tasmota_hall/SENSOR",
+			payload:   `{"Time":"2026-10-13T18:00:00","AM2301":{"Temperature":72.5},"TempUnit":"F"}`,
+			wantID:    "temp_hall",
+			wantValue: 72.5,
+			wantUnit:  "F",
+			wantTime:  time.Date(2026, 10, 13, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "plain ON payload",
+			sub:       config.MQTTSubscription{SensorID: "motion_hall", SensorType: "motion"},
+			topic:     "stat/tasmota_hall/POWER",
+			payload:   "ON",
+			wantID:    "motion_hall",
+			wantValue: true,
+			wantTime:  now,
+		},
+		{
+			name:      "plain numeric string",
+			sub:       config.MQTTSubscription{SensorID: "temp_hall", SensorType: "temperature"},
+			topic:     "stat/temp",
+			payload:   " 23.5 ",
+			wantID:    "temp_hall",
+			wantValue: 23.5,
+			wantTime:  now,
+		},
+		{
+			name: "object fields",
+			sub: config.MQTTSubscription{
+				SensorID: "air_kitchen", SensorType: "air_quality",
+				Fields: map[string]string{"co2": "sensors.co2", "nh3": "sensors.nh3"},
+			},
+			topic:     "home/air",
+			payload:   `{"sensors":{"co2":"650","nh3":12}}`,
+			wantID:    "air_kitchen",
+			wantValue: map[string]interface{}{"co2": 650.0, "nh3": 12.0},
+			wantTime:  now,
+		},
+		{
+			name:    "missing value",
+			sub:     config.MQTTSubscription{SensorID: "t", SensorType: "temperature", Value: "temperature"},
+			topic:   "t",
+			payload: `{"humidity":48}`,
+			wantErr: true,
+		},
+		{
+			name: "missing field",
+			sub: config.MQTTSubscription{
+				SensorID: "air", SensorType: "air_quality", Fields: map[string]string{"co2": "co2"},
+			},
+			topic:   "air",
+			payload: `{"nh3":1}`,
+			wantErr: true,
+		},
+		{
+			name: "invalid timestamp",
+			sub: config.MQTTSubscription{
+				SensorID: "t", SensorType: "temperature", Value: "v", TimestampPath: "time",
+			},
+			topic:   "t",
+			payload: `{"v":1,"time":"soon"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseMessage(tt.sub, tt.topic, []byte(tt.payload), now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %+v, want error", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if data.SensorID != tt.wantID {
+				t.Errorf("sensor ID = %q, want %q", data.SensorID, tt.wantID)
+			}
+			if data.SensorType != tt.sub.SensorType {
+				t.Errorf("sensor type = %q, want %q", data.SensorType, tt.sub.SensorType)
+			}
+			if !reflect.DeepEqual(data.Value.Data, tt.wantValue) {
+				t.Errorf("value = %#v, want %#v", data.Value.Data, tt.wantValue)
+			}
+			if data.Unit != tt.wantUnit {
+				t.Errorf("unit = %q, want %q", data.Unit, tt.wantUnit)
+			}
+			if !data.Timestamp.Equal(tt.wantTime) {
+				t.Errorf("timestamp = %v, want %v", data.Timestamp, tt.wantTime)
+			}
+		})
+	}
+}
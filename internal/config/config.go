@@ -13,10 +13,21 @@ import (
 
 // Config содержит все настройки приложения
 type Config struct {
-	Sensors     SensorsConfig `yaml:"sensors"`
+	Sensors     SensorsConfig      `yaml:"sensors"`
+	Devices     DevicesConfig      `yaml:"devices"`
+	Reports     []ReportConfig     `yaml:"reports"`
+	Climate     ClimateConfig      `yaml:"climate"`
+	Environment EnvironmentConfig  `yaml:"environment"`
+	Occupancy   OccupancyConfig    `yaml:"occupancy"`
+	MQTT        MQTTConfig         `yaml:"mqtt"`
+	Processing  []ProcessingConfig `yaml:"processing"`
 	Database    DatabaseConfig
-	API         APIConfig
+	API         APIConfig `yaml:"api"`
 	TelegramBot TelegramBotConfig
+	SMTP        SMTPConfig
+	Automation  AutomationConfig
+	Emulator    EmulatorConfig
+	Collector   CollectorConfig
 }
 
 // DatabaseConfig содержит настройки базы данных
@@ -41,14 +52,192 @@ type APIConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	Ingest       IngestConfig `yaml:"ingest"` // Прием показаний от устройств (задается в файле конфигурации)
+}
+
+// IngestConfig содержит настройки приема показаний, которые устройства
+// (например, платы ESP8266) отправляют в API сами
+type IngestConfig struct {
+	RateLimit float64              `yaml:"rate_limit"` // Показаний в секунду на устройство, по умолчанию 1
+	Burst     int                  `yaml:"burst"`      // Сколько показаний устройство может отправить разом, по умолчанию 10
+	Devices   []IngestDeviceConfig `yaml:"devices"`
+}
+
+// IngestDeviceConfig описывает устройство, которому разрешено отправлять показания
+type IngestDeviceConfig struct {
+	ID      string               `yaml:"id"`
+	Token   string               `yaml:"token"` // Токен устройства; можно задать переменной INGEST_TOKEN_<ID>
+	Sensors []IngestSensorConfig `yaml:"sensors"`
+}
+
+// IngestSensorConfig описывает датчик устройства; при запуске API датчик
+// заносится в реестр датчиков
+type IngestSensorConfig struct {
+	ID       string `yaml:"id"`
+	Type     string `yaml:"type"`
+	Name     string `yaml:"name"`
+	Location string `yaml:"location"`
+}
+
+func (c IngestConfig) Validate() error {
+	if c.RateLimit < 0 || c.Burst < 0 {
+		return fmt.Errorf("rate limit and burst must not be negative")
+	}
+
+	tokens := make(map[string]bool, len(c.Devices))
+	for _, device := range c.Devices {
+		if device.ID == "" {
+			return fmt.Errorf("device ID is required")
+		}
+		if device.Token == "" {
+			return fmt.Errorf("device %s: token is required", device.ID)
+		}
+		if tokens[device.Token] {
+			return fmt.Errorf("device %s: token is already used by another device", device.ID)
+		}
+		tokens[device.Token] = true
+
+		if len(device.Sensors) == 0 {
+			return fmt.Errorf("device %s: at least one sensor is required", device.ID)
+		}
+		for _, sensor := range device.Sensors {
+			if sensor.ID == "" || sensor.Type == "" {
+				return fmt.Errorf("device %s: sensor ID and type are required", device.ID)
+			}
+		}
+	}
+	return nil
 }
 
 // TelegramBotConfig содержит настройки Telegram-бота
 type TelegramBotConfig struct {
 	Token                     string
+	APIEndpoint               string // Шаблон адреса Bot API (можно указать локальный фейковый сервер)
 	AuthorizedUserIDs         []int64
+	AdminUserIDs              []int64 // Пользователи, которым разрешено менять правила оповещений
 	AlertCheckInterval        time.Duration
 	TemperatureAlertThreshold float64
+	Mode                      string // "polling" (по умолчанию) или "webhook"
+	WebhookURL                string // Публичный HTTPS-адрес, который регистрируется в Telegram
+	WebhookListenAddr         string // Адрес встроенного HTTP-сервера для приема обновлений
+	WebhookSecret             string // Секрет, который Telegram передает в заголовке запроса
+	WebhookCertFile           string // Сертификат TLS (если TLS не завершается на прокси)
+	WebhookKeyFile            string // Ключ TLS
+}
+
+func (c TelegramBotConfig) Validate() error {
+	switch c.Mode {
+	case "", "polling":
+		return nil
+	case "webhook":
+		if !strings.HasPrefix(c.WebhookURL, "https://") {
+			return fmt.Errorf("webhook URL must use https")
+		}
+		if c.WebhookSecret == "" {
+			return fmt.Errorf("webhook secret token is required")
+		}
+		if (c.WebhookCertFile == "") != (c.WebhookKeyFile == "") {
+			return fmt.Errorf("both webhook TLS certificate and key must be set")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown telegram bot mode %q", c.Mode)
+	}
+}
+
+// SMTPConfig содержит настройки почтового сервера для отправки отчетов
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	From     string
+}
+
+// Enabled сообщает, настроена ли отправка почты
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != "" && c.From != ""
+}
+
+// AutomationConfig содержит настройки движка автоматизации
+type AutomationConfig struct {
+	RulesFile     string         // Путь к YAML-файлу с правилами
+	Interval      time.Duration  // Период вычисления правил
+	DryRun        bool           // Пробный режим: действия только записываются в журнал
+	Location      *time.Location // Часовой пояс для триггеров и условий по времени
+	MaxReadingAge time.Duration  // Более старые показания не учитываются, 0 - без ограничения
+}
+
+// EmulatorConfig содержит настройки воспроизводимости и режима времени эмулятора
+type EmulatorConfig struct {
+	Seed      int64     // Общее зерно генераторов случайных чисел, 0 - случайное
+	Speed     float64   // Ускорение модельного времени относительно реального
+	Start     time.Time // Начальный момент модельного времени, по умолчанию текущий
+	BatchDays int       // Пакетный режим: сгенерировать данные за N дней и завершиться
+	Output    string    // Куда записывать пакет: путь к NDJSON-файлу или "db"
+	Scenario  string    // Путь к YAML-файлу сценария, пусто - без сценария
+	Listen    string    // Адрес HTTP-сервера датчиков, пусто - не запускать
+	PushURL   string    // Адрес приемника, куда отправлять каждое показание (режим push)
+	PushToken string    // Токен приемника, общий со сборщиком
+	// Откуда модель комнат берет состояния устройств: local - только
+	// устройства процесса эмулятора, db - еще и команды, сохраненные в БД
+	// другими сервисами (API, автоматизацией, климатом)
+	DeviceStates string
+	MQTT         EmulatorMQTTConfig
+}
+
+// EmulatorMQTTConfig содержит настройки публикации показаний эмулятора в
+// MQTT. В шаблонах топиков {id} заменяется на ID датчика, {type} - на тип
+type EmulatorMQTTConfig struct {
+	Broker            string // Адрес брокера, например tcp://localhost:1883; пусто - не публиковать
+	Username          string
+	Password          string
+	Format            string // Формат сообщений: zigbee2mqtt или tasmota
+	Topic             string // Шаблон топика показаний, по умолчанию как у выбранного формата
+	AvailabilityTopic string // Шаблон топика доступности (LWT), по умолчанию как у выбранного формата
+	Retain            bool   // Сохранять последнее показание на брокере
+	QoS               byte
+}
+
+// Enabled сообщает, включена ли публикация в MQTT
+func (c EmulatorMQTTConfig) Enabled() bool {
+	return c.Broker != ""
+}
+
+// CollectorConfig содержит настройки источников показаний сборщика
+type CollectorConfig struct {
+	Sensors     string // "local" - эмулируемые датчики в процессе сборщика, "remote" - датчики процесса эмулятора
+	EmulatorURL string // Адрес HTTP-сервера эмулятора для режима remote
+	RemoteMode  string // Как получать показания датчиков эмулятора: poll или push
+	RemoteToken string // Токен приемника показаний в режиме push, общий с эмулятором
+
+	LineProtocol      bool   // Принимать точки InfluxDB line protocol на /write и /api/v2/write
+	LineProtocolTag   string // Тег с ID датчика
+	LineProtocolToken string // Токен для записи, пусто - без проверки
+}
+
+// ReportConfig содержит расписание сводных отчетов для пользователя
+type ReportConfig struct {
+	UserID   int64  `yaml:"user_id"`
+	Daily    string `yaml:"daily"`    // Время ежедневного отчета, например "08:00"
+	Weekly   string `yaml:"weekly"`   // День и время еженедельного отчета, например "mon 08:00"
+	Email    string `yaml:"email"`    // Адрес для дублирования отчета по почте
+	Timezone string `yaml:"timezone"` // Часовой пояс расписания, по умолчанию UTC
+}
+
+func (c ReportConfig) Validate() error {
+	if c.UserID == 0 {
+		return fmt.Errorf("report user ID cannot be empty")
+	}
+	if c.Daily == "" && c.Weekly == "" {
+		return fmt.Errorf("report schedule for user %d is empty", c.UserID)
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid report timezone %q: %w", c.Timezone, err)
+		}
+	}
+	return nil
 }
 
 // LoadConfig загружает конфигурацию из файла и переменных окружения
@@ -72,15 +261,230 @@ func LoadConfig(filePath string) (*Config, error) {
 	// Загружаем настройки базы данных из переменных окружения
 	cfg.Database = loadDatabaseConfig()
 
-	// Загружаем настройки API из переменных окружения
+	// Загружаем настройки API из переменных окружения; прием показаний
+	// от устройств задается в файле, токены можно переопределить окружением
+	ingest := cfg.API.Ingest
 	cfg.API = loadAPIConfig()
+	cfg.API.Ingest = ingest
+	for i, device := range cfg.API.Ingest.Devices {
+		envKey := "INGEST_TOKEN_" + strings.ToUpper(strings.ReplaceAll(device.ID, "-", "_"))
+		cfg.API.Ingest.Devices[i].Token = getEnv(envKey, device.Token)
+	}
+	if err := cfg.API.Ingest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ingest config: %w", err)
+	}
 
 	// Загружаем настройки Telegram бота из переменных окружения
 	cfg.TelegramBot = loadTelegramBotConfig()
 
+	// Загружаем настройки почты из переменных окружения
+	cfg.SMTP = loadSMTPConfig()
+
+	// Загружаем настройки автоматизации из переменных окружения
+	cfg.Automation, err = loadAutomationConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Загружаем настройки эмулятора из переменных окружения
+	cfg.Emulator, err = loadEmulatorConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Загружаем настройки сборщика из переменных окружения
+	cfg.Collector, err = loadCollectorConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Адрес брокера и учетные данные MQTT можно переопределить переменными окружения
+	cfg.MQTT.Broker = getEnv("MQTT_BROKER", cfg.MQTT.Broker)
+	cfg.MQTT.Username = getEnv("MQTT_USERNAME", cfg.MQTT.Username)
+	cfg.MQTT.Password = getEnv("MQTT_PASSWORD", cfg.MQTT.Password)
+	if err := cfg.MQTT.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mqtt config: %w", err)
+	}
+
+	for _, p := range cfg.Processing {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid processing config: %w", err)
+		}
+	}
+
 	return &cfg, nil
 }
 
+// MQTTConfig содержит настройки подписки сборщика на MQTT-брокер
+type MQTTConfig struct {
+	Broker        string             `yaml:"broker"`    // Адрес брокера, например tcp://localhost:1883; пусто - MQTT отключен
+	ClientID      string             `yaml:"client_id"` // Идентификатор клиента
+	Username      string             `yaml:"username"`
+	Password      string             `yaml:"password"`
+	Subscriptions []MQTTSubscription `yaml:"subscriptions"`
+}
+
+// MQTTSubscription сопоставляет топик с датчиком. Пути - точечная нотация по
+// JSON-сообщению с индексами массивов (AM2301.Temperature, readings[0].value).
+// В sensor_id можно подставлять уровни топика: {1} - второй уровень
+type MQTTSubscription struct {
+	Topic         string            `yaml:"topic"`          // Топик, допускаются + и #
+	QoS           byte              `yaml:"qos"`            // Уровень QoS подписки
+	SensorID      string            `yaml:"sensor_id"`      // ID датчика или шаблон с уровнями топика
+	SensorType    string            `yaml:"sensor_type"`    // Тип датчика
+	Value         string            `yaml:"value"`          // Путь к значению, пусто - сообщение целиком
+	Fields        map[string]string `yaml:"fields"`         // Составное значение: поле -> путь (co2, nh3)
+	Unit          string            `yaml:"unit"`           // Единица измерения
+	UnitPath      string            `yaml:"unit_path"`      // Путь к единице измерения в сообщении
+	TimestampPath string            `yaml:"timestamp_path"` // Путь к времени измерения (RFC3339 или Unix-время), по умолчанию время получения
+}
+
+// Enabled сообщает, включен ли прием показаний по MQTT
+func (c MQTTConfig) Enabled() bool {
+	return c.Broker != "" && len(c.Subscriptions) > 0
+}
+
+func (c MQTTConfig) Validate() error {
+	for i, sub := range c.Subscriptions {
+		if sub.Topic == "" || sub.SensorID == "" || sub.SensorType == "" {
+			return fmt.Errorf("subscription %d: topic, sensor ID and type are required", i+1)
+		}
+		if sub.QoS > 2 {
+			return fmt.Errorf("subscription %d: QoS must be 0, 1 or 2", i+1)
+		}
+		if sub.Value != "" && len(sub.Fields) > 0 {
+			return fmt.Errorf("subscription %d: value and fields are mutually exclusive", i+1)
+		}
+	}
+	return nil
+}
+
+// SensorTypes возвращает типы датчиков, объявленных в конфигурации: эмулируемых,
+// воспроизводимых, сетевых, подписок MQTT с постоянным ID и устройств API.
+// Датчики без указанного типа и шаблоны ID в результат не попадают
+func (c *Config) SensorTypes() map[string]string {
+	types := make(map[string]string)
+	add := func(id, sensorType string) {
+		if id != "" && sensorType != "" && !strings.Contains(id, "{") {
+			types[id] = sensorType
+		}
+	}
+
+	for _, entry := range c.Sensors.List {
+		add(entry.ID, entry.Type)
+	}
+	for _, replay := range c.Sensors.Replay {
+		add(replay.ID, replay.Type)
+	}
+	for _, remote := range c.Sensors.Remote {
+		add(remote.ID, remote.Type)
+	}
+	for _, sub := range c.MQTT.Subscriptions {
+		add(sub.SensorID, sub.SensorType)
+	}
+	for _, device := range c.API.Ingest.Devices {
+		for _, sensor := range device.Sensors {
+			add(sensor.ID, sensor.Type)
+		}
+	}
+	return types
+}
+
+// ProcessingConfig описывает обработку показаний датчика в сборщике перед
+// сохранением: калибровку, ограничение, округление, сглаживание и отбраковку
+// выбросов. Шаги выполняются по порядку над значением в канонической единице
+// типа датчика; исходное значение сохраняется в метаданных (raw_value)
+type ProcessingConfig struct {
+	SensorID string                 `yaml:"sensor_id"`
+	Field    string                 `yaml:"field"` // Поле составного значения (co2), пусто - значение целиком
+	Steps    []ProcessingStepConfig `yaml:"steps"`
+}
+
+func (c ProcessingConfig) Validate() error {
+	if c.SensorID == "" {
+		return fmt.Errorf("sensor ID cannot be empty")
+	}
+	if len(c.Steps) == 0 {
+		return fmt.Errorf("%s: at least one step is required", c.SensorID)
+	}
+	for i, step := range c.Steps {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("%s: step %d: %w", c.SensorID, i+1, err)
+		}
+	}
+	return nil
+}
+
+// ProcessingStepConfig - шаг обработки. Параметры зависят от типа:
+//
+//	offset          - value + offset
+//	linear          - value*scale + offset
+//	two_point       - линейная поправка по двум точкам: raw - показания датчика, reference - эталон
+//	clamp           - ограничение диапазоном [min, max] (можно задать одну границу)
+//	round           - округление до digits знаков после запятой
+//	moving_average  - скользящее среднее по window последним значениям
+//	ema             - экспоненциальное сглаживание с коэффициентом alpha
+//	outlier         - отбраковка показания, отличающегося от медианы window последних больше чем на max_delta
+type ProcessingStepConfig struct {
+	Type      string    `yaml:"type"`
+	Offset    float64   `yaml:"offset"`
+	Scale     float64   `yaml:"scale"`
+	Raw       []float64 `yaml:"raw"`
+	Reference []float64 `yaml:"reference"`
+	Min       *float64  `yaml:"min"`
+	Max       *float64  `yaml:"max"`
+	Digits    int       `yaml:"digits"`
+	Window    int       `yaml:"window"`
+	Alpha     float64   `yaml:"alpha"`
+	MaxDelta  float64   `yaml:"max_delta"`
+}
+
+func (c ProcessingStepConfig) Validate() error {
+	switch c.Type {
+	case "offset":
+	case "linear":
+		if c.Scale == 0 {
+			return fmt.Errorf("linear scale cannot be zero")
+		}
+	case "two_point":
+		if len(c.Raw) != 2 || len(c.Reference) != 2 {
+			return fmt.Errorf("two_point requires two raw and two reference values")
+		}
+		if c.Raw[0] == c.Raw[1] {
+			return fmt.Errorf("two_point raw values must differ")
+		}
+	case "clamp":
+		if c.Min == nil && c.Max == nil {
+			return fmt.Errorf("clamp requires min or max")
+		}
+		if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+			return fmt.Errorf("clamp min must not exceed max")
+		}
+	case "round":
+		if c.Digits < 0 {
+			return fmt.Errorf("round digits cannot be negative")
+		}
+	case "moving_average":
+		if c.Window < 1 {
+			return fmt.Errorf("moving_average window must be positive")
+		}
+	case "ema":
+		if c.Alpha <= 0 || c.Alpha > 1 {
+			return fmt.Errorf("ema alpha must be in (0, 1]")
+		}
+	case "outlier":
+		if c.Window < 0 || c.Window == 1 {
+			return fmt.Errorf("outlier window must be at least 2")
+		}
+		if c.MaxDelta <= 0 {
+			return fmt.Errorf("outlier max_delta must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown step type %q", c.Type)
+	}
+	return nil
+}
+
 // loadDatabaseConfig загружает настройки БД из переменных окружения
 func loadDatabaseConfig() DatabaseConfig {
 	port, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
@@ -111,27 +515,304 @@ func loadAPIConfig() APIConfig {
 
 // loadTelegramBotConfig загружает настройки Telegram бота из переменных окружения
 func loadTelegramBotConfig() TelegramBotConfig {
-	userIDsStr := getEnv("AUTHORIZED_USER_IDS", "")
-	var userIDs []int64
-
-	if userIDsStr != "" {
-		for _, idStr := range strings.Split(userIDsStr, ",") {
-			id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
-			if err == nil {
-				userIDs = append(userIDs, id)
-			}
-		}
-	}
+	userIDs := parseIDList(getEnv("AUTHORIZED_USER_IDS", ""))
+	adminIDs := parseIDList(getEnv("ADMIN_USER_IDS", ""))
 
 	checkInterval, _ := time.ParseDuration(getEnv("ALERT_CHECK_INTERVAL", "1m"))
 	tempThreshold, _ := strconv.ParseFloat(getEnv("TEMPERATURE_ALERT_THRESHOLD", "30"), 64)
 
 	return TelegramBotConfig{
 		Token:                     getEnv("TELEGRAM_BOT_TOKEN", ""),
+		APIEndpoint:               getEnv("TELEGRAM_API_ENDPOINT", "https://api.telegram.org/bot%s/%s"),
 		AuthorizedUserIDs:         userIDs,
+		AdminUserIDs:              adminIDs,
 		AlertCheckInterval:        checkInterval,
 		TemperatureAlertThreshold: tempThreshold,
+		Mode:                      getEnv("TELEGRAM_MODE", "polling"),
+		WebhookURL:                getEnv("TELEGRAM_WEBHOOK_URL", ""),
+		WebhookListenAddr:         getEnv("TELEGRAM_WEBHOOK_LISTEN_ADDR", ":8443"),
+		WebhookSecret:             getEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+		WebhookCertFile:           getEnv("TELEGRAM_WEBHOOK_CERT_FILE", ""),
+		WebhookKeyFile:            getEnv("TELEGRAM_WEBHOOK_KEY_FILE", ""),
+	}
+}
+
+// parseIDList разбирает список идентификаторов через запятую
+func parseIDList(s string) []int64 {
+	var ids []int64
+
+	if s != "" {
+		for _, idStr := range strings.Split(s, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+			if err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// loadSMTPConfig загружает настройки почты из переменных окружения
+func loadSMTPConfig() SMTPConfig {
+	port, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+
+	return SMTPConfig{
+		Host:     getEnv("SMTP_HOST", ""),
+		Port:     port,
+		User:     getEnv("SMTP_USER", ""),
+		Password: getEnv("SMTP_PASSWORD", ""),
+		From:     getEnv("SMTP_FROM", ""),
+	}
+}
+
+// loadAutomationConfig загружает настройки автоматизации из переменных окружения
+func loadAutomationConfig() (AutomationConfig, error) {
+	interval, _ := time.ParseDuration(getEnv("AUTOMATION_INTERVAL", "10s"))
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	dryRun, _ := strconv.ParseBool(getEnv("AUTOMATION_DRY_RUN", "false"))
+
+	rawMaxAge := getEnv("AUTOMATION_MAX_READING_AGE", "5m")
+	maxReadingAge, err := time.ParseDuration(rawMaxAge)
+	if err != nil || maxReadingAge < 0 {
+		return AutomationConfig{}, fmt.Errorf("invalid automation max reading age %q", rawMaxAge)
+	}
+
+	location, err := time.LoadLocation(getEnv("AUTOMATION_TIMEZONE", "Local"))
+	if err != nil {
+		return AutomationConfig{}, fmt.Errorf("invalid automation timezone: %w", err)
+	}
+
+	return AutomationConfig{
+		RulesFile:     getEnv("AUTOMATIONS_FILE", "configs/automations.yaml"),
+		Interval:      interval,
+		DryRun:        dryRun,
+		Location:      location,
+		MaxReadingAge: maxReadingAge,
+	}, nil
+}
+
+// loadEmulatorConfig загружает настройки эмулятора из переменных окружения
+func loadEmulatorConfig() (EmulatorConfig, error) {
+	seed, err := strconv.ParseInt(getEnv("EMULATOR_SEED", "0"), 10, 64)
+	if err != nil {
+		return EmulatorConfig{}, fmt.Errorf("invalid emulator seed: %w", err)
+	}
+
+	speed, err := strconv.ParseFloat(getEnv("EMULATOR_SPEED", "1"), 64)
+	if err != nil || speed <= 0 {
+		return EmulatorConfig{}, fmt.Errorf("invalid emulator speed %q", getEnv("EMULATOR_SPEED", "1"))
+	}
+
+	var start time.Time
+	if value := getEnv("EMULATOR_START", ""); value != "" {
+		if start, err = time.Parse(time.RFC3339, value); err != nil {
+			return EmulatorConfig{}, fmt.Errorf("invalid emulator start time: %w", err)
+		}
+	}
+
+	batchDays, err := strconv.Atoi(getEnv("EMULATOR_BATCH_DAYS", "0"))
+	if err != nil || batchDays < 0 {
+		return EmulatorConfig{}, fmt.Errorf("invalid emulator batch days %q", getEnv("EMULATOR_BATCH_DAYS", "0"))
+	}
+
+	mqttQoS, err := strconv.Atoi(getEnv("EMULATOR_MQTT_QOS", "0"))
+	if err != nil || mqttQoS < 0 || mqttQoS > 2 {
+		return EmulatorConfig{}, fmt.Errorf("invalid emulator MQTT QoS %q", getEnv("EMULATOR_MQTT_QOS", "0"))
+	}
+	mqttRetain, _ := strconv.ParseBool(getEnv("EMULATOR_MQTT_RETAIN", "true"))
+	deviceStates := getEnv("EMULATOR_DEVICE_STATES", "local")
+	if deviceStates != "local" && deviceStates != "db" {
+		return EmulatorConfig{}, fmt.Errorf("unknown emulator device states source %q", deviceStates)
+	}
+
+	mqttFormat := getEnv("EMULATOR_MQTT_FORMAT", "zigbee2mqtt")
+	if mqttFormat != "zigbee2mqtt" && mqttFormat != "tasmota" {
+		return EmulatorConfig{}, fmt.Errorf("unknown emulator MQTT format %q", mqttFormat)
+	}
+
+	return EmulatorConfig{
+		Seed:         seed,
+		Speed:        speed,
+		Start:        start,
+		BatchDays:    batchDays,
+		Output:       getEnv("EMULATOR_OUTPUT", "readings.ndjson"),
+		Scenario:     getEnv("EMULATOR_SCENARIO", ""),
+		Listen:       getEnv("EMULATOR_LISTEN", ":9092"),
+		PushURL:      getEnv("EMULATOR_PUSH_URL", ""),
+		PushToken:    getEnv("REMOTE_PUSH_TOKEN", ""),
+		DeviceStates: deviceStates,
+		MQTT: EmulatorMQTTConfig{
+			Broker:            getEnv("EMULATOR_MQTT_BROKER", ""),
+			Username:          getEnv("EMULATOR_MQTT_USERNAME", ""),
+			Password:          getEnv("EMULATOR_MQTT_PASSWORD", ""),
+			Format:            mqttFormat,
+			Topic:             getEnv("EMULATOR_MQTT_TOPIC", ""),
+			AvailabilityTopic: getEnv("EMULATOR_MQTT_AVAILABILITY_TOPIC", ""),
+			Retain:            mqttRetain,
+			QoS:               byte(mqttQoS),
+		},
+	}, nil
+}
+
+// loadCollectorConfig загружает настройки сборщика из переменных окружения
+func loadCollectorConfig() (CollectorConfig, error) {
+	cfg := CollectorConfig{
+		Sensors:     getEnv("COLLECTOR_SENSORS", "local"),
+		EmulatorURL: getEnv("EMULATOR_URL", "http://localhost:9092"),
+		RemoteMode:  getEnv("COLLECTOR_REMOTE_MODE", "poll"),
+		RemoteToken: getEnv("REMOTE_PUSH_TOKEN", ""),
+
+		LineProtocolTag:   getEnv("LINE_PROTOCOL_SENSOR_TAG", "sensor_id"),
+		LineProtocolToken: getEnv("LINE_PROTOCOL_TOKEN", ""),
+	}
+	cfg.LineProtocol, _ = strconv.ParseBool(getEnv("COLLECTOR_LINE_PROTOCOL", "false"))
+	if cfg.Sensors != "local" && cfg.Sensors != "remote" {
+		return CollectorConfig{}, fmt.Errorf("unknown collector sensors source %q", cfg.Sensors)
+	}
+	if cfg.RemoteMode != "poll" && cfg.RemoteMode != "push" {
+		return CollectorConfig{}, fmt.Errorf("unknown collector remote mode %q", cfg.RemoteMode)
+	}
+	if cfg.Sensors == "remote" && cfg.RemoteMode == "push" && cfg.RemoteToken == "" {
+		return CollectorConfig{}, fmt.Errorf("REMOTE_PUSH_TOKEN is required in push mode")
+	}
+	return cfg, nil
+}
+
+// ClimateConfig содержит настройки поддержания температуры в комнатах
+type ClimateConfig struct {
+	Interval time.Duration       `yaml:"interval"` // Период регулирования
+	Timezone string              `yaml:"timezone"` // Часовой пояс расписания уставок, по умолчанию UTC
+	Rooms    []ClimateRoomConfig `yaml:"rooms"`
+}
+
+// ClimateRoomConfig содержит настройки регулятора одной комнаты
+type ClimateRoomConfig struct {
+	Room            string           `yaml:"room"`             // Комната (часть ID датчика после типа)
+	Device          string           `yaml:"device"`           // ID обогревателя/кондиционера
+	Sensors         []string         `yaml:"sensors"`          // Датчики температуры, по умолчанию все датчики комнаты
+	Algorithm       string           `yaml:"algorithm"`        // "hysteresis" (по умолчанию) или "pid"
+	Hysteresis      float64          `yaml:"hysteresis"`       // Половина ширины зоны нечувствительности, °C
+	PID             PIDConfig        `yaml:"pid"`              // Коэффициенты ПИД-регулятора
+	DefaultSetpoint float64          `yaml:"default_setpoint"` // Уставка, если расписание не задано
+	Schedule        []SetpointConfig `yaml:"schedule"`         // Расписание уставок
+	StaleAfter      time.Duration    `yaml:"stale_after"`      // Через сколько показания считаются устаревшими
+	DutyWindow      time.Duration    `yaml:"duty_window"`      // Окно расчета коэффициента заполнения
+}
+
+// PIDConfig содержит коэффициенты ПИД-регулятора
+type PIDConfig struct {
+	Kp float64 `yaml:"kp"`
+	Ki float64 `yaml:"ki"`
+	Kd float64 `yaml:"kd"`
+}
+
+// SetpointConfig задает уставку, действующую с указанного времени
+type SetpointConfig struct {
+	At       string   `yaml:"at"`   // Время начала действия "HH:MM"
+	Days     []string `yaml:"days"` // Дни недели (mon, tue, ...), пусто - каждый день
+	Setpoint float64  `yaml:"setpoint"`
+}
+
+func (c ClimateConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("climate interval cannot be negative")
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid climate timezone %q: %w", c.Timezone, err)
+		}
+	}
+
+	rooms := make(map[string]bool)
+	for _, room := range c.Rooms {
+		if err := room.Validate(); err != nil {
+			return fmt.Errorf("climate room %q: %w", room.Room, err)
+		}
+		if rooms[room.Room] {
+			return fmt.Errorf("duplicate climate room %q", room.Room)
+		}
+		rooms[room.Room] = true
 	}
+	return nil
+}
+
+func (c ClimateRoomConfig) Validate() error {
+	if c.Room == "" {
+		return fmt.Errorf("room cannot be empty")
+	}
+	if c.Device == "" {
+		return fmt.Errorf("device cannot be empty")
+	}
+	switch c.Algorithm {
+	case "", "hysteresis":
+		if c.Hysteresis < 0 {
+			return fmt.Errorf("hysteresis cannot be negative")
+		}
+	case "pid":
+		if c.PID.Kp < 0 || c.PID.Ki < 0 || c.PID.Kd < 0 {
+			return fmt.Errorf("PID coefficients cannot be negative")
+		}
+		if c.PID.Kp == 0 && c.PID.Ki == 0 {
+			return fmt.Errorf("PID requires kp or ki")
+		}
+	default:
+		return fmt.Errorf("unknown algorithm %q", c.Algorithm)
+	}
+	if len(c.Schedule) == 0 && c.DefaultSetpoint == 0 {
+		return fmt.Errorf("schedule or default_setpoint is required")
+	}
+	for _, sp := range c.Schedule {
+		if _, err := ParseClock(sp.At); err != nil {
+			return fmt.Errorf("invalid schedule time %q", sp.At)
+		}
+		for _, day := range sp.Days {
+			if _, ok := ParseWeekday(day); !ok {
+				return fmt.Errorf("unknown weekday %q", day)
+			}
+		}
+	}
+	if c.StaleAfter < 0 || c.DutyWindow < 0 {
+		return fmt.Errorf("durations cannot be negative")
+	}
+	return nil
+}
+
+// ParseClock разбирает время суток "HH:MM" и возвращает смещение от полуночи.
+// Общий разбор для расписаний отчетов, автоматизаций, климата и жильцов
+func ParseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", s)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", s)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// weekdays сопоставляет сокращенные названия дней недели с time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWeekday разбирает сокращенное название дня недели (mon, tue, ...)
+func ParseWeekday(s string) (time.Weekday, bool) {
+	day, ok := weekdays[strings.ToLower(s)]
+	return day, ok
 }
 
 // getEnv возвращает значение переменной окружения или значение по умолчанию
@@ -142,72 +823,506 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// SensorsConfig содержит датчики. В файле конфигурации они задаются списком
+// записей с типом из реестра типов датчиков (пакет sensortype):
+//
+//	sensors:
+//	  - type: temperature
+//	    id: temp_kitchen
+//	    interval: 30s
+//	    params: {min: 17, max: 29}
+//
+// Запись с блоком replay или remote описывает датчик, воспроизводящий
+// записанные показания, или сетевой датчик. Прежний формат со списками по
+// типам (temperature:, motion:, air_quality:, replay:, remote:) тоже
+// поддерживается: параметры датчика тогда задаются рядом с id
 type SensorsConfig struct {
-	Temperature []TemperatureConfig `yaml:"temperature"`
-	Motion      []MotionConfig      `yaml:"motion"`
-	AirQuality  []AirQualityConfig  `yaml:"air_quality"`
+	List   []SensorEntry        // Эмулируемые датчики
+	Replay []ReplayConfig       // Воспроизведение записанных показаний
+	Remote []RemoteSensorConfig // Сетевые датчики
 }
 
-type TemperatureConfig struct {
-	ID       string        `yaml:"id"`
-	Min      float64       `yaml:"min"`
-	Max      float64       `yaml:"max"`
-	Interval time.Duration `yaml:"interval"`
+// SensorEntry описывает датчик: общие настройки и параметры, которые
+// разбирает тип датчика
+type SensorEntry struct {
+	Type     string              `yaml:"type"`
+	ID       string              `yaml:"id"`
+	Interval time.Duration       `yaml:"interval"`
+	Seed     int64               `yaml:"seed"`   // Зерно генератора датчика, по умолчанию выводится из общего зерна эмулятора
+	Faults   FaultsConfig        `yaml:"faults"` // Имитация неисправностей
+	Params   yaml.Node           `yaml:"params"` // Параметры типа датчика
+	Replay   *ReplayConfig       `yaml:"replay"` // Воспроизведение записанных показаний вместо модели
+	Remote   *RemoteSensorConfig `yaml:"remote"` // Сетевой датчик вместо модели
 }
 
-func (c TemperatureConfig) Validate() error {
+// DecodeParams разбирает параметры датчика в структуру типа
+func (e SensorEntry) DecodeParams(v interface{}) error {
+	if e.Params.Kind == 0 {
+		return nil
+	}
+	if err := e.Params.Decode(v); err != nil {
+		return fmt.Errorf("invalid params of sensor %s: %w", e.ID, err)
+	}
+	return nil
+}
+
+func (e SensorEntry) Validate() error {
+	if e.ID == "" || e.Type == "" {
+		return fmt.Errorf("sensor ID and type cannot be empty")
+	}
+	if e.Interval <= 0 {
+		return fmt.Errorf("polling interval of sensor %s must be positive", e.ID)
+	}
+	return e.Faults.Validate()
+}
+
+// UnmarshalYAML разбирает список датчиков или прежний формат со списками по типам
+func (c *SensorsConfig) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var entries []SensorEntry
+		if err := node.Decode(&entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			c.add(entry)
+		}
+		return nil
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch key {
+			case "replay":
+				var replay []ReplayConfig
+				if err := value.Decode(&replay); err != nil {
+					return err
+				}
+				c.Replay = append(c.Replay, replay...)
+			case "remote":
+				var remote []RemoteSensorConfig
+				if err := value.Decode(&remote); err != nil {
+					return err
+				}
+				c.Remote = append(c.Remote, remote...)
+			default:
+				// Список датчиков типа key, параметры - в той же записи
+				var items []yaml.Node
+				if err := value.Decode(&items); err != nil {
+					return err
+				}
+				for _, item := range items {
+					var entry SensorEntry
+					if err := item.Decode(&entry); err != nil {
+						return err
+					}
+					entry.Type = key
+					entry.Params = item
+					c.add(entry)
+				}
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("line %d: sensors must be a list", node.Line)
+}
+
+// add распределяет запись по видам датчиков
+func (c *SensorsConfig) add(entry SensorEntry) {
+	switch {
+	case entry.Replay != nil:
+		replay := *entry.Replay
+		replay.ID, replay.Type, replay.Faults = entry.ID, entry.Type, entry.Faults
+		if replay.Interval == 0 {
+			replay.Interval = entry.Interval
+		}
+		c.Replay = append(c.Replay, replay)
+	case entry.Remote != nil:
+		remote := *entry.Remote
+		remote.ID, remote.Type = entry.ID, entry.Type
+		if remote.Interval == 0 {
+			remote.Interval = entry.Interval
+		}
+		c.Remote = append(c.Remote, remote)
+	default:
+		c.List = append(c.List, entry)
+	}
+}
+
+// ReplayConfig описывает датчик, воспроизводящий записанные показания из файла
+// экспорта (CSV или NDJSON) или из таблицы sensor_readings за период
+type ReplayConfig struct {
+	ID                 string        `yaml:"id"`
+	Type               string        `yaml:"type"`                // Тип датчика, по умолчанию берется из записей
+	File               string        `yaml:"file"`                // Файл .csv или .ndjson; пусто - чтение из БД
+	Source             string        `yaml:"source"`              // ID датчика в записанных данных, по умолчанию ID
+	From               time.Time     `yaml:"from"`                // Начало периода записи
+	To                 time.Time     `yaml:"to"`                  // Конец периода записи
+	Speed              float64       `yaml:"speed"`               // Ускорение воспроизведения, по умолчанию 1 (исходные интервалы)
+	Loop               bool          `yaml:"loop"`                // Повторять запись по кругу
+	OriginalTimestamps bool          `yaml:"original_timestamps"` // Сохранять исходные метки времени вместо текущих
+	Interval           time.Duration `yaml:"interval"`            // Период опроса, по умолчанию по минимальному интервалу записи
+	Faults             FaultsConfig  `yaml:"faults"`              // Имитация неисправностей
+}
+
+// SourceID возвращает ID датчика в записанных данных
+func (c ReplayConfig) SourceID() string {
+	if c.Source != "" {
+		return c.Source
+	}
+	return c.ID
+}
+
+func (c ReplayConfig) Validate() error {
 	if c.ID == "" {
 		return fmt.Errorf("sensor ID cannot be empty")
 	}
-	if c.Min >= c.Max {
-		return fmt.Errorf("minimum temperature must be less than maximum")
+	if c.File == "" && (c.From.IsZero() || c.To.IsZero()) {
+		return fmt.Errorf("replay from the database requires a time range")
+	}
+	if !c.From.IsZero() && !c.To.IsZero() && !c.From.Before(c.To) {
+		return fmt.Errorf("replay start must be before end")
+	}
+	if c.Speed < 0 || c.Interval < 0 {
+		return fmt.Errorf("replay speed and interval cannot be negative")
+	}
+	return c.Faults.Validate()
+}
+
+// RemoteSensorConfig описывает сетевой датчик, который сборщик опрашивает по
+// HTTP (poll) или от которого принимает показания (push)
+type RemoteSensorConfig struct {
+	ID       string        `yaml:"id"`
+	Type     string        `yaml:"type"`
+	URL      string        `yaml:"url"`      // poll: адрес последнего показания
+	Mode     string        `yaml:"mode"`     // poll (по умолчанию) или push
+	Interval time.Duration `yaml:"interval"` // Период опроса датчика или очереди push
+	Timeout  time.Duration `yaml:"timeout"`  // Таймаут запроса, по умолчанию 5s
+}
+
+func (c RemoteSensorConfig) Validate() error {
+	if c.ID == "" || c.Type == "" {
+		return fmt.Errorf("sensor ID and type cannot be empty")
 	}
 	if c.Interval <= 0 {
 		return fmt.Errorf("polling interval must be positive")
 	}
+	switch c.Mode {
+	case "", "poll":
+		if c.URL == "" {
+			return fmt.Errorf("URL is required for polled sensor %s", c.ID)
+		}
+	case "push":
+	default:
+		return fmt.Errorf("unknown remote sensor mode %q", c.Mode)
+	}
+	return nil
+}
+
+// FaultsConfig описывает неисправности, которые эмулятор добавляет к показаниям
+// датчика. Вероятности задаются на один опрос
+type FaultsConfig struct {
+	NotReady     float64           `yaml:"not_ready"`      // Вероятность ответа "датчик не готов"
+	Error        float64           `yaml:"error"`          // Вероятность ошибки чтения
+	Stuck        FaultWindowConfig `yaml:"stuck"`          // Залипание: датчик повторяет последнее значение
+	Spike        SpikeConfig       `yaml:"spike"`          // Одиночные выбросы
+	DriftPerHour float64           `yaml:"drift_per_hour"` // Дрейф числовых значений в единицах измерения за час
+	Slow         SlowReadConfig    `yaml:"slow"`           // Медленные чтения и таймауты
+	Outage       FaultWindowConfig `yaml:"outage"`         // Полная недоступность датчика
+}
+
+// FaultWindowConfig описывает неисправность, которая начинается с заданной
+// вероятностью и длится Duration
+type FaultWindowConfig struct {
+	Probability float64       `yaml:"probability"`
+	Duration    time.Duration `yaml:"duration"`
+}
+
+// SpikeConfig описывает выбросы: числовые значения отклоняются на ±Magnitude
+// (доля значения), логические инвертируются
+type SpikeConfig struct {
+	Probability float64 `yaml:"probability"`
+	Magnitude   float64 `yaml:"magnitude"`
+}
+
+// SlowReadConfig описывает медленные чтения: с вероятностью Probability чтение
+// длится Delay, а если Delay не меньше Timeout, завершается ошибкой таймаута
+type SlowReadConfig struct {
+	Probability float64       `yaml:"probability"`
+	Delay       time.Duration `yaml:"delay"`
+	Timeout     time.Duration `yaml:"timeout"`
+}
+
+// Enabled сообщает, задана ли хотя бы одна неисправность
+func (c FaultsConfig) Enabled() bool {
+	return c.NotReady > 0 || c.Error > 0 || c.Stuck.Probability > 0 || c.Spike.Probability > 0 ||
+		c.DriftPerHour != 0 || c.Slow.Probability > 0 || c.Outage.Probability > 0
+}
+
+func (c FaultsConfig) Validate() error {
+	for name, p := range map[string]float64{
+		"not_ready":          c.NotReady,
+		"error":              c.Error,
+		"stuck probability":  c.Stuck.Probability,
+		"spike probability":  c.Spike.Probability,
+		"slow probability":   c.Slow.Probability,
+		"outage probability": c.Outage.Probability,
+	} {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("fault %s must be between 0 and 1", name)
+		}
+	}
+	if c.Stuck.Probability > 0 && c.Stuck.Duration <= 0 {
+		return fmt.Errorf("stuck fault duration must be positive")
+	}
+	if c.Outage.Probability > 0 && c.Outage.Duration <= 0 {
+		return fmt.Errorf("outage duration must be positive")
+	}
+	if c.Spike.Magnitude < 0 {
+		return fmt.Errorf("spike magnitude cannot be negative")
+	}
+	if c.Slow.Delay < 0 || c.Slow.Timeout < 0 {
+		return fmt.Errorf("slow read delay and timeout cannot be negative")
+	}
 	return nil
 }
 
-type MotionConfig struct {
-	ID                string        `yaml:"id"`
-	DetectionInterval time.Duration `yaml:"detection_interval"`
-	Interval          time.Duration `yaml:"interval"`
+type DevicesConfig struct {
+	Lights      []LightConfig      `yaml:"lights"`
+	Thermostats []ThermostatConfig `yaml:"thermostats"`
+	Fans        []FanConfig        `yaml:"fans"`
+	Plugs       []PlugConfig       `yaml:"plugs"`
+	HVAC        []HVACConfig       `yaml:"hvac"`
 }
 
-func (c MotionConfig) Validate() error {
+type LightConfig struct {
+	ID         string `yaml:"id"`
+	Dimmable   bool   `yaml:"dimmable"`
+	Brightness int    `yaml:"brightness"`
+}
+
+func (c LightConfig) Validate() error {
 	if c.ID == "" {
-		return fmt.Errorf("sensor ID cannot be empty")
+		return fmt.Errorf("device ID cannot be empty")
 	}
-	if c.DetectionInterval <= 0 {
-		return fmt.Errorf("detection interval must be positive")
+	if c.Brightness < 0 || c.Brightness > 100 {
+		return fmt.Errorf("brightness must be between 0 and 100")
 	}
-	if c.Interval <= 0 {
-		return fmt.Errorf("polling interval must be positive")
+	return nil
+}
+
+type ThermostatConfig struct {
+	ID          string  `yaml:"id"`
+	Setpoint    float64 `yaml:"setpoint"`
+	MinSetpoint float64 `yaml:"min_setpoint"`
+	MaxSetpoint float64 `yaml:"max_setpoint"`
+}
+
+func (c ThermostatConfig) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("device ID cannot be empty")
+	}
+	if c.MinSetpoint >= c.MaxSetpoint {
+		return fmt.Errorf("minimum setpoint must be less than maximum")
+	}
+	if c.Setpoint < c.MinSetpoint || c.Setpoint > c.MaxSetpoint {
+		return fmt.Errorf("setpoint must be within the setpoint range")
 	}
 	return nil
 }
 
-type AirQualityConfig struct {
-	ID       string        `yaml:"id"`
-	MinCO2   int           `yaml:"min_co2"`
-	MaxCO2   int           `yaml:"max_co2"`
-	MinNH3   int           `yaml:"min_nh3"`
-	MaxNH3   int           `yaml:"max_nh3"`
-	Interval time.Duration `yaml:"interval"`
+type FanConfig struct {
+	ID string `yaml:"id"`
 }
 
-func (c AirQualityConfig) Validate() error {
+func (c FanConfig) Validate() error {
 	if c.ID == "" {
-		return fmt.Errorf("sensor ID cannot be empty")
+		return fmt.Errorf("device ID cannot be empty")
 	}
-	if c.MinCO2 >= c.MaxCO2 {
-		return fmt.Errorf("minimum CO2 level must be less than maximum")
+	return nil
+}
+
+type PlugConfig struct {
+	ID    string  `yaml:"id"`
+	LoadW float64 `yaml:"load_w"`
+}
+
+func (c PlugConfig) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("device ID cannot be empty")
 	}
-	if c.MinNH3 >= c.MaxNH3 {
-		return fmt.Errorf("minimum NH3 level must be less than maximum")
+	if c.LoadW < 0 {
+		return fmt.Errorf("load cannot be negative")
 	}
-	if c.Interval <= 0 {
-		return fmt.Errorf("polling interval must be positive")
+	return nil
+}
+
+type HVACConfig struct {
+	ID      string  `yaml:"id"`
+	Heating bool    `yaml:"heating"`
+	Cooling bool    `yaml:"cooling"`
+	PowerW  float64 `yaml:"power_w"`
+}
+
+func (c HVACConfig) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("device ID cannot be empty")
+	}
+	if !c.Heating && !c.Cooling {
+		return fmt.Errorf("device must support heating or cooling")
+	}
+	if c.PowerW < 0 {
+		return fmt.Errorf("power cannot be negative")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// EnvironmentConfig содержит параметры физической модели комнат эмулятора
+type EnvironmentConfig struct {
+	Step           time.Duration       `yaml:"step"`            // Шаг моделирования
+	Outdoor        OutdoorConfig       `yaml:"outdoor"`         // Условия снаружи
+	VentilationM3H map[string]float64  `yaml:"ventilation_m3h"` // Производительность вентилятора по скоростям, м³/ч
+	Rooms          []RoomPhysicsConfig `yaml:"rooms"`
+}
+
+// OutdoorConfig описывает условия снаружи дома
+type OutdoorConfig struct {
+	Temperature float64 `yaml:"temperature"` // Температура, °C
+	CO2         float64 `yaml:"co2"`         // Уровень CO2, ppm
+}
+
+// RoomPhysicsConfig описывает физические параметры комнаты
+type RoomPhysicsConfig struct {
+	Room               string   `yaml:"room"`                   // Комната (часть ID датчика после типа)
+	VolumeM3           float64  `yaml:"volume_m3"`              // Объем воздуха, м³
+	HeatCapacityKJPerK float64  `yaml:"heat_capacity_kj_per_k"` // Теплоемкость комнаты с мебелью и стенами, кДж/К
+	HeatLossWPerK      float64  `yaml:"heat_loss_w_per_k"`      // Теплопотери через ограждения, Вт/К
+	AirChangesPerHour  float64  `yaml:"air_changes_per_hour"`   // Естественный воздухообмен (инфильтрация), 1/ч
+	InitialTemperature float64  `yaml:"initial_temperature"`    // Начальная температура, °C
+	InitialCO2         float64  `yaml:"initial_co2"`            // Начальный уровень CO2, ppm
+	Occupants          int      `yaml:"occupants"`              // Количество людей в комнате
+	NH3PPMPerHour      float64  `yaml:"nh3_ppm_per_hour"`       // Выделение NH3 (кухня, санузел), ppm/ч
+	BaseHeatW          float64  `yaml:"base_heat_w"`            // Постоянные теплопоступления (центральное отопление), Вт
+	Devices            []string `yaml:"devices"`                // Устройства, влияющие на комнату
+}
+
+func (c EnvironmentConfig) Validate() error {
+	if c.Step < 0 {
+		return fmt.Errorf("environment step cannot be negative")
+	}
+	if c.Outdoor.CO2 < 0 {
+		return fmt.Errorf("outdoor CO2 cannot be negative")
+	}
+	for speed, flow := range c.VentilationM3H {
+		if flow < 0 {
+			return fmt.Errorf("ventilation flow for speed %q cannot be negative", speed)
+		}
+	}
+
+	rooms := make(map[string]bool)
+	for _, room := range c.Rooms {
+		if err := room.Validate(); err != nil {
+			return fmt.Errorf("environment room %q: %w", room.Room, err)
+		}
+		if rooms[room.Room] {
+			return fmt.Errorf("duplicate environment room %q", room.Room)
+		}
+		rooms[room.Room] = true
+	}
+	return nil
+}
+
+func (c RoomPhysicsConfig) Validate() error {
+	if c.Room == "" {
+		return fmt.Errorf("room cannot be empty")
+	}
+	if c.VolumeM3 < 0 || c.HeatCapacityKJPerK < 0 || c.HeatLossWPerK < 0 || c.AirChangesPerHour < 0 {
+		return fmt.Errorf("physical parameters cannot be negative")
+	}
+	if c.BaseHeatW < 0 {
+		return fmt.Errorf("base heat cannot be negative")
+	}
+	if c.InitialCO2 < 0 || c.NH3PPMPerHour < 0 {
+		return fmt.Errorf("gas parameters cannot be negative")
+	}
+	if c.Occupants < 0 {
+		return fmt.Errorf("occupants cannot be negative")
+	}
+	return nil
+}
+
+// OccupancyConfig описывает жильцов дома и их распорядок для эмуляции присутствия
+type OccupancyConfig struct {
+	Timezone   string              `yaml:"timezone"`   // Часовой пояс распорядка, по умолчанию UTC
+	Transition time.Duration       `yaml:"transition"` // Время перехода между соседними комнатами
+	Entrance   string              `yaml:"entrance"`   // Комната, через которую жильцы входят и выходят из дома
+	House      map[string][]string `yaml:"house"`      // Граф дома: комната -> соседние комнаты
+	People     []PersonConfig      `yaml:"people"`
+}
+
+// PersonConfig описывает распорядок одного жильца
+type PersonConfig struct {
+	Name              string          `yaml:"name"`
+	WanderProbability float64         `yaml:"wander_probability"` // Вероятность отойти в соседнюю комнату за шаг
+	Weekday           []ScheduleEntry `yaml:"weekday"`            // Распорядок по будням
+	Weekend           []ScheduleEntry `yaml:"weekend"`            // Распорядок по выходным (по умолчанию как в будни)
+}
+
+// ScheduleEntry - пункт распорядка: с момента At жилец находится в Room
+type ScheduleEntry struct {
+	At       string `yaml:"at"`       // Время "HH:MM"
+	Room     string `yaml:"room"`     // Комната или "away" (нет дома)
+	Activity string `yaml:"activity"` // active (по умолчанию) или sleep
+}
+
+func (c OccupancyConfig) Validate() error {
+	if len(c.People) == 0 {
+		return nil
+	}
+	if c.Transition < 0 {
+		return fmt.Errorf("transition cannot be negative")
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid occupancy timezone %q: %w", c.Timezone, err)
+		}
+	}
+
+	rooms := make(map[string]bool)
+	for room, neighbours := range c.House {
+		rooms[room] = true
+		for _, n := range neighbours {
+			rooms[n] = true
+		}
+	}
+	if !rooms[c.Entrance] {
+		return fmt.Errorf("entrance %q is not in the house graph", c.Entrance)
+	}
+
+	for _, person := range c.People {
+		if person.Name == "" {
+			return fmt.Errorf("person name cannot be empty")
+		}
+		if person.WanderProbability < 0 || person.WanderProbability > 1 {
+			return fmt.Errorf("person %s: wander probability must be between 0 and 1", person.Name)
+		}
+		if len(person.Weekday) == 0 {
+			return fmt.Errorf("person %s: weekday schedule is required", person.Name)
+		}
+		for _, entry := range append(append([]ScheduleEntry{}, person.Weekday...), person.Weekend...) {
+			if _, err := ParseClock(entry.At); err != nil {
+				return fmt.Errorf("person %s: invalid schedule time %q", person.Name, entry.At)
+			}
+			if entry.Room != "away" && !rooms[entry.Room] {
+				return fmt.Errorf("person %s: room %q is not in the house graph", person.Name, entry.Room)
+			}
+			switch entry.Activity {
+			case "", "active", "sleep":
+			default:
+				return fmt.Errorf("person %s: unknown activity %q", person.Name, entry.Activity)
+			}
+		}
+	}
+	return nil
+}
@@ -2,13 +2,18 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/4Amangel1/smart-house-automate/internal/models"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// ErrNotFound возвращается, если запрошенная запись не найдена
+var ErrNotFound = errors.New("not found")
+
 type Repository struct {
 	db *sql.DB
 }
@@ -38,6 +43,36 @@ func (r *Repository) Close() error {
 
 // SaveReading сохраняет показания датчика в БД
 func (r *Repository) SaveReading(data models.SensorData) error {
+	return insertReading(r.db, data)
+}
+
+// SaveReadings сохраняет пакет показаний в одной транзакции: при ошибке не
+// сохраняется ни одно показание
+func (r *Repository) SaveReadings(readings []models.SensorData) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, data := range readings {
+		if err := insertReading(tx, data); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit readings: %w", err)
+	}
+	return nil
+}
+
+// rowQuerier - соединение или транзакция
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func insertReading(db rowQuerier, data models.SensorData) error {
 	query := `
 		INSERT INTO sensor_readings (sensor_id, sensor_type, timestamp, value, unit, metadata, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
@@ -55,7 +90,7 @@ func (r *Repository) SaveReading(data models.SensorData) error {
 	}
 
 	var id int64
-	err = r.db.QueryRow(
+	err = db.QueryRow(
 		query,
 		data.SensorID,
 		data.SensorType,
@@ -129,6 +164,567 @@ func (r *Repository) GetReadingHistory(sensorID string, limit int) ([]models.Sen
 	return r.scanReadings(rows)
 }
 
+// GetReadingsInRange возвращает показания датчика за период в хронологическом порядке
+func (r *Repository) GetReadingsInRange(sensorID string, from, to time.Time) ([]models.SensorData, error) {
+	query := `
+		SELECT id, sensor_id, sensor_type, timestamp, value, unit, metadata, created_at
+		FROM sensor_readings
+		WHERE sensor_id = $1 AND timestamp >= $2 AND timestamp <= $3
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.Query(query, sensorID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query readings in range: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanReadings(rows)
+}
+
+// GetReadingsByTypeInRange возвращает показания датчиков определенного типа за период
+func (r *Repository) GetReadingsByTypeInRange(sensorType string, from, to time.Time) ([]models.SensorData, error) {
+	query := `
+		SELECT id, sensor_id, sensor_type, timestamp, value, unit, metadata, created_at
+		FROM sensor_readings
+		WHERE sensor_type = $1 AND timestamp >= $2 AND timestamp <= $3
+		ORDER BY sensor_id, timestamp ASC
+	`
+
+	rows, err := r.db.Query(query, sensorType, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query readings by type in range: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanReadings(rows)
+}
+
+// SaveAlert сохраняет сработавшее оповещение
+func (r *Repository) SaveAlert(alert models.Alert) error {
+	query := `
+		INSERT INTO alerts (sensor_id, alert_type, message, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if alert.CreatedAt.IsZero() {
+		alert.CreatedAt = time.Now().UTC()
+	}
+
+	if _, err := r.db.Exec(query, alert.SensorID, alert.AlertType, alert.Message, alert.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save alert: %w", err)
+	}
+
+	return nil
+}
+
+// GetAlertsInRange возвращает оповещения, сработавшие за период
+func (r *Repository) GetAlertsInRange(from, to time.Time) ([]models.Alert, error) {
+	query := `
+		SELECT id, sensor_id, alert_type, message, created_at
+		FROM alerts
+		WHERE created_at >= $1 AND created_at <= $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		var alert models.Alert
+		if err := rows.Scan(&alert.ID, &alert.SensorID, &alert.AlertType, &alert.Message, &alert.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// GetLastAlertID возвращает ID последнего сохраненного оповещения, 0 - оповещений нет
+func (r *Repository) GetLastAlertID() (int64, error) {
+	var id int64
+	if err := r.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM alerts`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to query last alert ID: %w", err)
+	}
+	return id, nil
+}
+
+// GetAlertsAfter возвращает оповещения типа alertType с ID больше afterID в
+// порядке сохранения
+func (r *Repository) GetAlertsAfter(afterID int64, alertType string) ([]models.Alert, error) {
+	query := `
+		SELECT id, sensor_id, alert_type, message, created_at
+		FROM alerts
+		WHERE id > $1 AND alert_type = $2
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Query(query, afterID, alertType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		var alert models.Alert
+		if err := rows.Scan(&alert.ID, &alert.SensorID, &alert.AlertType, &alert.Message, &alert.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// GetUserPreferences возвращает настройки пользователя или nil, если они не сохранены
+func (r *Repository) GetUserPreferences(userID int64) (*models.UserPreferences, error) {
+	query := `
+		SELECT user_id, language, units, updated_at
+		FROM user_preferences
+		WHERE user_id = $1
+	`
+
+	var prefs models.UserPreferences
+	err := r.db.QueryRow(query, userID).Scan(&prefs.UserID, &prefs.Language, &prefs.Units, &prefs.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+// SaveUserPreferences сохраняет настройки пользователя
+func (r *Repository) SaveUserPreferences(prefs models.UserPreferences) error {
+	query := `
+		INSERT INTO user_preferences (user_id, language, units, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET language = EXCLUDED.language, units = EXCLUDED.units, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.Exec(query, prefs.UserID, prefs.Language, prefs.Units, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+
+	return nil
+}
+
+// GetSensorType возвращает тип датчика по его последнему показанию
+func (r *Repository) GetSensorType(sensorID string) (string, error) {
+	query := `
+		SELECT sensor_type
+		FROM sensor_readings
+		WHERE sensor_id = $1
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	var sensorType string
+	err := r.db.QueryRow(query, sensorID).Scan(&sensorType)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get sensor type: %w", err)
+	}
+
+	return sensorType, nil
+}
+
+// SaveSensor добавляет датчик в реестр или обновляет его описание
+func (r *Repository) SaveSensor(sensor models.SensorInfo) error {
+	query := `
+		INSERT INTO sensors (id, type, name, location, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (id) DO UPDATE
+		SET type = EXCLUDED.type,
+		    name = EXCLUDED.name,
+		    location = EXCLUDED.location,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(query, sensor.ID, sensor.Type, sensor.Name, sensor.Location, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save sensor: %w", err)
+	}
+
+	return nil
+}
+
+// GetSensor возвращает датчик из реестра
+func (r *Repository) GetSensor(sensorID string) (*models.SensorInfo, error) {
+	query := `
+		SELECT id, type, COALESCE(name, ''), COALESCE(location, ''), created_at, updated_at
+		FROM sensors
+		WHERE id = $1
+	`
+
+	var sensor models.SensorInfo
+	err := r.db.QueryRow(query, sensorID).Scan(
+		&sensor.ID, &sensor.Type, &sensor.Name, &sensor.Location, &sensor.CreatedAt, &sensor.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sensor: %w", err)
+	}
+
+	return &sensor, nil
+}
+
+// GetSensors возвращает датчики реестра по идентификаторам одним запросом.
+// Незарегистрированных датчиков в результате нет
+func (r *Repository) GetSensors(sensorIDs []string) (map[string]*models.SensorInfo, error) {
+	query := `
+		SELECT id, type, COALESCE(name, ''), COALESCE(location, ''), created_at, updated_at
+		FROM sensors
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.db.Query(query, pq.Array(sensorIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sensors: %w", err)
+	}
+	defer rows.Close()
+
+	sensors := make(map[string]*models.SensorInfo, len(sensorIDs))
+	for rows.Next() {
+		var sensor models.SensorInfo
+		if err := rows.Scan(
+			&sensor.ID, &sensor.Type, &sensor.Name, &sensor.Location, &sensor.CreatedAt, &sensor.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sensor: %w", err)
+		}
+		sensors[sensor.ID] = &sensor
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sensors: %w", err)
+	}
+
+	return sensors, nil
+}
+
+// GetAlertRules возвращает все правила оповещений
+func (r *Repository) GetAlertRules() ([]models.AlertRule, error) {
+	query := `
+		SELECT id, sensor_id, field, operator, threshold, duration_seconds, created_by, created_at, updated_at
+		FROM alert_rules
+		ORDER BY sensor_id, id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.AlertRule
+	for rows.Next() {
+		var rule models.AlertRule
+		var durationSeconds int64
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.SensorID,
+			&rule.Field,
+			&rule.Operator,
+			&rule.Threshold,
+			&durationSeconds,
+			&rule.CreatedBy,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		rule.Duration = time.Duration(durationSeconds) * time.Second
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SaveAlertRule создает правило или обновляет существующее с тем же датчиком,
+// полем и оператором; возвращает ID правила и признак создания
+func (r *Repository) SaveAlertRule(rule models.AlertRule) (int64, bool, error) {
+	now := time.Now().UTC()
+	durationSeconds := int64(rule.Duration / time.Second)
+
+	// xmax = 0 только у строки, вставленной этим запросом
+	var id int64
+	var created bool
+	err := r.db.QueryRow(`
+		INSERT INTO alert_rules (sensor_id, field, operator, threshold, duration_seconds, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (sensor_id, field, operator) DO UPDATE
+		SET threshold = EXCLUDED.threshold, duration_seconds = EXCLUDED.duration_seconds, updated_at = EXCLUDED.updated_at
+		RETURNING id, xmax = 0
+	`, rule.SensorID, rule.Field, rule.Operator, rule.Threshold, durationSeconds, rule.CreatedBy, now).Scan(&id, &created)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to save alert rule: %w", err)
+	}
+
+	return id, created, nil
+}
+
+// DeleteAlertRule удаляет правило оповещения
+func (r *Repository) DeleteAlertRule(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SaveDeviceEvent сохраняет команду или изменение состояния устройства
+func (r *Repository) SaveDeviceEvent(event models.DeviceEvent) error {
+	query := `
+		INSERT INTO device_events (device_id, device_type, event_type, command, state, source, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	var commandJSON, stateJSON []byte
+	var err error
+	if event.Command != nil {
+		if commandJSON, err = json.Marshal(event.Command); err != nil {
+			return fmt.Errorf("failed to convert command to JSON: %w", err)
+		}
+	}
+	if event.State != nil {
+		if stateJSON, err = json.Marshal(event.State); err != nil {
+			return fmt.Errorf("failed to convert state to JSON: %w", err)
+		}
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	if _, err := r.db.Exec(query,
+		event.DeviceID,
+		event.DeviceType,
+		event.EventType,
+		commandJSON,
+		stateJSON,
+		event.Source,
+		event.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save device event: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeviceEvents возвращает последние события устройства
+func (r *Repository) GetDeviceEvents(deviceID string, limit int) ([]models.DeviceEvent, error) {
+	query := `
+		SELECT id, device_id, device_type, event_type, command, state, source, created_at
+		FROM device_events
+		WHERE device_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, deviceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query device events: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanDeviceEvents(rows)
+}
+
+// GetLatestDeviceStates возвращает последнее сохраненное состояние каждого устройства
+func (r *Repository) GetLatestDeviceStates() (map[string]models.ActuatorState, error) {
+	query := `
+		SELECT DISTINCT ON (device_id) device_id, state
+		FROM device_events
+		WHERE event_type = $1 AND state IS NOT NULL
+		ORDER BY device_id, created_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, models.DeviceEventState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query device states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]models.ActuatorState)
+	for rows.Next() {
+		var deviceID string
+		var stateBytes []byte
+		if err := rows.Scan(&deviceID, &stateBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan device state: %w", err)
+		}
+
+		var state models.ActuatorState
+		if err := json.Unmarshal(stateBytes, &state); err != nil {
+			return nil, fmt.Errorf("failed to scan state: %w", err)
+		}
+		states[deviceID] = state
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return states, nil
+}
+
+// scanDeviceEvents сканирует события устройств
+func (r *Repository) scanDeviceEvents(rows *sql.Rows) ([]models.DeviceEvent, error) {
+	var events []models.DeviceEvent
+
+	for rows.Next() {
+		var event models.DeviceEvent
+		var commandBytes, stateBytes []byte
+
+		if err := rows.Scan(
+			&event.ID,
+			&event.DeviceID,
+			&event.DeviceType,
+			&event.EventType,
+			&commandBytes,
+			&stateBytes,
+			&event.Source,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan device event: %w", err)
+		}
+
+		if len(commandBytes) > 0 {
+			event.Command = &models.Command{}
+			if err := json.Unmarshal(commandBytes, event.Command); err != nil {
+				return nil, fmt.Errorf("failed to scan command: %w", err)
+			}
+		}
+		if len(stateBytes) > 0 {
+			if err := json.Unmarshal(stateBytes, &event.State); err != nil {
+				return nil, fmt.Errorf("failed to scan state: %w", err)
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// SaveAutomationRun сохраняет запись о выполнении правила автоматизации
+func (r *Repository) SaveAutomationRun(run models.AutomationRun) error {
+	query := `
+		INSERT INTO automation_runs (rule_id, trigger, dry_run, success, actions, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	actionsJSON, err := json.Marshal(run.Actions)
+	if err != nil {
+		return fmt.Errorf("failed to convert actions to JSON: %w", err)
+	}
+
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now().UTC()
+	}
+
+	if _, err := r.db.Exec(query,
+		run.RuleID,
+		run.Trigger,
+		run.DryRun,
+		run.Success,
+		actionsJSON,
+		sql.NullString{String: run.Error, Valid: run.Error != ""},
+		run.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save automation run: %w", err)
+	}
+
+	return nil
+}
+
+// GetAutomationRuns возвращает последние выполнения правил автоматизации;
+// пустой ruleID означает все правила
+func (r *Repository) GetAutomationRuns(ruleID string, limit int) ([]models.AutomationRun, error) {
+	query := `
+		SELECT id, rule_id, trigger, dry_run, success, actions, error, created_at
+		FROM automation_runs
+		WHERE $1 = '' OR rule_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, ruleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query automation runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.AutomationRun
+	for rows.Next() {
+		var run models.AutomationRun
+		var actionsBytes []byte
+		var runErr sql.NullString
+
+		if err := rows.Scan(
+			&run.ID,
+			&run.RuleID,
+			&run.Trigger,
+			&run.DryRun,
+			&run.Success,
+			&actionsBytes,
+			&runErr,
+			&run.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan automation run: %w", err)
+		}
+
+		if err := json.Unmarshal(actionsBytes, &run.Actions); err != nil {
+			return nil, fmt.Errorf("failed to scan actions: %w", err)
+		}
+		run.Error = runErr.String
+
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return runs, nil
+}
+
 // scanReadings сканирует результаты запроса в структуры моделей
 func (r *Repository) scanReadings(rows *sql.Rows) ([]models.SensorData, error) {
 	var readings []models.SensorData
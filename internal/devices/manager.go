@@ -0,0 +1,121 @@
+// Package devices управляет исполнительными устройствами: выполняет команды
+// и сохраняет историю команд и состояний в БД
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// ErrDeviceNotFound возвращается для неизвестного устройства
+var ErrDeviceNotFound = errors.New("device not found")
+
+// Источники команд
+const (
+	SourceAPI     = "api"
+	SourceStartup = "startup"
+)
+
+// Manager выполняет команды устройств и сохраняет события
+type Manager struct {
+	devices map[string]models.Actuator
+	order   []models.Actuator
+	repo    *database.Repository
+	logger  *log.Logger
+}
+
+// NewManager создает менеджер для списка устройств
+func NewManager(devices []models.Actuator, repo *database.Repository, logger *log.Logger) *Manager {
+	m := &Manager{
+		devices: make(map[string]models.Actuator, len(devices)),
+		order:   devices,
+		repo:    repo,
+		logger:  logger,
+	}
+	for _, d := range devices {
+		m.devices[d.ID()] = d
+	}
+	return m
+}
+
+// List возвращает все устройства
+func (m *Manager) List() []models.Actuator {
+	return m.order
+}
+
+// Get возвращает устройство по ID
+func (m *Manager) Get(id string) (models.Actuator, error) {
+	d, ok := m.devices[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, id)
+	}
+	return d, nil
+}
+
+// RecordStates сохраняет текущее состояние всех устройств
+// (например, исходное состояние при запуске)
+func (m *Manager) RecordStates(source string) {
+	for _, d := range m.order {
+		m.saveEvent(models.DeviceEvent{
+			DeviceID:   d.ID(),
+			DeviceType: d.Type(),
+			EventType:  models.DeviceEventState,
+			State:      d.State(),
+			Source:     source,
+		})
+	}
+}
+
+// Execute выполняет команду устройства, сохраняет команду и, если состояние
+// изменилось, новое состояние
+func (m *Manager) Execute(id string, cmd models.Command, source string) (models.ActuatorState, error) {
+	d, err := m.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := d.State()
+	after, err := d.Execute(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	m.saveEvent(models.DeviceEvent{
+		DeviceID:   d.ID(),
+		DeviceType: d.Type(),
+		EventType:  models.DeviceEventCommand,
+		Command:    &cmd,
+		Source:     source,
+		CreatedAt:  now,
+	})
+
+	if !reflect.DeepEqual(before, after) {
+		m.saveEvent(models.DeviceEvent{
+			DeviceID:   d.ID(),
+			DeviceType: d.Type(),
+			EventType:  models.DeviceEventState,
+			State:      after,
+			Source:     source,
+			CreatedAt:  now,
+		})
+		m.logger.Printf("Device %s state changed by %s: %v", d.ID(), source, after)
+	}
+
+	return after, nil
+}
+
+func (m *Manager) saveEvent(event models.DeviceEvent) {
+	if m.repo == nil {
+		return
+	}
+	if err := m.repo.SaveDeviceEvent(event); err != nil {
+		m.logger.Printf("Error saving %s event of device %s: %v", event.EventType, event.DeviceID, err)
+	}
+}
@@ -0,0 +1,113 @@
+package factory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/fan"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/hvac"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/light"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/plug"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/thermostat"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+type DeviceFactory struct {
+	devices map[string]models.Actuator
+}
+
+func New(cfg config.DevicesConfig) (*DeviceFactory, error) {
+	f := &DeviceFactory{
+		devices: make(map[string]models.Actuator),
+	}
+
+	// Светильники
+	for _, c := range cfg.Lights {
+		d, err := light.New(light.Config{
+			ID:         c.ID,
+			Dimmable:   c.Dimmable,
+			Brightness: c.Brightness,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("light device error: %w", err)
+		}
+		if err := f.add(d); err != nil {
+			return nil, err
+		}
+	}
+
+	// Термостаты
+	for _, c := range cfg.Thermostats {
+		d, err := thermostat.New(thermostat.Config{
+			ID:          c.ID,
+			Setpoint:    c.Setpoint,
+			MinSetpoint: c.MinSetpoint,
+			MaxSetpoint: c.MaxSetpoint,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("thermostat device error: %w", err)
+		}
+		if err := f.add(d); err != nil {
+			return nil, err
+		}
+	}
+
+	// Вентиляторы
+	for _, c := range cfg.Fans {
+		d, err := fan.New(fan.Config{ID: c.ID})
+		if err != nil {
+			return nil, fmt.Errorf("fan device error: %w", err)
+		}
+		if err := f.add(d); err != nil {
+			return nil, err
+		}
+	}
+
+	// Умные розетки
+	for _, c := range cfg.Plugs {
+		d, err := plug.New(plug.Config{ID: c.ID, LoadW: c.LoadW})
+		if err != nil {
+			return nil, fmt.Errorf("plug device error: %w", err)
+		}
+		if err := f.add(d); err != nil {
+			return nil, err
+		}
+	}
+
+	// Обогреватели и кондиционеры
+	for _, c := range cfg.HVAC {
+		d, err := hvac.New(hvac.Config{
+			ID:      c.ID,
+			Heating: c.Heating,
+			Cooling: c.Cooling,
+			PowerW:  c.PowerW,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("hvac device error: %w", err)
+		}
+		if err := f.add(d); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (f *DeviceFactory) add(d models.Actuator) error {
+	if _, exists := f.devices[d.ID()]; exists {
+		return fmt.Errorf("duplicate device ID %q", d.ID())
+	}
+	f.devices[d.ID()] = d
+	return nil
+}
+
+// GetAllDevices возвращает устройства, отсортированные по ID
+func (f *DeviceFactory) GetAllDevices() []models.Actuator {
+	devices := make([]models.Actuator, 0, len(f.devices))
+	for _, d := range f.devices {
+		devices = append(devices, d)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID() < devices[j].ID() })
+	return devices
+}
@@ -0,0 +1,16 @@
+package fan
+
+import (
+	"errors"
+)
+
+type Config struct {
+	ID string `yaml:"id"`
+}
+
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errors.New("device ID is required")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package fan
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Скорости вентилятора
+var speeds = []string{"off", "low", "medium", "high"}
+
+// Device представляет эмулируемый вентилятор вытяжки
+type Device struct {
+	mu        sync.Mutex
+	id        string
+	speed     int
+	lastSpeed int
+}
+
+// New создает выключенный вентилятор
+func New(cfg Config) (*Device, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Device{id: cfg.ID, lastSpeed: 1}, nil
+}
+
+func (d *Device) ID() string   { return d.id }
+func (d *Device) Type() string { return "fan" }
+
+// Capabilities возвращает поддерживаемые команды
+func (d *Device) Capabilities() []string {
+	return []string{models.CommandTurnOn, models.CommandTurnOff, models.CommandToggle, models.CommandSetSpeed}
+}
+
+// Execute выполняет команду и возвращает новое состояние
+func (d *Device) Execute(cmd models.Command) (models.ActuatorState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch cmd.Name {
+	case models.CommandTurnOn:
+		d.setSpeed(d.lastSpeed)
+	case models.CommandTurnOff:
+		d.setSpeed(0)
+	case models.CommandToggle:
+		if d.speed == 0 {
+			d.setSpeed(d.lastSpeed)
+		} else {
+			d.setSpeed(0)
+		}
+	case models.CommandSetSpeed:
+		speed, err := parseSpeed(cmd.Value)
+		if err != nil {
+			return nil, err
+		}
+		d.setSpeed(speed)
+	default:
+		return nil, actuators.Unsupported(cmd)
+	}
+
+	return d.state(), nil
+}
+
+// State возвращает текущее состояние
+func (d *Device) State() models.ActuatorState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state()
+}
+
+func (d *Device) setSpeed(speed int) {
+	d.speed = speed
+	if speed > 0 {
+		d.lastSpeed = speed
+	}
+}
+
+func (d *Device) state() models.ActuatorState {
+	return models.ActuatorState{
+		"on":    d.speed > 0,
+		"speed": speeds[d.speed],
+	}
+}
+
+// parseSpeed принимает название скорости (off, low, medium, high) или ее номер 0-3
+func parseSpeed(v interface{}) (int, error) {
+	if name, ok := v.(string); ok {
+		for i, speed := range speeds {
+			if strings.EqualFold(name, speed) {
+				return i, nil
+			}
+		}
+	}
+
+	value, err := actuators.Float(v)
+	if err != nil || value != float64(int(value)) || value < 0 || int(value) >= len(speeds) {
+		return 0, fmt.Errorf("%w: speed must be one of %s or 0-%d",
+			models.ErrInvalidCommandValue, strings.Join(speeds, ", "), len(speeds)-1)
+	}
+	return int(value), nil
+}
@@ -0,0 +1,25 @@
+package hvac
+
+import (
+	"errors"
+)
+
+type Config struct {
+	ID      string  `yaml:"id"`
+	Heating bool    `yaml:"heating"`
+	Cooling bool    `yaml:"cooling"`
+	PowerW  float64 `yaml:"power_w"`
+}
+
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errors.New("device ID is required")
+	}
+	if !c.Heating && !c.Cooling {
+		return errors.New("device must support heating or cooling")
+	}
+	if c.PowerW < 0 {
+		return errors.New("invalid power")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package hvac
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Режимы работы
+const (
+	ModeOff  = "off"
+	ModeHeat = "heat"
+	ModeCool = "cool"
+)
+
+// Device представляет эмулируемый обогреватель/кондиционер с регулируемой мощностью
+type Device struct {
+	mu      sync.Mutex
+	id      string
+	heating bool
+	cooling bool
+	powerW  float64
+	mode    string
+	power   float64 // Мощность в процентах от номинальной
+}
+
+// New создает выключенное устройство
+func New(cfg Config) (*Device, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Device{
+		id:      cfg.ID,
+		heating: cfg.Heating,
+		cooling: cfg.Cooling,
+		powerW:  cfg.PowerW,
+		mode:    ModeOff,
+		power:   100,
+	}, nil
+}
+
+func (d *Device) ID() string   { return d.id }
+func (d *Device) Type() string { return "hvac" }
+
+// Capabilities возвращает поддерживаемые команды
+func (d *Device) Capabilities() []string {
+	return []string{models.CommandTurnOff, models.CommandSetMode, models.CommandSetPower}
+}
+
+// Supports сообщает, поддерживает ли устройство режим
+func (d *Device) Supports(mode string) bool {
+	switch mode {
+	case ModeOff:
+		return true
+	case ModeHeat:
+		return d.heating
+	case ModeCool:
+		return d.cooling
+	}
+	return false
+}
+
+// Execute выполняет команду и возвращает новое состояние
+func (d *Device) Execute(cmd models.Command) (models.ActuatorState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch cmd.Name {
+	case models.CommandTurnOff:
+		d.mode = ModeOff
+	case models.CommandSetMode:
+		mode, _ := cmd.Value.(string)
+		if !d.Supports(mode) {
+			return nil, fmt.Errorf("%w: unsupported mode %v", models.ErrInvalidCommandValue, cmd.Value)
+		}
+		d.mode = mode
+	case models.CommandSetPower:
+		value, err := actuators.Float(cmd.Value)
+		if err != nil {
+			return nil, err
+		}
+		if value < 0 || value > 100 {
+			return nil, fmt.Errorf("%w: power must be between 0 and 100", models.ErrInvalidCommandValue)
+		}
+		d.power = value
+	default:
+		return nil, actuators.Unsupported(cmd)
+	}
+
+	return d.state(), nil
+}
+
+// State возвращает текущее состояние
+func (d *Device) State() models.ActuatorState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state()
+}
+
+func (d *Device) state() models.ActuatorState {
+	on := d.mode != ModeOff && d.power > 0
+	consumption := 0.0
+	if on {
+		consumption = d.powerW * d.power / 100
+	}
+	return models.ActuatorState{
+		"on":      on,
+		"mode":    d.mode,
+		"power":   d.power,
+		"power_w": consumption,
+	}
+}
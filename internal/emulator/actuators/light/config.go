@@ -0,0 +1,21 @@
+package light
+
+import (
+	"errors"
+)
+
+type Config struct {
+	ID         string `yaml:"id"`
+	Dimmable   bool   `yaml:"dimmable"`
+	Brightness int    `yaml:"brightness"`
+}
+
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errors.New("device ID is required")
+	}
+	if c.Brightness < 0 || c.Brightness > 100 {
+		return errors.New("invalid brightness")
+	}
+	return nil
+}
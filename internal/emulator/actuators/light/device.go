@@ -0,0 +1,101 @@
+package light
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Device представляет эмулируемый светильник
+type Device struct {
+	mu         sync.Mutex
+	id         string
+	dimmable   bool
+	on         bool
+	brightness int
+}
+
+// New создает светильник; изначально он выключен
+func New(cfg Config) (*Device, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	brightness := cfg.Brightness
+	if brightness == 0 {
+		brightness = 100
+	}
+
+	return &Device{
+		id:         cfg.ID,
+		dimmable:   cfg.Dimmable,
+		brightness: brightness,
+	}, nil
+}
+
+func (d *Device) ID() string   { return d.id }
+func (d *Device) Type() string { return "light" }
+
+// Capabilities возвращает поддерживаемые команды
+func (d *Device) Capabilities() []string {
+	caps := []string{models.CommandTurnOn, models.CommandTurnOff, models.CommandToggle}
+	if d.dimmable {
+		caps = append(caps, models.CommandSetBrightness)
+	}
+	return caps
+}
+
+// Execute выполняет команду и возвращает новое состояние
+func (d *Device) Execute(cmd models.Command) (models.ActuatorState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch cmd.Name {
+	case models.CommandTurnOn:
+		d.on = true
+	case models.CommandTurnOff:
+		d.on = false
+	case models.CommandToggle:
+		d.on = !d.on
+	case models.CommandSetBrightness:
+		if !d.dimmable {
+			return nil, actuators.Unsupported(cmd)
+		}
+		value, err := actuators.Float(cmd.Value)
+		if err != nil {
+			return nil, err
+		}
+		if value < 0 || value > 100 {
+			return nil, fmt.Errorf("%w: brightness must be between 0 and 100", models.ErrInvalidCommandValue)
+		}
+		// Нулевая яркость выключает свет, сохраняя предыдущую яркость
+		if value == 0 {
+			d.on = false
+		} else {
+			d.on = true
+			d.brightness = int(math.Round(value))
+		}
+	default:
+		return nil, actuators.Unsupported(cmd)
+	}
+
+	return d.state(), nil
+}
+
+// State возвращает текущее состояние
+func (d *Device) State() models.ActuatorState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state()
+}
+
+func (d *Device) state() models.ActuatorState {
+	state := models.ActuatorState{"on": d.on}
+	if d.dimmable {
+		state["brightness"] = d.brightness
+	}
+	return state
+}
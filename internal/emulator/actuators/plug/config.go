@@ -0,0 +1,20 @@
+package plug
+
+import (
+	"errors"
+)
+
+type Config struct {
+	ID    string  `yaml:"id"`
+	LoadW float64 `yaml:"load_w"`
+}
+
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errors.New("device ID is required")
+	}
+	if c.LoadW < 0 {
+		return errors.New("invalid load")
+	}
+	return nil
+}
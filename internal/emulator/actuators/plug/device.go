@@ -0,0 +1,70 @@
+package plug
+
+import (
+	"sync"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Device представляет эмулируемую умную розетку
+type Device struct {
+	mu    sync.Mutex
+	id    string
+	on    bool
+	loadW float64
+}
+
+// New создает выключенную розетку с подключенной нагрузкой loadW
+func New(cfg Config) (*Device, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Device{id: cfg.ID, loadW: cfg.LoadW}, nil
+}
+
+func (d *Device) ID() string   { return d.id }
+func (d *Device) Type() string { return "plug" }
+
+// Capabilities возвращает поддерживаемые команды
+func (d *Device) Capabilities() []string {
+	return []string{models.CommandTurnOn, models.CommandTurnOff, models.CommandToggle}
+}
+
+// Execute выполняет команду и возвращает новое состояние
+func (d *Device) Execute(cmd models.Command) (models.ActuatorState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch cmd.Name {
+	case models.CommandTurnOn:
+		d.on = true
+	case models.CommandTurnOff:
+		d.on = false
+	case models.CommandToggle:
+		d.on = !d.on
+	default:
+		return nil, actuators.Unsupported(cmd)
+	}
+
+	return d.state(), nil
+}
+
+// State возвращает текущее состояние
+func (d *Device) State() models.ActuatorState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state()
+}
+
+func (d *Device) state() models.ActuatorState {
+	power := 0.0
+	if d.on {
+		power = d.loadW
+	}
+	return models.ActuatorState{
+		"on":      d.on,
+		"power_w": power,
+	}
+}
@@ -0,0 +1,25 @@
+package thermostat
+
+import (
+	"errors"
+)
+
+type Config struct {
+	ID          string  `yaml:"id"`
+	Setpoint    float64 `yaml:"setpoint"`
+	MinSetpoint float64 `yaml:"min_setpoint"`
+	MaxSetpoint float64 `yaml:"max_setpoint"`
+}
+
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errors.New("device ID is required")
+	}
+	if c.MinSetpoint >= c.MaxSetpoint {
+		return errors.New("invalid setpoint range")
+	}
+	if c.Setpoint < c.MinSetpoint || c.Setpoint > c.MaxSetpoint {
+		return errors.New("setpoint is out of range")
+	}
+	return nil
+}
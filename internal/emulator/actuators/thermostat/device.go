@@ -0,0 +1,83 @@
+package thermostat
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Device представляет эмулируемый термостат
+type Device struct {
+	mu          sync.Mutex
+	id          string
+	on          bool
+	setpoint    float64
+	minSetpoint float64
+	maxSetpoint float64
+}
+
+// New создает термостат; изначально он включен с уставкой из конфигурации
+func New(cfg Config) (*Device, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Device{
+		id:          cfg.ID,
+		on:          true,
+		setpoint:    cfg.Setpoint,
+		minSetpoint: cfg.MinSetpoint,
+		maxSetpoint: cfg.MaxSetpoint,
+	}, nil
+}
+
+func (d *Device) ID() string   { return d.id }
+func (d *Device) Type() string { return "thermostat" }
+
+// Capabilities возвращает поддерживаемые команды
+func (d *Device) Capabilities() []string {
+	return []string{models.CommandTurnOn, models.CommandTurnOff, models.CommandSetSetpoint}
+}
+
+// Execute выполняет команду и возвращает новое состояние
+func (d *Device) Execute(cmd models.Command) (models.ActuatorState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch cmd.Name {
+	case models.CommandTurnOn:
+		d.on = true
+	case models.CommandTurnOff:
+		d.on = false
+	case models.CommandSetSetpoint:
+		value, err := actuators.Float(cmd.Value)
+		if err != nil {
+			return nil, err
+		}
+		if value < d.minSetpoint || value > d.maxSetpoint {
+			return nil, fmt.Errorf("%w: setpoint must be between %.1f and %.1f",
+				models.ErrInvalidCommandValue, d.minSetpoint, d.maxSetpoint)
+		}
+		d.setpoint = value
+	default:
+		return nil, actuators.Unsupported(cmd)
+	}
+
+	return d.state(), nil
+}
+
+// State возвращает текущее состояние
+func (d *Device) State() models.ActuatorState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state()
+}
+
+func (d *Device) state() models.ActuatorState {
+	return models.ActuatorState{
+		"on":       d.on,
+		"setpoint": d.setpoint,
+	}
+}
@@ -0,0 +1,41 @@
+package actuators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Float преобразует аргумент команды в число; JSON-числа приходят как float64.
+// NaN и бесконечности не принимаются: они проходят любые проверки диапазона
+func Float(v interface{}) (float64, error) {
+	var f float64
+	switch value := v.(type) {
+	case float64:
+		f = value
+	case int:
+		f = float64(value)
+	case int64:
+		f = float64(value)
+	case string:
+		var err error
+		f, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", models.ErrInvalidCommandValue, value)
+		}
+	default:
+		return 0, fmt.Errorf("%w: expected a number, got %v", models.ErrInvalidCommandValue, v)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: %v is not a finite number", models.ErrInvalidCommandValue, v)
+	}
+	return f, nil
+}
+
+// Unsupported возвращает ошибку для неподдерживаемой команды
+func Unsupported(cmd models.Command) error {
+	return fmt.Errorf("%w: %q", models.ErrUnsupportedCommand, cmd.Name)
+}
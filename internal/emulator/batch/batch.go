@@ -0,0 +1,150 @@
+// Package batch генерирует показания эмулируемых датчиков за интервал
+// модельного времени быстрее реального: часы эмулятора переводятся к моменту
+// очередного опроса, датчики опрашиваются в порядке времени, а показания
+// передаются в Sink (файл NDJSON или база данных)
+package batch
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Sink принимает сгенерированные показания
+type Sink interface {
+	Write(data models.SensorData) error
+}
+
+// Generator опрашивает датчики по модельным часам
+type Generator struct {
+	sensors []models.Sensor
+	clock   *sim.ManualClock
+	// Before вызывается перед каждым опросом с текущим модельным временем
+	// (например, чтобы продвинуть физическую модель комнат)
+	Before func(now time.Time)
+}
+
+// New создает генератор; датчики должны использовать clock
+func New(sensors []models.Sensor, clock *sim.ManualClock) *Generator {
+	return &Generator{sensors: sensors, clock: clock}
+}
+
+// Result - итог генерации
+type Result struct {
+	Written int // Записано показаний
+	Failed  int // Неудачных опросов (датчик не готов, имитация неисправностей)
+}
+
+// Run опрашивает датчики с их интервалами от from до to. Неудачные опросы
+// пропускаются, как это делает сборщик; ошибка возвращается только при записи
+func (g *Generator) Run(from, to time.Time, sink Sink) (Result, error) {
+	queue := make(pollQueue, 0, len(g.sensors))
+	for _, s := range g.sensors {
+		if s.Interval() <= 0 {
+			continue
+		}
+		queue = append(queue, &poll{sensor: s, at: from.Add(s.Interval())})
+	}
+	heap.Init(&queue)
+
+	var result Result
+	for queue.Len() > 0 && !queue[0].at.After(to) {
+		next := queue[0]
+		g.clock.Set(next.at)
+		if g.Before != nil {
+			g.Before(next.at)
+		}
+
+		reading, err := next.sensor.Read()
+		next.at = next.at.Add(next.sensor.Interval())
+		heap.Fix(&queue, 0)
+
+		if err != nil {
+			result.Failed++
+			continue
+		}
+
+		data := models.SensorData{
+			SensorID:   next.sensor.ID(),
+			SensorType: next.sensor.Type(),
+			Timestamp:  reading.Timestamp,
+			Value:      models.SensorValue{Data: reading.Value},
+			CreatedAt:  reading.Timestamp,
+		}
+		if err := sink.Write(data); err != nil {
+			return result, err
+		}
+		result.Written++
+	}
+
+	return result, nil
+}
+
+// poll - очередной опрос датчика
+type poll struct {
+	sensor models.Sensor
+	at     time.Time
+}
+
+// pollQueue - очередь опросов по времени; при равном времени порядок
+// определяется ID датчика, чтобы результат был воспроизводимым
+type pollQueue []*poll
+
+func (q pollQueue) Len() int { return len(q) }
+func (q pollQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].sensor.ID() < q[j].sensor.ID()
+	}
+	return q[i].at.Before(q[j].at)
+}
+func (q pollQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pollQueue) Push(x interface{}) { *q = append(*q, x.(*poll)) }
+func (q *pollQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// NDJSONSink записывает показания построчно в формате JSON
+type NDJSONSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewNDJSONSink создает запись в w; после генерации нужно вызвать Flush
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	buf := bufio.NewWriter(w)
+	return &NDJSONSink{w: buf, enc: json.NewEncoder(buf)}
+}
+
+// Write реализует Sink
+func (s *NDJSONSink) Write(data models.SensorData) error {
+	return s.enc.Encode(data)
+}
+
+// Flush дописывает буферизованные данные
+func (s *NDJSONSink) Flush() error {
+	return s.w.Flush()
+}
+
+// RepositorySink сохраняет показания в базу данных
+type RepositorySink struct {
+	repo *database.Repository
+}
+
+// NewRepositorySink создает запись в базу данных
+func NewRepositorySink(repo *database.Repository) *RepositorySink {
+	return &RepositorySink{repo: repo}
+}
+
+// Write реализует Sink
+func (s *RepositorySink) Write(data models.SensorData) error {
+	return s.repo.SaveReading(data)
+}
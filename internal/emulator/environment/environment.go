@@ -0,0 +1,349 @@
+// Package environment моделирует физику комнат для эмулятора: тепловую
+// инерцию, теплопотери наружу, мощность обогревателей, воздухообмен и
+// выделение CO2 людьми. Датчики, подключенные к модели, показывают ее
+// состояние и реагируют на команды исполнительных устройств
+package environment
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Значения по умолчанию
+const (
+	defaultStep               = 10 * time.Second
+	defaultOutdoorCO2         = 420.0
+	defaultVolumeM3           = 40.0
+	defaultHeatCapacityKJPerK = 2000.0
+	defaultHeatLossWPerK      = 50.0
+	defaultAirChangesPerHour  = 0.5
+	defaultInitialTemperature = 21.0
+)
+
+// Физические константы
+const (
+	// maxSubstep - максимальный шаг интегрирования, обеспечивающий устойчивость
+	maxSubstep = 10 * time.Second
+	// occupantHeatW - тепловыделение одного человека, Вт
+	occupantHeatW = 100.0
+	// occupantCO2M3PerSecond - выделение CO2 одним человеком в покое, м³/с (≈18 л/ч)
+	occupantCO2M3PerSecond = 0.005 / 1000
+	// airHeatCapacity - объемная теплоемкость воздуха, Дж/(м³·К)
+	airHeatCapacity = 1.2 * 1005
+)
+
+// defaultVentilation - производительность вентилятора по скоростям, м³/ч
+var defaultVentilation = map[string]float64{
+	"off":    0,
+	"low":    80,
+	"medium": 160,
+	"high":   250,
+}
+
+// RoomState - состояние комнаты в модели
+type RoomState struct {
+	Room        string  `json:"room"`
+	Temperature float64 `json:"temperature"` // °C
+	CO2         float64 `json:"co2"`         // ppm
+	NH3         float64 `json:"nh3"`         // ppm
+	Occupants   int     `json:"occupants"`
+	HeatW       float64 `json:"heatW"`      // Мощность нагрева (охлаждения, если отрицательная) от устройств, Вт
+	AirflowM3H  float64 `json:"airflowM3h"` // Суммарный воздухообмен с улицей, м³/ч
+}
+
+// OccupancySource предоставляет количество людей в комнатах (модель присутствия)
+type OccupancySource interface {
+	OccupantsAt(room string, now time.Time) (int, bool)
+}
+
+// room - комната модели
+type room struct {
+	cfg   config.RoomPhysicsConfig
+	state RoomState
+}
+
+// Environment - физическая модель комнат
+type Environment struct {
+	outdoor     config.OutdoorConfig
+	ventilation map[string]float64
+	step        time.Duration
+	states      DeviceStates
+	occupancy   OccupancySource
+	clock       sim.Clock
+	logger      *log.Logger
+
+	mu    sync.RWMutex
+	rooms map[string]*room
+	last  time.Time
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// New создает модель окружения; states может быть nil, тогда устройства не
+// учитываются, clock может быть nil (реальное время)
+func New(cfg config.EnvironmentConfig, states DeviceStates, clock sim.Clock, logger *log.Logger) (*Environment, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid environment config: %w", err)
+	}
+
+	e := &Environment{
+		outdoor:     cfg.Outdoor,
+		ventilation: defaultVentilation,
+		step:        cfg.Step,
+		states:      states,
+		clock:       sim.Or(clock),
+		logger:      logger,
+		rooms:       make(map[string]*room, len(cfg.Rooms)),
+		stopChan:    make(chan struct{}),
+	}
+	if e.step == 0 {
+		e.step = defaultStep
+	}
+	if e.outdoor.CO2 == 0 {
+		e.outdoor.CO2 = defaultOutdoorCO2
+	}
+	if len(cfg.VentilationM3H) > 0 {
+		e.ventilation = cfg.VentilationM3H
+	}
+
+	for _, rc := range cfg.Rooms {
+		if rc.VolumeM3 == 0 {
+			rc.VolumeM3 = defaultVolumeM3
+		}
+		if rc.HeatCapacityKJPerK == 0 {
+			rc.HeatCapacityKJPerK = defaultHeatCapacityKJPerK
+		}
+		if rc.HeatLossWPerK == 0 {
+			rc.HeatLossWPerK = defaultHeatLossWPerK
+		}
+		if rc.AirChangesPerHour == 0 {
+			rc.AirChangesPerHour = defaultAirChangesPerHour
+		}
+		if rc.InitialTemperature == 0 {
+			rc.InitialTemperature = defaultInitialTemperature
+		}
+		if rc.InitialCO2 == 0 {
+			rc.InitialCO2 = e.outdoor.CO2
+		}
+
+		e.rooms[rc.Room] = &room{
+			cfg: rc,
+			state: RoomState{
+				Room:        rc.Room,
+				Temperature: rc.InitialTemperature,
+				CO2:         rc.InitialCO2,
+				Occupants:   rc.Occupants,
+			},
+		}
+	}
+
+	return e, nil
+}
+
+// UseOccupancy подключает модель присутствия: количество людей в комнатах,
+// известных source, берется из нее вместо конфигурации. Вызывается до Start
+func (e *Environment) UseOccupancy(source OccupancySource) {
+	e.occupancy = source
+}
+
+// Start запускает моделирование по часам модели. При ускоренном времени
+// шаг модели сохраняется, а реальный период тиков сокращается
+func (e *Environment) Start() {
+	go func() {
+		period := e.step
+		if scaled, ok := e.clock.(*sim.ScaledClock); ok {
+			period = scaled.RealDuration(e.step)
+		}
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+
+		e.logger.Printf("Starting environment model for %d rooms with step %s", len(e.rooms), e.step)
+
+		e.Reset(e.clock.Now())
+
+		for {
+			select {
+			case <-ticker.C:
+				e.AdvanceTo(e.clock.Now())
+			case <-e.stopChan:
+				e.logger.Println("Stopping environment model")
+				return
+			}
+		}
+	}()
+}
+
+// Stop останавливает моделирование
+func (e *Environment) Stop() {
+	e.stopOnce.Do(func() { close(e.stopChan) })
+}
+
+// Reset задает момент, от которого AdvanceTo продвигает модель
+func (e *Environment) Reset(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.last = now
+}
+
+// Now возвращает текущее время по часам модели
+func (e *Environment) Now() time.Time {
+	return e.clock.Now()
+}
+
+// AdvanceTo продвигает модель до момента now
+func (e *Environment) AdvanceTo(now time.Time) {
+	e.mu.Lock()
+	last := e.last
+	e.last = now
+	e.mu.Unlock()
+
+	if last.IsZero() || !now.After(last) {
+		return
+	}
+
+	if e.occupancy != nil {
+		for _, name := range e.roomNames() {
+			if occupants, ok := e.occupancy.OccupantsAt(name, now); ok {
+				e.SetOccupants(name, occupants)
+			}
+		}
+	}
+
+	e.Advance(now.Sub(last))
+}
+
+// Advance продвигает модель на dt с учетом текущих состояний устройств
+func (e *Environment) Advance(dt time.Duration) {
+	var states map[string]models.ActuatorState
+	if e.states != nil {
+		var err error
+		if states, err = e.states.DeviceStates(); err != nil {
+			e.logger.Printf("Error getting device states for environment: %v", err)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range e.rooms {
+		r.state.HeatW, r.state.AirflowM3H = e.deviceEffects(r, states)
+		for remaining := dt; remaining > 0; remaining -= maxSubstep {
+			substep := remaining
+			if substep > maxSubstep {
+				substep = maxSubstep
+			}
+			e.integrate(r, substep.Seconds())
+		}
+	}
+}
+
+// deviceEffects вычисляет тепловую мощность устройств комнаты и воздухообмен
+func (e *Environment) deviceEffects(r *room, states map[string]models.ActuatorState) (heatW, airflowM3H float64) {
+	airflowM3H = r.cfg.AirChangesPerHour * r.cfg.VolumeM3
+
+	for _, id := range r.cfg.Devices {
+		state, ok := states[id]
+		if !ok {
+			continue
+		}
+
+		power, _ := state["power_w"].(float64)
+		if mode, ok := state["mode"].(string); ok {
+			// Обогреватель/кондиционер
+			switch mode {
+			case "heat":
+				heatW += power
+			case "cool":
+				heatW -= power
+			}
+		} else if on, _ := state["on"].(bool); on {
+			// Прочие электроприборы (розетки) отдают потребляемую мощность в виде тепла
+			heatW += power
+		}
+
+		if speed, ok := state["speed"].(string); ok {
+			airflowM3H += e.ventilation[speed]
+		}
+	}
+
+	return heatW, airflowM3H
+}
+
+// integrate выполняет один шаг явного метода Эйлера длительностью dt секунд
+func (e *Environment) integrate(r *room, dt float64) {
+	s := &r.state
+	volume := r.cfg.VolumeM3
+	airflow := s.AirflowM3H / 3600 // м³/с
+
+	// Тепловой баланс: отопление, устройства, люди, теплопотери и нагрев приточного воздуха
+	gain := r.cfg.BaseHeatW + s.HeatW + float64(s.Occupants)*occupantHeatW
+	loss := r.cfg.HeatLossWPerK * (s.Temperature - e.outdoor.Temperature)
+	ventilation := airflow * airHeatCapacity * (s.Temperature - e.outdoor.Temperature)
+	s.Temperature += (gain - loss - ventilation) * dt / (r.cfg.HeatCapacityKJPerK * 1000)
+
+	// Баланс газов: выделение в комнате и разбавление наружным воздухом
+	exchange := airflow / volume
+	generation := float64(s.Occupants) * occupantCO2M3PerSecond * 1e6 / volume
+	s.CO2 += (generation + exchange*(e.outdoor.CO2-s.CO2)) * dt
+	s.NH3 += (r.cfg.NH3PPMPerHour/3600 - exchange*s.NH3) * dt
+}
+
+func (e *Environment) roomNames() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	names := make([]string, 0, len(e.rooms))
+	for name := range e.rooms {
+		names = append(names, name)
+	}
+	return names
+}
+
+// HasRoom сообщает, моделируется ли комната
+func (e *Environment) HasRoom(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.rooms[name]
+	return ok
+}
+
+// Room возвращает состояние комнаты
+func (e *Environment) Room(name string) (RoomState, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	r, ok := e.rooms[name]
+	if !ok {
+		return RoomState{}, false
+	}
+	return r.state, true
+}
+
+// Rooms возвращает состояния всех комнат, отсортированные по названию
+func (e *Environment) Rooms() []RoomState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	states := make([]RoomState, 0, len(e.rooms))
+	for _, r := range e.rooms {
+		states = append(states, r.state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Room < states[j].Room })
+	return states
+}
+
+// SetOccupants задает количество людей в комнате
+func (e *Environment) SetOccupants(name string, occupants int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if r, ok := e.rooms[name]; ok && occupants >= 0 {
+		r.state.Occupants = occupants
+	}
+}
@@ -0,0 +1,99 @@
+package environment
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Шум измерений датчиков, подключенных к модели
+const (
+	temperatureNoise = 0.05 // °C
+	co2Noise         = 5.0  // ppm
+	nh3Noise         = 0.3  // ppm
+)
+
+// TemperatureSensor показывает температуру комнаты из модели окружения
+type TemperatureSensor struct {
+	mu       sync.Mutex
+	id       string
+	room     string
+	interval time.Duration
+	env      *Environment
+	rng      *rand.Rand
+}
+
+// NewTemperatureSensor создает датчик температуры комнаты; комната
+// определяется по ID датчика, seed - зерно шума измерений (0 - случайное)
+func NewTemperatureSensor(id string, interval time.Duration, seed int64, env *Environment) (*TemperatureSensor, error) {
+	room := models.SensorLocation(id)
+	if !env.HasRoom(room) {
+		return nil, fmt.Errorf("room %q is not modelled", room)
+	}
+	return &TemperatureSensor{id: id, room: room, interval: interval, env: env, rng: sim.NewRand(seed, id)}, nil
+}
+
+func (s *TemperatureSensor) ID() string              { return s.id }
+func (s *TemperatureSensor) Type() string            { return "temperature" }
+func (s *TemperatureSensor) Interval() time.Duration { return s.interval }
+
+// Read возвращает текущую температуру комнаты с шумом измерения
+func (s *TemperatureSensor) Read() (models.Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, _ := s.env.Room(s.room)
+	value := state.Temperature + s.rng.NormFloat64()*temperatureNoise
+
+	return models.Reading{
+		Value:     math.Round(value*10) / 10,
+		Timestamp: s.env.Now().UTC(),
+	}, nil
+}
+
+// AirQualitySensor показывает уровни CO2 и NH3 комнаты из модели окружения
+type AirQualitySensor struct {
+	mu       sync.Mutex
+	id       string
+	room     string
+	interval time.Duration
+	env      *Environment
+	rng      *rand.Rand
+}
+
+// NewAirQualitySensor создает датчик качества воздуха комнаты; комната
+// определяется по ID датчика, seed - зерно шума измерений (0 - случайное)
+func NewAirQualitySensor(id string, interval time.Duration, seed int64, env *Environment) (*AirQualitySensor, error) {
+	room := models.SensorLocation(id)
+	if !env.HasRoom(room) {
+		return nil, fmt.Errorf("room %q is not modelled", room)
+	}
+	return &AirQualitySensor{id: id, room: room, interval: interval, env: env, rng: sim.NewRand(seed, id)}, nil
+}
+
+func (s *AirQualitySensor) ID() string              { return s.id }
+func (s *AirQualitySensor) Type() string            { return "air_quality" }
+func (s *AirQualitySensor) Interval() time.Duration { return s.interval }
+
+// Read возвращает текущие уровни газов в комнате с шумом измерения
+func (s *AirQualitySensor) Read() (models.Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, _ := s.env.Room(s.room)
+	co2 := math.Max(0, state.CO2+s.rng.NormFloat64()*co2Noise)
+	nh3 := math.Max(0, state.NH3+s.rng.NormFloat64()*nh3Noise)
+
+	return models.Reading{
+		Value: map[string]interface{}{
+			"co2": math.Round(co2),
+			"nh3": math.Round(nh3),
+		},
+		Timestamp: s.env.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package environment
+
+import (
+	"github.com/4Amangel1/smart-house-automate/internal/database"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// DeviceStates предоставляет текущие состояния исполнительных устройств
+type DeviceStates interface {
+	DeviceStates() (map[string]models.ActuatorState, error)
+}
+
+// ActuatorStates читает состояния устройств, созданных в том же процессе
+type ActuatorStates []models.Actuator
+
+// DeviceStates реализует DeviceStates
+func (a ActuatorStates) DeviceStates() (map[string]models.ActuatorState, error) {
+	states := make(map[string]models.ActuatorState, len(a))
+	for _, d := range a {
+		states[d.ID()] = d.State()
+	}
+	return states, nil
+}
+
+// OverlayStates объединяет источники состояний устройств: состояние из
+// следующего источника заменяет состояние того же устройства из предыдущего.
+// При ошибке источника возвращаются состояния остальных вместе с ошибкой
+type OverlayStates []DeviceStates
+
+// DeviceStates реализует DeviceStates
+func (o OverlayStates) DeviceStates() (map[string]models.ActuatorState, error) {
+	states := make(map[string]models.ActuatorState)
+	var firstErr error
+	for _, source := range o {
+		layer, err := source.DeviceStates()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for id, state := range layer {
+			states[id] = state
+		}
+	}
+	return states, firstErr
+}
+
+// RepositoryStates читает последние состояния устройств из БД; так модель
+// окружения видит команды, выполненные другим сервисом (API, автоматизацией)
+type RepositoryStates struct {
+	repo *database.Repository
+}
+
+// NewRepositoryStates создает источник состояний устройств из БД
+func NewRepositoryStates(repo *database.Repository) *RepositoryStates {
+	return &RepositoryStates{repo: repo}
+}
+
+// DeviceStates реализует DeviceStates
+func (r *RepositoryStates) DeviceStates() (map[string]models.ActuatorState, error) {
+	return r.repo.GetLatestDeviceStates()
+}
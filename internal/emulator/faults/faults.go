@@ -0,0 +1,166 @@
+// Package faults имитирует неисправности эмулируемых датчиков: отказ
+// готовности, ошибки чтения, залипание, выбросы, дрейф, медленные чтения и
+// полную недоступность. Неисправности добавляются оберткой над models.Sensor,
+// поэтому подходят для датчиков любого типа
+package faults
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Ошибки неисправного датчика
+var (
+	ErrReadFailed = errors.New("sensor read failed")
+	ErrTimeout    = errors.New("sensor read timed out")
+	ErrOffline    = errors.New("sensor is offline")
+)
+
+// Sensor - датчик с имитацией неисправностей
+type Sensor struct {
+	models.Sensor
+
+	cfg   config.FaultsConfig
+	clock sim.Clock
+
+	mu          sync.Mutex
+	rng         *rand.Rand
+	started     time.Time
+	outageUntil time.Time
+	stuckUntil  time.Time
+	stuckValue  interface{}
+}
+
+// Wrap добавляет неисправности к датчику; если ни одна неисправность не
+// задана, датчик возвращается без изменений. clock может быть nil (реальное
+// время), seed - зерно генератора (0 - случайное)
+func Wrap(sensor models.Sensor, cfg config.FaultsConfig, clock sim.Clock, seed int64) (models.Sensor, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return sensor, nil
+	}
+
+	return &Sensor{
+		Sensor: sensor,
+		cfg:    cfg,
+		clock:  sim.Or(clock),
+		// Отдельный ключ, чтобы неисправности не меняли последовательность показаний датчика
+		rng: sim.NewRand(seed, sensor.ID()+"/faults"),
+	}, nil
+}
+
+// Read читает показания исходного датчика с учетом неисправностей
+func (s *Sensor) Read() (models.Reading, error) {
+	s.mu.Lock()
+	now := s.clock.Now()
+	if s.started.IsZero() {
+		s.started = now
+	}
+
+	// Недоступность длится окно целиком
+	if now.Before(s.outageUntil) {
+		s.mu.Unlock()
+		return models.Reading{}, ErrOffline
+	}
+	if s.roll(s.cfg.Outage.Probability) {
+		s.outageUntil = now.Add(s.cfg.Outage.Duration)
+		s.mu.Unlock()
+		return models.Reading{}, ErrOffline
+	}
+
+	slow := s.roll(s.cfg.Slow.Probability)
+	notReady := s.roll(s.cfg.NotReady)
+	failed := s.roll(s.cfg.Error)
+	s.mu.Unlock()
+
+	if slow {
+		delay := s.cfg.Slow.Delay
+		if s.cfg.Slow.Timeout > 0 && delay >= s.cfg.Slow.Timeout {
+			sim.Sleep(s.clock, s.cfg.Slow.Timeout)
+			return models.Reading{}, ErrTimeout
+		}
+		sim.Sleep(s.clock, delay)
+	}
+	if notReady {
+		return models.Reading{}, models.ErrNotReady
+	}
+	if failed {
+		return models.Reading{}, ErrReadFailed
+	}
+
+	reading, err := s.Sensor.Read()
+	if err != nil {
+		return reading, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value := reading.Value
+	if s.cfg.DriftPerHour != 0 {
+		value = shift(value, s.cfg.DriftPerHour*now.Sub(s.started).Hours())
+	}
+
+	switch {
+	case now.Before(s.stuckUntil):
+		value = s.stuckValue
+	case s.roll(s.cfg.Stuck.Probability):
+		s.stuckUntil = now.Add(s.cfg.Stuck.Duration)
+		s.stuckValue = value
+	case s.roll(s.cfg.Spike.Probability):
+		sign := 1.0
+		if s.rng.Intn(2) == 0 {
+			sign = -1
+		}
+		value = spike(value, 1+sign*s.cfg.Spike.Magnitude)
+	}
+
+	reading.Value = value
+	return reading, nil
+}
+
+// roll возвращает true с вероятностью p; вызывается под мьютексом
+func (s *Sensor) roll(p float64) bool {
+	return p > 0 && s.rng.Float64() < p
+}
+
+// shift прибавляет delta к числовым значениям (в том числе к полям составного значения)
+func shift(value interface{}, delta float64) interface{} {
+	return mapNumbers(value, func(v float64) float64 { return v + delta })
+}
+
+// spike умножает числовые значения на factor и инвертирует логические
+func spike(value interface{}, factor float64) interface{} {
+	if b, ok := value.(bool); ok {
+		return !b
+	}
+	return mapNumbers(value, func(v float64) float64 { return v * factor })
+}
+
+func mapNumbers(value interface{}, fn func(float64) float64) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return round(fn(v))
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			result[key] = mapNumbers(field, fn)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,72 @@
+package occupancy
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// MotionSensor - датчик движения, срабатывающий от жильцов модели присутствия
+type MotionSensor struct {
+	mu              sync.Mutex
+	id              string
+	room            string
+	interval        time.Duration
+	latch           time.Duration
+	probability     float64
+	idleProbability float64
+	lastDetected    time.Time
+	model           *Simulation
+	rng             *rand.Rand
+}
+
+// NewMotionSensor создает датчик движения комнаты; комната определяется по ID
+// датчика. probability - вероятность срабатывания за опрос от одного
+// бодрствующего человека, idleProbability - вероятность ложного срабатывания.
+// Датчик использует часы модели; seed - зерно генератора, 0 - случайное
+func NewMotionSensor(id string, interval, latch time.Duration, probability, idleProbability float64, seed int64, model *Simulation) (*MotionSensor, error) {
+	room := models.SensorLocation(id)
+	if !model.HasRoom(room) {
+		return nil, fmt.Errorf("room %q is not in the house graph", room)
+	}
+
+	return &MotionSensor{
+		id:              id,
+		room:            room,
+		interval:        interval,
+		latch:           latch,
+		probability:     probability,
+		idleProbability: idleProbability,
+		model:           model,
+		rng:             sim.NewRand(seed, id),
+	}, nil
+}
+
+func (s *MotionSensor) ID() string              { return s.id }
+func (s *MotionSensor) Type() string            { return "motion" }
+func (s *MotionSensor) Interval() time.Duration { return s.interval }
+
+// Read возвращает true, если движение было обнаружено в течение latch
+func (s *MotionSensor) Read() (models.Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.model.clock.Now().UTC()
+	intensity := s.model.IntensityAt(s.room, now)
+
+	// Каждый человек независимо вызывает срабатывание; к этому добавляются ложные срабатывания
+	miss := math.Pow(1-s.probability, intensity) * (1 - s.idleProbability)
+	if s.rng.Float64() >= miss {
+		s.lastDetected = now
+	}
+
+	return models.Reading{
+		Value:     !s.lastDetected.IsZero() && now.Sub(s.lastDetected) <= s.latch,
+		Timestamp: now,
+	}, nil
+}
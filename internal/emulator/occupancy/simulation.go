@@ -0,0 +1,320 @@
+// Package occupancy моделирует присутствие жильцов: каждый жилец следует
+// распорядку (будни/выходные), перемещается между комнатами по графу дома и
+// иногда отходит в соседние комнаты. Датчики движения срабатывают в зависимости
+// от того, кто и в каком состоянии находится в комнате
+package occupancy
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+)
+
+// Away - "комната" жильца, который ушел из дома
+const Away = "away"
+
+// Активность жильца
+const (
+	ActivityActive = "active"
+	ActivitySleep  = "sleep"
+)
+
+// Значения по умолчанию
+const (
+	defaultTransition = 30 * time.Second
+	// sleepIntensity - относительная подвижность спящего человека
+	sleepIntensity = 0.1
+	// maxCatchUp - максимальный интервал, который моделируется пошагово;
+	// при большем разрыве жильцы сразу оказываются там, где должны быть по распорядку
+	maxCatchUp = 6 * time.Hour
+)
+
+// entry - разобранный пункт распорядка
+type entry struct {
+	offset   time.Duration
+	room     string
+	activity string
+}
+
+// person - жилец и его текущее положение
+type person struct {
+	name     string
+	wander   float64
+	weekday  []entry
+	weekend  []entry
+	room     string
+	activity string
+}
+
+// Position - положение жильца
+type Position struct {
+	Name     string `json:"name"`
+	Room     string `json:"room"`
+	Activity string `json:"activity"`
+}
+
+// Simulation моделирует перемещение жильцов по дому
+type Simulation struct {
+	graph      map[string][]string
+	entrance   string
+	transition time.Duration
+	location   *time.Location
+	clock      sim.Clock
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	people []*person
+	last   time.Time
+}
+
+// New создает модель присутствия по конфигурации. clock может быть nil
+// (реальное время); при ненулевом seed перемещения жильцов воспроизводимы
+func New(cfg config.OccupancyConfig, clock sim.Clock, seed int64) (*Simulation, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid occupancy config: %w", err)
+	}
+
+	s := &Simulation{
+		graph:      make(map[string][]string),
+		entrance:   cfg.Entrance,
+		transition: cfg.Transition,
+		location:   time.UTC,
+		clock:      sim.Or(clock),
+		rng:        sim.NewRand(seed, "occupancy"),
+	}
+	if s.transition == 0 {
+		s.transition = defaultTransition
+	}
+	if cfg.Timezone != "" {
+		s.location, _ = time.LoadLocation(cfg.Timezone)
+	}
+
+	// Граф дома неориентированный
+	for room, neighbours := range cfg.House {
+		for _, n := range neighbours {
+			s.graph[room] = appendUnique(s.graph[room], n)
+			s.graph[n] = appendUnique(s.graph[n], room)
+		}
+	}
+	for room := range s.graph {
+		sort.Strings(s.graph[room])
+	}
+
+	for _, pc := range cfg.People {
+		p := &person{
+			name:    pc.Name,
+			wander:  pc.WanderProbability,
+			weekday: parseEntries(pc.Weekday),
+			weekend: parseEntries(pc.Weekend),
+			room:    Away,
+		}
+		if len(p.weekend) == 0 {
+			p.weekend = p.weekday
+		}
+		s.people = append(s.people, p)
+	}
+
+	return s, nil
+}
+
+func parseEntries(cfgs []config.ScheduleEntry) []entry {
+	entries := make([]entry, 0, len(cfgs))
+	for _, c := range cfgs {
+		offset, _ := config.ParseClock(c.At)
+		activity := c.Activity
+		if activity == "" {
+			activity = ActivityActive
+		}
+		entries = append(entries, entry{
+			offset:   offset,
+			room:     c.Room,
+			activity: activity,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+	return entries
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, item := range list {
+		if item == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+// HasRoom сообщает, есть ли комната в графе дома
+func (s *Simulation) HasRoom(room string) bool {
+	_, ok := s.graph[room]
+	return ok
+}
+
+// AdvanceTo продвигает модель до момента now шагами длительностью transition
+func (s *Simulation) AdvanceTo(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advanceTo(now)
+}
+
+func (s *Simulation) advanceTo(now time.Time) {
+	if s.last.IsZero() || now.Sub(s.last) > maxCatchUp {
+		for _, p := range s.people {
+			p.room, p.activity = s.scheduled(p, now)
+		}
+		s.last = now
+		return
+	}
+
+	for t := s.last.Add(s.transition); !t.After(now); t = t.Add(s.transition) {
+		for _, p := range s.people {
+			s.step(p, t)
+		}
+		s.last = t
+	}
+}
+
+// scheduled возвращает комнату и активность жильца по распорядку на момент t
+func (s *Simulation) scheduled(p *person, t time.Time) (string, string) {
+	local := t.In(s.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+	offset := local.Sub(midnight)
+
+	entries := p.scheduleFor(local.Weekday())
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].offset <= offset {
+			return entries[i].room, entries[i].activity
+		}
+	}
+
+	// До первого пункта действует последний пункт предыдущего дня
+	previous := p.scheduleFor(midnight.AddDate(0, 0, -1).Weekday())
+	last := previous[len(previous)-1]
+	return last.room, last.activity
+}
+
+func (p *person) scheduleFor(day time.Weekday) []entry {
+	if day == time.Saturday || day == time.Sunday {
+		return p.weekend
+	}
+	return p.weekday
+}
+
+// step перемещает жильца на одну комнату к цели или, если он на месте,
+// с заданной вероятностью отводит в соседнюю комнату
+func (s *Simulation) step(p *person, t time.Time) {
+	target, activity := s.scheduled(p, t)
+	p.activity = activity
+
+	switch {
+	case p.room == target:
+		if target == Away || activity == ActivitySleep || s.rng.Float64() >= p.wander {
+			return
+		}
+		if neighbours := s.graph[p.room]; len(neighbours) > 0 {
+			p.room = neighbours[s.rng.Intn(len(neighbours))]
+		}
+
+	case p.room == Away:
+		p.room = s.entrance
+
+	case target == Away:
+		if p.room == s.entrance {
+			p.room = Away
+			return
+		}
+		p.room = s.nextHop(p.room, s.entrance)
+
+	default:
+		p.room = s.nextHop(p.room, target)
+	}
+}
+
+// nextHop возвращает следующую комнату кратчайшего пути от from к to (поиск в ширину)
+func (s *Simulation) nextHop(from, to string) string {
+	if from == to {
+		return to
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		for _, n := range s.graph[room] {
+			if _, seen := previous[n]; seen {
+				continue
+			}
+			previous[n] = room
+			if n == to {
+				// Восстанавливаем путь до первого шага
+				hop := n
+				for previous[hop] != from {
+					hop = previous[hop]
+				}
+				return hop
+			}
+			queue = append(queue, n)
+		}
+	}
+
+	// Комната недостижима: переходим сразу
+	return to
+}
+
+// OccupantsAt возвращает количество людей в комнате на момент now
+func (s *Simulation) OccupantsAt(room string, now time.Time) (int, bool) {
+	if !s.HasRoom(room) {
+		return 0, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advanceTo(now)
+
+	count := 0
+	for _, p := range s.people {
+		if p.room == room {
+			count++
+		}
+	}
+	return count, true
+}
+
+// IntensityAt возвращает подвижность людей в комнате на момент now:
+// бодрствующий человек дает 1, спящий - 0.1
+func (s *Simulation) IntensityAt(room string, now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advanceTo(now)
+
+	intensity := 0.0
+	for _, p := range s.people {
+		if p.room != room {
+			continue
+		}
+		if p.activity == ActivitySleep {
+			intensity += sleepIntensity
+		} else {
+			intensity++
+		}
+	}
+	return intensity
+}
+
+// Positions возвращает текущее положение жильцов
+func (s *Simulation) Positions() []Position {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	positions := make([]Position, 0, len(s.people))
+	for _, p := range s.people {
+		positions = append(positions, Position{Name: p.name, Room: p.room, Activity: p.activity})
+	}
+	return positions
+}
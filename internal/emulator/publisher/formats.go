@@ -0,0 +1,115 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Format описывает топики и сообщения, которые публикует устройство
+type Format struct {
+	Topic             string // Шаблон топика показаний
+	AvailabilityTopic string // Шаблон топика доступности
+	Online            string // Сообщение "устройство в сети"
+	Offline           string // Сообщение "устройство недоступно" (LWT)
+
+	// Payload формирует сообщение с показанием датчика
+	Payload func(sensorType string, reading models.Reading) ([]byte, error)
+}
+
+// Formats - поддерживаемые форматы сообщений
+var Formats = map[string]Format{
+	// Zigbee2MQTT: zigbee2mqtt/<устройство> {"temperature":21.4}
+	"zigbee2mqtt": {
+		Topic:             "zigbee2mqtt/{id}",
+		AvailabilityTopic: "zigbee2mqtt/{id}/availability",
+		Online:            `{"state":"online"}`,
+		Offline:           `{"state":"offline"}`,
+		Payload:           zigbee2mqttPayload,
+	},
+	// Tasmota: tele/<устройство>/SENSOR {"Time":"...","DS18B20":{"Temperature":21.4},"TempUnit":"C"}
+	"tasmota": {
+		Topic:             "tele/{id}/SENSOR",
+		AvailabilityTopic: "tele/{id}/LWT",
+		Online:            "Online",
+		Offline:           "Offline",
+		Payload:           tasmotaPayload,
+	},
+}
+
+// zigbee2mqttKeys - имена свойств Zigbee2MQTT для скалярных показаний
+var zigbee2mqttKeys = map[string]string{
+	"temperature": "temperature",
+	"motion":      "occupancy",
+}
+
+// zigbee2mqttPayload публикует показание как набор свойств устройства;
+// составные показания (co2, nh3) становятся отдельными свойствами
+func zigbee2mqttPayload(sensorType string, reading models.Reading) ([]byte, error) {
+	payload := make(map[string]interface{})
+	switch v := reading.Value.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			payload[key] = value
+		}
+	default:
+		key, ok := zigbee2mqttKeys[sensorType]
+		if !ok {
+			key = sensorType
+		}
+		payload[key] = v
+	}
+	return json.Marshal(payload)
+}
+
+// tasmotaSensors - имена датчиков Tasmota по типу показаний
+var tasmotaSensors = map[string]string{
+	"temperature": "DS18B20",
+	"air_quality": "MQ135",
+}
+
+// tasmotaPayload публикует показание как телеметрию Tasmota: время без
+// часового пояса и данные под именем датчика; движение - как Switch1
+func tasmotaPayload(sensorType string, reading models.Reading) ([]byte, error) {
+	payload := map[string]interface{}{
+		"Time": reading.Timestamp.Format("2006-01-02T15:04:05"),
+	}
+
+	name, ok := tasmotaSensors[sensorType]
+	if !ok {
+		name = strings.ToUpper(sensorType)
+	}
+
+	switch v := reading.Value.(type) {
+	case bool:
+		state := "OFF"
+		if v {
+			state = "ON"
+		}
+		payload["Switch1"] = state
+	case map[string]interface{}:
+		fields := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			fields[strings.ToUpper(key)] = value
+		}
+		payload[name] = fields
+	case float64, int:
+		if sensorType == "temperature" {
+			payload[name] = map[string]interface{}{"Temperature": v}
+			payload["TempUnit"] = "C"
+		} else {
+			payload[name] = map[string]interface{}{"Value": v}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported reading value %T", reading.Value)
+	}
+
+	return json.Marshal(payload)
+}
+
+// expand подставляет ID и тип датчика в шаблон топика
+func expand(template, id, sensorType string) string {
+	return strings.NewReplacer("{id}", id, "{type}", sensorType).Replace(template)
+}
@@ -0,0 +1,175 @@
+// Package publisher публикует показания датчиков эмулятора в MQTT так, как это
+// делают Zigbee2MQTT и Tasmota, чтобы проверять прием по MQTT без реальных
+// устройств. Каждый датчик подключается к брокеру отдельным клиентом со своим
+// LWT, поэтому при аварийной остановке эмулятора брокер сам помечает
+// устройства недоступными
+package publisher
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/faults"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// publishTimeout - сколько ждать подтверждения публикации
+const publishTimeout = 5 * time.Second
+
+// Publisher публикует показания датчиков в MQTT
+type Publisher struct {
+	cfg     config.EmulatorMQTTConfig
+	format  Format
+	logger  *log.Logger
+	devices map[string]*device
+}
+
+// device - подключение одного датчика к брокеру
+type device struct {
+	client            mqtt.Client
+	sensorType        string
+	topic             string
+	availabilityTopic string
+
+	mu      sync.Mutex
+	offline bool
+}
+
+// New создает публикатор для датчиков; подключение выполняется в Start
+func New(cfg config.EmulatorMQTTConfig, sensors []models.Sensor, logger *log.Logger) (*Publisher, error) {
+	format, ok := Formats[cfg.Format]
+	if !ok {
+		return nil, fmt.Errorf("unknown MQTT format %q", cfg.Format)
+	}
+	if cfg.Topic != "" {
+		format.Topic = cfg.Topic
+	}
+	if cfg.AvailabilityTopic != "" {
+		format.AvailabilityTopic = cfg.AvailabilityTopic
+	}
+
+	p := &Publisher{
+		cfg:     cfg,
+		format:  format,
+		logger:  logger,
+		devices: make(map[string]*device, len(sensors)),
+	}
+	for _, sensor := range sensors {
+		p.devices[sensor.ID()] = p.newDevice(sensor)
+	}
+	return p, nil
+}
+
+// newDevice настраивает клиента датчика: LWT и сообщение о доступности при
+// каждом подключении
+func (p *Publisher) newDevice(sensor models.Sensor) *device {
+	d := &device{
+		sensorType:        sensor.Type(),
+		topic:             expand(p.format.Topic, sensor.ID(), sensor.Type()),
+		availabilityTopic: expand(p.format.AvailabilityTopic, sensor.ID(), sensor.Type()),
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(p.cfg.Broker).
+		SetClientID("emulator-"+sensor.ID()).
+		SetUsername(p.cfg.Username).
+		SetPassword(p.cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetWill(d.availabilityTopic, p.format.Offline, p.cfg.QoS, true).
+		SetOnConnectHandler(func(client mqtt.Client) {
+			d.mu.Lock()
+			payload := p.format.Online
+			if d.offline {
+				payload = p.format.Offline
+			}
+			d.mu.Unlock()
+			client.Publish(d.availabilityTopic, p.cfg.QoS, true, payload)
+		})
+	d.client = mqtt.NewClient(opts)
+	return d
+}
+
+// Start подключает датчики к брокеру. Недоступный брокер не мешает запуску:
+// подключение повторяется в фоне
+func (p *Publisher) Start() {
+	p.logger.Printf("Publishing %d sensors to MQTT broker %s (%s format)", len(p.devices), p.cfg.Broker, p.cfg.Format)
+	for _, d := range p.devices {
+		d.client.Connect()
+	}
+}
+
+// Stop помечает датчики недоступными и отключается от брокера. При штатной
+// остановке LWT не отправляется, поэтому сообщение публикуется явно
+func (p *Publisher) Stop() {
+	for _, d := range p.devices {
+		if d.client.IsConnected() {
+			d.client.Publish(d.availabilityTopic, p.cfg.QoS, true, p.format.Offline).WaitTimeout(publishTimeout)
+		}
+		d.client.Disconnect(250)
+	}
+}
+
+// Publish публикует результат опроса датчика. Недоступность датчика
+// (faults.ErrOffline) публикуется в топик доступности, другие ошибки чтения
+// не публикуются, как и у настоящих устройств
+func (p *Publisher) Publish(sensorID string, reading models.Reading, err error) error {
+	d, ok := p.devices[sensorID]
+	if !ok {
+		return fmt.Errorf("sensor %s is not published", sensorID)
+	}
+
+	if errors.Is(err, faults.ErrOffline) {
+		return p.setOffline(d, true)
+	}
+	if err != nil {
+		return nil
+	}
+	if err := p.setOffline(d, false); err != nil {
+		return err
+	}
+
+	// Пока брокер недоступен, показания отбрасываются, а не копятся в очереди
+	// клиента: после подключения важны только свежие значения
+	if !d.client.IsConnectionOpen() {
+		return nil
+	}
+
+	payload, err := p.format.Payload(d.sensorType, reading)
+	if err != nil {
+		return err
+	}
+	return wait(d.client.Publish(d.topic, p.cfg.QoS, p.cfg.Retain, payload))
+}
+
+// setOffline публикует изменение доступности датчика
+func (p *Publisher) setOffline(d *device, offline bool) error {
+	d.mu.Lock()
+	changed := d.offline != offline
+	d.offline = offline
+	d.mu.Unlock()
+
+	// Без подключения состояние опубликует обработчик подключения
+	if !changed || !d.client.IsConnectionOpen() {
+		return nil
+	}
+
+	payload := p.format.Online
+	if offline {
+		payload = p.format.Offline
+	}
+	return wait(d.client.Publish(d.availabilityTopic, p.cfg.QoS, true, payload))
+}
+
+// wait ждет подтверждения публикации
+func wait(token mqtt.Token) error {
+	if !token.WaitTimeout(publishTimeout) {
+		return errors.New("MQTT publish timed out")
+	}
+	return token.Error()
+}
@@ -0,0 +1,209 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// ReadingSource - хранилище показаний (database.Repository)
+type ReadingSource interface {
+	GetReadingsInRange(sensorID string, from, to time.Time) ([]models.SensorData, error)
+}
+
+// LoadFile загружает записи датчика sensorID из файла экспорта. Формат
+// определяется расширением: .csv или .ndjson/.jsonl. Нулевые from и to
+// не ограничивают период
+func LoadFile(path, sensorID string, from, to time.Time) ([]models.SensorData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	defer f.Close()
+
+	var records []models.SensorData
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err = readCSV(f)
+	case ".ndjson", ".jsonl", ".json":
+		records, err = readNDJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported replay file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file %s: %w", path, err)
+	}
+
+	return filter(records, sensorID, from, to), nil
+}
+
+// LoadRange загружает показания датчика из хранилища за период
+func LoadRange(source ReadingSource, sensorID string, from, to time.Time) ([]models.SensorData, error) {
+	records, err := source.GetReadingsInRange(sensorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return filter(records, sensorID, from, to), nil
+}
+
+// filter оставляет записи датчика за период и сортирует их по времени
+func filter(records []models.SensorData, sensorID string, from, to time.Time) []models.SensorData {
+	result := make([]models.SensorData, 0, len(records))
+	for _, r := range records {
+		if r.SensorID != "" && r.SensorID != sensorID {
+			continue
+		}
+		if (!from.IsZero() && r.Timestamp.Before(from)) || (!to.IsZero() && r.Timestamp.After(to)) {
+			continue
+		}
+		result = append(result, r)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+	return result
+}
+
+// ndjsonRecord - строка NDJSON; значение может быть как в формате
+// models.SensorValue ({"data": ...}), так и непосредственно значением
+type ndjsonRecord struct {
+	SensorID   string          `json:"sensorId"`
+	SensorType string          `json:"sensorType"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Value      json.RawMessage `json:"value"`
+	Unit       string          `json:"unit"`
+}
+
+// readNDJSON читает записи по одной на строку (формат пакетной генерации эмулятора)
+func readNDJSON(r io.Reader) ([]models.SensorData, error) {
+	var records []models.SensorData
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record ndjsonRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(record.Value, &value); err != nil {
+			return nil, fmt.Errorf("line %d: invalid value: %w", line, err)
+		}
+		if wrapped, ok := value.(map[string]interface{}); ok && len(wrapped) == 1 {
+			if data, ok := wrapped["data"]; ok {
+				value = data
+			}
+		}
+
+		records = append(records, models.SensorData{
+			SensorID:   record.SensorID,
+			SensorType: record.SensorType,
+			Timestamp:  record.Timestamp,
+			Value:      models.SensorValue{Data: value},
+			Unit:       record.Unit,
+		})
+	}
+
+	return records, scanner.Err()
+}
+
+// readCSV читает CSV с заголовком. Обязательны колонки timestamp (RFC3339) и
+// value либо поля составного значения (например, co2 и nh3); колонки
+// sensor_id и sensor_type необязательны
+func readCSV(r io.Reader) ([]models.SensorData, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["timestamp"]; !ok {
+		return nil, errors.New("timestamp column is required")
+	}
+
+	var records []models.SensorData
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := models.SensorData{}
+		fields := make(map[string]interface{})
+		for name, i := range columns {
+			if i >= len(row) {
+				continue
+			}
+			cell := strings.TrimSpace(row[i])
+			switch name {
+			case "sensor_id":
+				record.SensorID = cell
+			case "sensor_type":
+				record.SensorType = cell
+			case "unit":
+				record.Unit = cell
+			case "timestamp":
+				if record.Timestamp, err = time.Parse(time.RFC3339, cell); err != nil {
+					return nil, fmt.Errorf("line %d: invalid timestamp: %w", line, err)
+				}
+			case "value":
+				record.Value.Data = parseValue(cell)
+			default:
+				if cell != "" {
+					fields[name] = parseValue(cell)
+				}
+			}
+		}
+
+		if _, ok := columns["value"]; !ok {
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("line %d: value is empty", line)
+			}
+			record.Value.Data = fields
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseValue разбирает значение ячейки: число, логическое значение, JSON-объект или строку
+func parseValue(cell string) interface{} {
+	if f, err := strconv.ParseFloat(cell, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(cell); err == nil {
+		return b
+	}
+	if strings.HasPrefix(cell, "{") {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(cell), &object); err == nil {
+			return object
+		}
+	}
+	return cell
+}
@@ -0,0 +1,154 @@
+// Package replay воспроизводит записанные показания датчиков (экспорт CSV или
+// NDJSON, либо таблица sensor_readings за период) с исходными интервалами или
+// ускоренно, чтобы прогонять сборщик, оповещения и автоматизации на реальных
+// исторических данных
+package replay
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Значения по умолчанию
+const (
+	defaultInterval = 10 * time.Second
+	// minInterval - нижняя граница периода опроса при ускоренном воспроизведении
+	minInterval = 100 * time.Millisecond
+)
+
+// Options - параметры воспроизведения
+type Options struct {
+	Type               string        // Тип датчика, по умолчанию из записей
+	Speed              float64       // Ускорение, по умолчанию 1
+	Loop               bool          // Повторять запись по кругу
+	OriginalTimestamps bool          // Сохранять исходные метки времени
+	Interval           time.Duration // Период опроса, по умолчанию по минимальному интервалу записи
+	Clock              sim.Clock     // Часы эмулятора, по умолчанию реальное время
+}
+
+// Sensor воспроизводит записанные показания: запись выдается, когда с начала
+// воспроизведения прошло столько же времени (с учетом ускорения), сколько
+// прошло с начала записи; если очередная запись еще не наступила, Read
+// возвращает models.ErrNotReady
+type Sensor struct {
+	id       string
+	typ      string
+	records  []models.SensorData
+	opts     Options
+	clock    sim.Clock
+	interval time.Duration
+
+	mu    sync.Mutex
+	start time.Time // Начало текущего прохода
+	next  int
+}
+
+// NewSensor создает датчик воспроизведения по записям в хронологическом порядке
+func NewSensor(id string, records []models.SensorData, opts Options) (*Sensor, error) {
+	if len(records) == 0 {
+		return nil, errors.New("no recorded readings to replay")
+	}
+	if opts.Speed <= 0 {
+		opts.Speed = 1
+	}
+
+	typ := opts.Type
+	if typ == "" {
+		typ = records[0].SensorType
+	}
+	if typ == "" {
+		return nil, errors.New("sensor type is not recorded and must be configured")
+	}
+
+	s := &Sensor{
+		id:       id,
+		typ:      typ,
+		records:  records,
+		opts:     opts,
+		clock:    sim.Or(opts.Clock),
+		interval: opts.Interval,
+	}
+	if s.interval == 0 {
+		s.interval = s.defaultInterval()
+	}
+	return s, nil
+}
+
+// defaultInterval возвращает минимальный интервал между записями с учетом ускорения
+func (s *Sensor) defaultInterval() time.Duration {
+	var spacing time.Duration
+	for i := 1; i < len(s.records); i++ {
+		d := s.records[i].Timestamp.Sub(s.records[i-1].Timestamp)
+		if d > 0 && (spacing == 0 || d < spacing) {
+			spacing = d
+		}
+	}
+	if spacing == 0 {
+		spacing = defaultInterval
+	}
+
+	interval := time.Duration(float64(spacing) / s.opts.Speed)
+	if interval < minInterval {
+		interval = minInterval
+	}
+	return interval
+}
+
+func (s *Sensor) ID() string              { return s.id }
+func (s *Sensor) Type() string            { return s.typ }
+func (s *Sensor) Interval() time.Duration { return s.interval }
+
+// Read возвращает очередную наступившую запись. Если наступило несколько
+// записей (опрос реже записи), они выдаются по одной за опрос
+func (s *Sensor) Read() (models.Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if s.start.IsZero() {
+		s.start = now
+	}
+
+	if s.next >= len(s.records) {
+		if !s.opts.Loop {
+			return models.Reading{}, models.ErrNotReady
+		}
+		// Следующий проход начинается через интервал после последней записи
+		s.start = s.start.Add(s.offset(len(s.records)-1) + s.interval)
+		s.next = 0
+	}
+
+	offset := s.offset(s.next)
+	if now.Sub(s.start) < offset {
+		return models.Reading{}, models.ErrNotReady
+	}
+
+	record := s.records[s.next]
+	s.next++
+
+	timestamp := s.start.Add(offset).UTC()
+	if s.opts.OriginalTimestamps {
+		timestamp = record.Timestamp.UTC()
+	}
+
+	return models.Reading{
+		Value:     record.Value.Data,
+		Timestamp: timestamp,
+	}, nil
+}
+
+// offset возвращает момент записи i относительно начала прохода с учетом ускорения
+func (s *Sensor) offset(i int) time.Duration {
+	return time.Duration(float64(s.records[i].Timestamp.Sub(s.records[0].Timestamp)) / s.opts.Speed)
+}
+
+// Remaining возвращает количество еще не выданных записей текущего прохода
+func (s *Sensor) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records) - s.next
+}
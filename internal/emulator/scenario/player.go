@@ -0,0 +1,287 @@
+package scenario
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Fired - отчет о выполненном шаге
+type Fired struct {
+	Step    int       `json:"step"` // Номер шага в файле, начиная с 1
+	At      time.Time `json:"at"`
+	Summary string    `json:"summary"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// override - подмена значения датчика (или поля составного значения)
+type override struct {
+	value  interface{} // set: постоянное значение
+	ramp   bool
+	from   float64
+	to     float64
+	start  time.Time
+	end    time.Time // конец изменения для ramp
+	expiry time.Time // конец действия подмены, нулевое - до release
+}
+
+// at возвращает значение подмены в момент now
+func (o *override) at(now time.Time) interface{} {
+	if !o.ramp {
+		return o.value
+	}
+	if !now.Before(o.end) {
+		return o.to
+	}
+	frac := float64(now.Sub(o.start)) / float64(o.end.Sub(o.start))
+	return math.Round((o.from+(o.to-o.from)*frac)*10) / 10
+}
+
+// Player воспроизводит сценарий по часам эмулятора
+type Player struct {
+	scenario *Scenario
+	order    []int // Индексы шагов в порядке времени
+	clock    sim.Clock
+	devices  map[string]models.Actuator
+	logger   *log.Logger
+
+	mu        sync.Mutex
+	start     time.Time
+	next      int
+	overrides map[string]map[string]*override // датчик -> поле -> подмена
+	last      map[string]interface{}          // последние собственные показания датчиков
+	fired     []Fired
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPlayer создает проигрыватель сценария. sensors и devices - датчики и
+// устройства эмулятора, которым адресованы шаги; шаги с неизвестными ID или
+// значением, не подходящим датчику, - ошибка. clock может быть nil (реальное время)
+func NewPlayer(sc *Scenario, clock sim.Clock, sensors []models.Sensor, devices []models.Actuator, logger *log.Logger) (*Player, error) {
+	if err := sc.Check(sensors, devices); err != nil {
+		return nil, fmt.Errorf("invalid scenario %q: %w", sc.Name, err)
+	}
+
+	order := make([]int, len(sc.Steps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sc.Steps[order[i]].At < sc.Steps[order[j]].At })
+
+	byID := make(map[string]models.Actuator, len(devices))
+	for _, d := range devices {
+		byID[d.ID()] = d
+	}
+
+	return &Player{
+		scenario:  sc,
+		order:     order,
+		clock:     sim.Or(clock),
+		devices:   byID,
+		logger:    logger,
+		overrides: make(map[string]map[string]*override),
+		last:      make(map[string]interface{}),
+		stopChan:  make(chan struct{}),
+	}, nil
+}
+
+// Begin задает момент начала сценария
+func (p *Player) Begin(start time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.start = start
+	p.logger.Printf("Scenario %q started at %s (%d steps)", p.scenario.Name, start.Format(time.RFC3339), len(p.order))
+}
+
+// Start начинает сценарий сейчас и выполняет шаги по мере наступления их времени
+func (p *Player) Start() {
+	p.Begin(p.clock.Now())
+
+	go func() {
+		period := time.Second
+		if scaled, ok := p.clock.(*sim.ScaledClock); ok {
+			period = scaled.RealDuration(time.Second)
+		}
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if p.AdvanceTo(p.clock.Now()) {
+					return
+				}
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop останавливает воспроизведение
+func (p *Player) Stop() {
+	p.stopOnce.Do(func() { close(p.stopChan) })
+}
+
+// AdvanceTo выполняет шаги, время которых наступило к моменту now, и
+// сообщает, выполнены ли все шаги
+func (p *Player) AdvanceTo(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.start.IsZero() {
+		return false
+	}
+
+	for p.next < len(p.order) {
+		index := p.order[p.next]
+		step := p.scenario.Steps[index]
+		at := p.start.Add(step.At)
+		if at.After(now) {
+			return false
+		}
+		p.next++
+		p.fire(index, step, at)
+		if p.next == len(p.order) {
+			p.logger.Printf("Scenario %q completed", p.scenario.Name)
+		}
+	}
+	return true
+}
+
+// fire выполняет шаг; вызывается под мьютексом
+func (p *Player) fire(index int, step Step, at time.Time) {
+	report := Fired{Step: index + 1, At: at, Summary: step.String()}
+
+	switch step.Type {
+	case StepSet, StepRamp:
+		o := &override{value: step.Value, start: at}
+		if step.For > 0 {
+			o.expiry = at.Add(step.For)
+		}
+		if step.Type == StepRamp {
+			o.ramp = true
+			o.to, _ = toFloat(step.Value)
+			o.end = at.Add(step.Over)
+			o.from = o.to
+			if step.From != nil {
+				o.from = *step.From
+			} else if current, ok := p.current(step.Sensor, step.Field, at); ok {
+				o.from = current
+			}
+		}
+		if p.overrides[step.Sensor] == nil {
+			p.overrides[step.Sensor] = make(map[string]*override)
+		}
+		p.overrides[step.Sensor][step.Field] = o
+
+	case StepRelease:
+		delete(p.overrides, step.Sensor)
+
+	case StepDevice:
+		device, ok := p.devices[step.Device]
+		if !ok {
+			report.Error = "device not found"
+			break
+		}
+		if _, err := device.Execute(models.Command{Name: step.Command, Value: step.Value}); err != nil {
+			report.Error = err.Error()
+		}
+	}
+
+	p.fired = append(p.fired, report)
+	if report.Error != "" {
+		p.logger.Printf("Scenario %q step %d at +%s failed: %s: %s", p.scenario.Name, report.Step, step.At, report.Summary, report.Error)
+		return
+	}
+	p.logger.Printf("Scenario %q step %d at +%s: %s", p.scenario.Name, report.Step, step.At, report.Summary)
+}
+
+// current возвращает текущее числовое значение поля датчика: значение
+// действующей подмены или последнее собственное показание
+func (p *Player) current(sensor, field string, now time.Time) (float64, bool) {
+	var value interface{}
+	if o, ok := p.overrides[sensor][field]; ok {
+		value = o.at(now)
+	} else if last, ok := p.last[sensor]; ok {
+		value = last
+		if field != "" {
+			fields, _ := last.(map[string]interface{})
+			value = fields[field]
+		}
+	}
+	return toFloat(value)
+}
+
+// Fired возвращает отчет о выполненных шагах
+func (p *Player) Fired() []Fired {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Fired(nil), p.fired...)
+}
+
+// apply подменяет значение показания датчика согласно действующим подменам
+func (p *Player) apply(sensorID string, reading models.Reading) models.Reading {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.last[sensorID] = reading.Value
+
+	fields := p.overrides[sensorID]
+	for field, o := range fields {
+		if !o.expiry.IsZero() && !reading.Timestamp.Before(o.expiry) {
+			delete(fields, field)
+			continue
+		}
+
+		value := o.at(reading.Timestamp)
+		if field == "" {
+			reading.Value = value
+			continue
+		}
+
+		// Подменяем одно поле, не изменяя исходное значение датчика
+		original, _ := reading.Value.(map[string]interface{})
+		replaced := make(map[string]interface{}, len(original)+1)
+		for k, v := range original {
+			replaced[k] = v
+		}
+		replaced[field] = value
+		reading.Value = replaced
+	}
+
+	return reading
+}
+
+// Wrap подключает датчики к сценарию: их показания подменяются шагами set и ramp
+func (p *Player) Wrap(sensors []models.Sensor) []models.Sensor {
+	wrapped := make([]models.Sensor, 0, len(sensors))
+	for _, s := range sensors {
+		wrapped = append(wrapped, &sensor{Sensor: s, player: p})
+	}
+	return wrapped
+}
+
+// sensor - датчик, показания которого может подменять сценарий
+type sensor struct {
+	models.Sensor
+	player *Player
+}
+
+// Read выполняет наступившие шаги сценария и возвращает показание с учетом подмен
+func (s *sensor) Read() (models.Reading, error) {
+	reading, err := s.Sensor.Read()
+	if err != nil {
+		return reading, err
+	}
+	s.player.AdvanceTo(reading.Timestamp)
+	return s.player.apply(s.ID(), reading), nil
+}
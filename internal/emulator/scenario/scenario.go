@@ -0,0 +1,247 @@
+// Package scenario воспроизводит сценарии эмулятора: YAML-таймлайн шагов,
+// которые в заданные моменты подменяют показания датчиков (постоянное
+// значение или плавное изменение), возвращают датчики к обычной модели,
+// отправляют команды устройствам и отмечают события
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+	"gopkg.in/yaml.v3"
+)
+
+// Типы шагов сценария
+const (
+	StepSet     = "set"     // Датчик показывает постоянное значение
+	StepRamp    = "ramp"    // Значение датчика плавно меняется до заданного
+	StepRelease = "release" // Датчик возвращается к обычной модели
+	StepDevice  = "device"  // Команда устройству эмулятора
+	StepEvent   = "event"   // Отметка в журнале (например, "дом поставлен на охрану")
+)
+
+// Scenario - сценарий эмулятора
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Steps       []Step `yaml:"steps"`
+}
+
+// Step - шаг сценария; At отсчитывается от начала воспроизведения
+type Step struct {
+	At      time.Duration `yaml:"at"`
+	Type    string        `yaml:"type"`
+	Sensor  string        `yaml:"sensor"`  // set, ramp, release: ID датчика
+	Field   string        `yaml:"field"`   // set, ramp: поле составного значения (co2, nh3), пусто - значение целиком
+	Value   interface{}   `yaml:"value"`   // set: значение; ramp: конечное значение; device: аргумент команды
+	From    *float64      `yaml:"from"`    // ramp: начальное значение, по умолчанию последнее показание датчика
+	Over    time.Duration `yaml:"over"`    // ramp: длительность изменения
+	For     time.Duration `yaml:"for"`     // set, ramp: сколько действует подмена, 0 - до release
+	Device  string        `yaml:"device"`  // device: ID устройства
+	Command string        `yaml:"command"` // device: команда
+	Message string        `yaml:"message"` // event: текст события; для остальных шагов - пояснение
+}
+
+// Load загружает сценарий из YAML-файла
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var sc Scenario
+	if err := yaml.Unmarshal(data, &sc); err != nil {
+		return nil, fmt.Errorf("failed to decode scenario: %w", err)
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %q: %w", sc.Name, err)
+	}
+
+	return &sc, nil
+}
+
+// Validate проверяет сценарий
+func (s Scenario) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("scenario name cannot be empty")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	for i, step := range s.Steps {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Check проверяет, что шаги сценария адресованы существующим датчикам и
+// устройствам, а значения шагов set и ramp подходят схеме значения датчика
+func (s Scenario) Check(sensors []models.Sensor, devices []models.Actuator) error {
+	sensorTypes := make(map[string]string, len(sensors))
+	for _, sensor := range sensors {
+		sensorTypes[sensor.ID()] = sensor.Type()
+	}
+	deviceIDs := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		deviceIDs[device.ID()] = true
+	}
+
+	for i, step := range s.Steps {
+		switch step.Type {
+		case StepSet, StepRamp, StepRelease:
+			sensorType, ok := sensorTypes[step.Sensor]
+			if !ok {
+				return fmt.Errorf("step %d: unknown sensor %q", i+1, step.Sensor)
+			}
+			if err := step.checkValue(sensorType); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+		case StepDevice:
+			if !deviceIDs[step.Device] {
+				return fmt.Errorf("step %d: unknown device %q", i+1, step.Device)
+			}
+		}
+	}
+	return nil
+}
+
+// checkValue проверяет поле и значение шага set или ramp по схеме типа
+// датчика. Значения незарегистрированных типов не проверяются
+func (s Step) checkValue(sensorType string) error {
+	t, ok := sensortype.Lookup(sensorType)
+	if !ok || s.Type == StepRelease {
+		return nil
+	}
+	schema := t.Value
+
+	if s.Field != "" {
+		if schema.Kind != models.KindObject {
+			return fmt.Errorf("%s sensor %s has no fields", sensorType, s.Sensor)
+		}
+		if !contains(schema.Fields, s.Field) {
+			return fmt.Errorf("%s sensor %s has no field %s (expected %s)", sensorType, s.Sensor, s.Field, strings.Join(schema.Fields, ", "))
+		}
+		if _, ok := toFloat(s.Value); !ok {
+			return fmt.Errorf("value of field %s must be a number", s.Field)
+		}
+		return nil
+	}
+
+	if s.Type == StepRamp {
+		switch schema.Kind {
+		case models.KindObject:
+			return fmt.Errorf("ramp of %s sensor %s requires a field (%s)", sensorType, s.Sensor, strings.Join(schema.Fields, ", "))
+		case models.KindBool:
+			return fmt.Errorf("%s sensor %s cannot be ramped", sensorType, s.Sensor)
+		}
+		return nil
+	}
+	if _, err := schema.Decode(s.Value); err != nil {
+		return fmt.Errorf("invalid value for %s sensor %s: %w", sensorType, s.Sensor, err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate проверяет шаг сценария
+func (s Step) Validate() error {
+	if s.At < 0 || s.For < 0 {
+		return fmt.Errorf("step times cannot be negative")
+	}
+
+	switch s.Type {
+	case StepSet:
+		if s.Sensor == "" {
+			return fmt.Errorf("sensor is required")
+		}
+		if s.Value == nil {
+			return fmt.Errorf("value is required")
+		}
+	case StepRamp:
+		if s.Sensor == "" {
+			return fmt.Errorf("sensor is required")
+		}
+		if _, ok := toFloat(s.Value); !ok {
+			return fmt.Errorf("ramp target must be a number")
+		}
+		if s.Over <= 0 {
+			return fmt.Errorf("ramp duration must be positive")
+		}
+	case StepRelease:
+		if s.Sensor == "" {
+			return fmt.Errorf("sensor is required")
+		}
+	case StepDevice:
+		if s.Device == "" || s.Command == "" {
+			return fmt.Errorf("device and command are required")
+		}
+	case StepEvent:
+		if s.Message == "" {
+			return fmt.Errorf("event message is required")
+		}
+	default:
+		return fmt.Errorf("unknown step type %q", s.Type)
+	}
+	return nil
+}
+
+// String описывает шаг для журнала
+func (s Step) String() string {
+	var text string
+	switch s.Type {
+	case StepSet:
+		text = fmt.Sprintf("%s%s = %v", s.Sensor, fieldSuffix(s.Field), s.Value)
+	case StepRamp:
+		text = fmt.Sprintf("%s%s -> %v over %s", s.Sensor, fieldSuffix(s.Field), s.Value, s.Over)
+	case StepRelease:
+		text = fmt.Sprintf("%s released", s.Sensor)
+	case StepDevice:
+		text = fmt.Sprintf("%s %s", s.Device, s.Command)
+		if s.Value != nil {
+			text += fmt.Sprintf(" %v", s.Value)
+		}
+	case StepEvent:
+		return s.Message
+	}
+	if s.For > 0 {
+		text += fmt.Sprintf(" for %s", s.For)
+	}
+	if s.Message != "" {
+		text += " (" + s.Message + ")"
+	}
+	return text
+}
+
+func fieldSuffix(field string) string {
+	if field == "" {
+		return ""
+	}
+	return "." + field
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
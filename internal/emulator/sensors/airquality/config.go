@@ -3,6 +3,8 @@ package airquality
 import (
 	"errors"
 	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 )
 
 type Config struct {
@@ -12,6 +14,8 @@ type Config struct {
 	MinNH3   float64       `yaml:"min_nh3"`
 	MaxNH3   float64       `yaml:"max_nh3"`
 	Interval time.Duration `yaml:"interval"`
+	Seed     int64         `yaml:"seed"` // Зерно генератора случайных чисел, 0 - случайное
+	Clock    sim.Clock     `yaml:"-"`    // Часы эмулятора, по умолчанию реальное время
 }
 
 func (c Config) Validate() error {
@@ -2,8 +2,10 @@ package airquality
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
 )
 
@@ -14,6 +16,9 @@ type Service struct {
 	minNH3   float64
 	maxNH3   float64
 	interval time.Duration
+	clock    sim.Clock
+	mu       sync.Mutex
+	rng      *rand.Rand
 }
 
 func New(cfg Config) (*Service, error) {
@@ -28,6 +33,8 @@ func New(cfg Config) (*Service, error) {
 		minNH3:   cfg.MinNH3,
 		maxNH3:   cfg.MaxNH3,
 		interval: cfg.Interval,
+		clock:    sim.Or(cfg.Clock),
+		rng:      sim.NewRand(cfg.Seed, cfg.ID),
 	}, nil
 }
 
@@ -39,8 +46,10 @@ func (s *Service) ID() string   { return s.id }
 func (s *Service) Type() string { return "air_quality" }
 
 func (s *Service) Read() (models.Reading, error) {
-	co2 := s.minCO2 + rand.Float64()*(s.maxCO2-s.minCO2)
-	nh3 := s.minNH3 + rand.Float64()*(s.maxNH3-s.minNH3)
+	s.mu.Lock()
+	co2 := s.minCO2 + s.rng.Float64()*(s.maxCO2-s.minCO2)
+	nh3 := s.minNH3 + s.rng.Float64()*(s.maxNH3-s.minNH3)
+	s.mu.Unlock()
 
 	// Округляем до целых значений для согласованности
 	co2 = float64(int(co2))
@@ -51,6 +60,6 @@ func (s *Service) Read() (models.Reading, error) {
 			"co2": co2,
 			"nh3": nh3,
 		},
-		Timestamp: time.Now().UTC(),
+		Timestamp: s.clock.Now().UTC(),
 	}, nil
 }
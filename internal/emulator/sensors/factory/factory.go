@@ -4,74 +4,116 @@ import (
 	"fmt"
 
 	"github.com/4Amangel1/smart-house-automate/internal/config"
-	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/airquality"
-	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/motion"
-	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/temperature"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/faults"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/occupancy"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/replay"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
 )
 
 type SensorFactory struct {
 	sensors map[string]models.Sensor
 }
 
+// Options - модели эмулятора, к которым подключаются датчики (любая может
+// быть nil), часы и общее зерно генераторов случайных чисел
+type Options struct {
+	Environment *environment.Environment
+	Occupancy   *occupancy.Simulation
+	Clock       sim.Clock            // nil - реальное время
+	Seed        int64                // 0 - случайное зерно; датчик может задать собственное
+	Readings    replay.ReadingSource // Хранилище для воспроизведения показаний из БД
+}
+
+// seed возвращает зерно датчика: собственное, если задано, иначе общее
+func (o Options) seed(sensorSeed int64) int64 {
+	if sensorSeed != 0 {
+		return sensorSeed
+	}
+	return o.Seed
+}
+
 func New(cfg config.SensorsConfig) (*SensorFactory, error) {
+	return NewWithOptions(cfg, Options{})
+}
+
+// NewWithOptions создает датчики по реестру типов с учетом моделей
+// эмулятора: датчики температуры и качества воздуха в комнатах, которые
+// моделирует окружение, показывают его состояние (если для датчика
+// температуры не выбрана другая модель), а датчики движения в комнатах графа
+// дома срабатывают от жильцов.
+// При одинаковом ненулевом зерне и часах показания воспроизводимы
+func NewWithOptions(cfg config.SensorsConfig, opts Options) (*SensorFactory, error) {
 	f := &SensorFactory{
 		sensors: make(map[string]models.Sensor),
 	}
 
-	// Температурные датчики
-	for _, c := range cfg.Temperature {
-		// Преобразуем общую конфигурацию в конфигурацию для датчика
-		sensorCfg := temperature.Config{
-			ID:       c.ID,
-			Min:      c.Min,
-			Max:      c.Max,
-			Interval: c.Interval,
-		}
-
-		s, err := temperature.New(sensorCfg)
+	// Датчики зарегистрированных типов
+	for _, entry := range cfg.List {
+		s, err := sensortype.NewSensor(entry, sensortype.Options{
+			Environment: opts.Environment,
+			Occupancy:   opts.Occupancy,
+			Clock:       opts.Clock,
+			Seed:        opts.seed(entry.Seed),
+		})
 		if err != nil {
-			return nil, fmt.Errorf("temperature sensor error: %w", err)
+			return nil, fmt.Errorf("%s sensor error: %w", entry.Type, err)
+		}
+		if err := f.add(s, entry.Faults, entry.Seed, opts); err != nil {
+			return nil, fmt.Errorf("%s sensor error: %w", entry.Type, err)
 		}
-		f.sensors[s.ID()] = s
 	}
 
-	// Датчики движения
-	for _, c := range cfg.Motion {
-		sensorCfg := motion.Config{
-			ID:                c.ID,
-			DetectionInterval: c.DetectionInterval,
-			Interval:          c.Interval,
+	// Датчики воспроизведения записанных показаний
+	for _, c := range cfg.Replay {
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("replay sensor error: %w", err)
 		}
 
-		s, err := motion.New(sensorCfg)
-		if err != nil {
-			return nil, fmt.Errorf("motion sensor error: %w", err)
+		var records []models.SensorData
+		var err error
+		if c.File != "" {
+			records, err = replay.LoadFile(c.File, c.SourceID(), c.From, c.To)
+		} else if opts.Readings != nil {
+			records, err = replay.LoadRange(opts.Readings, c.SourceID(), c.From, c.To)
+		} else {
+			err = fmt.Errorf("%s: replay from the database is not available", c.ID)
 		}
-		f.sensors[s.ID()] = s
-	}
-
-	// Датчики качества воздуха
-	for _, c := range cfg.AirQuality {
-		sensorCfg := airquality.Config{
-			ID:       c.ID,
-			MinCO2:   float64(c.MinCO2),
-			MaxCO2:   float64(c.MaxCO2),
-			MinNH3:   float64(c.MinNH3),
-			MaxNH3:   float64(c.MaxNH3),
-			Interval: c.Interval,
+		if err != nil {
+			return nil, fmt.Errorf("replay sensor error: %w", err)
 		}
 
-		s, err := airquality.New(sensorCfg)
+		s, err := replay.NewSensor(c.ID, records, replay.Options{
+			Type:               c.Type,
+			Speed:              c.Speed,
+			Loop:               c.Loop,
+			OriginalTimestamps: c.OriginalTimestamps,
+			Interval:           c.Interval,
+			Clock:              opts.Clock,
+		})
 		if err != nil {
-			return nil, fmt.Errorf("air quality sensor error: %w", err)
+			return nil, fmt.Errorf("replay sensor error: %s: %w", c.ID, err)
+		}
+		if err := f.add(s, c.Faults, 0, opts); err != nil {
+			return nil, fmt.Errorf("replay sensor error: %w", err)
 		}
-		f.sensors[s.ID()] = s
 	}
 
 	return f, nil
 }
 
+// add регистрирует датчик, добавляя к нему неисправности из конфигурации
+func (f *SensorFactory) add(s models.Sensor, cfg config.FaultsConfig, seed int64, opts Options) error {
+	wrapped, err := faults.Wrap(s, cfg, opts.Clock, opts.seed(seed))
+	if err != nil {
+		return fmt.Errorf("invalid faults of %s: %w", s.ID(), err)
+	}
+	f.sensors[s.ID()] = wrapped
+	return nil
+}
+
 func (f *SensorFactory) GetAllSensors() []models.Sensor {
 	sensors := make([]models.Sensor, 0, len(f.sensors))
 	for _, s := range f.sensors {
@@ -3,12 +3,20 @@ package motion
 import (
 	"errors"
 	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 )
 
+// DefaultProbability - вероятность срабатывания за один опрос по умолчанию
+const DefaultProbability = 0.3
+
 type Config struct {
 	ID                string `yaml:"id"`
 	Interval          time.Duration
 	DetectionInterval time.Duration `yaml:"detection_interval"`
+	Probability       float64       `yaml:"probability"`
+	Seed              int64         `yaml:"seed"` // Зерно генератора случайных чисел, 0 - случайное
+	Clock             sim.Clock     `yaml:"-"`    // Часы эмулятора, по умолчанию реальное время
 }
 
 func (c Config) Validate() error {
@@ -18,5 +26,8 @@ func (c Config) Validate() error {
 	if c.DetectionInterval < 0 {
 		return errors.New("DetectionInterval can't be below 0")
 	}
+	if c.Probability < 0 || c.Probability > 1 {
+		return errors.New("probability must be between 0 and 1")
+	}
 	return nil
 }
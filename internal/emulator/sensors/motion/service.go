@@ -2,16 +2,22 @@ package motion
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
 )
 
 type Service struct {
+	mu                sync.Mutex
 	id                string
 	detectionInterval time.Duration
 	lastDetection     time.Time
 	interval          time.Duration
+	probability       float64
+	clock             sim.Clock
+	rng               *rand.Rand
 }
 
 func New(cfg Config) (*Service, error) {
@@ -19,11 +25,17 @@ func New(cfg Config) (*Service, error) {
 		return nil, err
 	}
 
+	if cfg.Probability == 0 {
+		cfg.Probability = DefaultProbability
+	}
+
 	return &Service{
 		id:                cfg.ID,
 		detectionInterval: cfg.DetectionInterval,
 		interval:          cfg.Interval,
-		lastDetection:     time.Now().Add(-24 * time.Hour), // Инициализация в прошлом
+		probability:       cfg.Probability,
+		clock:             sim.Or(cfg.Clock),
+		rng:               sim.NewRand(cfg.Seed, cfg.ID),
 	}, nil
 }
 
@@ -35,10 +47,13 @@ func (s *Service) ID() string   { return s.id }
 func (s *Service) Type() string { return "motion" }
 
 func (s *Service) Read() (models.Reading, error) {
-	now := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now().UTC()
 
-	// 30% шанс обнаружения движения
-	detected := rand.Float64() < 0.3
+	// Срабатывание с заданной вероятностью за опрос
+	detected := s.rng.Float64() < s.probability
 
 	// Если движение обнаружено, обновляем время последнего обнаружения
 	if detected {
@@ -46,7 +61,7 @@ func (s *Service) Read() (models.Reading, error) {
 	}
 
 	// Движение считается активным, если оно было обнаружено в течение detectionInterval
-	isActive := now.Sub(s.lastDetection) <= s.detectionInterval
+	isActive := !s.lastDetection.IsZero() && now.Sub(s.lastDetection) <= s.detectionInterval
 
 	return models.Reading{
 		Value:     isActive,
@@ -2,22 +2,87 @@ package temperature
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 )
 
 type Config struct {
 	ID       string        `yaml:"id"`
-	Min      float64       `yaml:"min"`
-	Max      float64       `yaml:"max"`
 	Interval time.Duration `yaml:"interval"`
+	Seed     int64         `yaml:"seed"` // Зерно генератора случайных чисел, 0 - случайное
+	Clock    sim.Clock     `yaml:"-"`    // Часы эмулятора, по умолчанию реальное время
+
+	Params `yaml:",inline"`
+}
+
+// Params - параметры модели температуры; их же разбирает тип датчика
+// temperature в реестре типов (пакет sensortype)
+type Params struct {
+	Min      float64      `yaml:"min"`
+	Max      float64      `yaml:"max"`
+	Model    string       `yaml:"model"`     // uniform, random_walk или diurnal, по умолчанию uniform
+	WalkStep float64      `yaml:"walk_step"` // Максимальное изменение за одно чтение для random_walk, °C
+	Noise    float64      `yaml:"noise"`     // Стандартное отклонение шума для random_walk и diurnal, °C
+	PeakHour *float64     `yaml:"peak_hour"` // Час суточного максимума для diurnal, по умолчанию 15
+	Events   EventsConfig `yaml:"events"`    // Скачкообразные события (открытое окно)
+}
+
+type EventsConfig struct {
+	Probability float64       `yaml:"probability"` // Вероятность начала события при каждом чтении
+	Delta       float64       `yaml:"delta"`       // Отклонение температуры, °C
+	Duration    time.Duration `yaml:"duration"`    // Длительность события
 }
 
 func (c Config) Validate() error {
 	if c.ID == "" {
 		return errors.New("sensor ID is required")
 	}
-	if c.Min >= c.Max {
-		return errors.New("invalid temperature range")
+	return c.Params.Validate()
+}
+
+func (p Params) Validate() error {
+	switch p.Model {
+	case "", ModelUniform, ModelRandomWalk, ModelDiurnal:
+	default:
+		return fmt.Errorf("unknown temperature model %q", p.Model)
+	}
+	if p.Min >= p.Max {
+		return errors.New("minimum temperature must be less than maximum")
+	}
+	if p.WalkStep < 0 || p.Noise < 0 {
+		return errors.New("walk step and noise cannot be negative")
+	}
+	// Проверяется и шаг по умолчанию: он может оказаться шире узкого диапазона
+	if p.Model == ModelRandomWalk && p.walkStep() >= p.Max-p.Min {
+		return fmt.Errorf("walk step %g must be less than the temperature range", p.walkStep())
+	}
+	if p.peakHour() < 0 || p.peakHour() >= 24 {
+		return errors.New("peak hour must be between 0 and 24")
+	}
+	if p.Events.Probability < 0 || p.Events.Probability > 1 {
+		return errors.New("event probability must be between 0 and 1")
+	}
+	if p.Events.Duration < 0 {
+		return errors.New("event duration cannot be negative")
 	}
 	return nil
 }
+
+// walkStep возвращает шаг случайного блуждания с учетом значения по умолчанию
+func (p Params) walkStep() float64 {
+	if p.WalkStep == 0 {
+		return defaultWalkStep
+	}
+	return p.WalkStep
+}
+
+// peakHour возвращает час суточного максимума; peak_hour: 0 - полночь, а
+// не отсутствие значения
+func (p Params) peakHour() float64 {
+	if p.PeakHour == nil {
+		return defaultPeakHour
+	}
+	return *p.PeakHour
+}
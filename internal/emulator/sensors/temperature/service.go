@@ -1,17 +1,20 @@
 package temperature
 
 import (
-	"math/rand"
+	"math"
+	"sync"
 	"time"
 
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
 	"github.com/4Amangel1/smart-house-automate/internal/models"
 )
 
 type Service struct {
+	mu       sync.Mutex
 	id       string
-	min      float64
-	max      float64
 	interval time.Duration
+	clock    sim.Clock
+	signal   *signal
 }
 
 func New(cfg Config) (*Service, error) {
@@ -21,9 +24,9 @@ func New(cfg Config) (*Service, error) {
 
 	return &Service{
 		id:       cfg.ID,
-		min:      cfg.Min,
-		max:      cfg.Max,
 		interval: cfg.Interval,
+		clock:    sim.Or(cfg.Clock),
+		signal:   newSignal(cfg),
 	}, nil
 }
 
@@ -35,9 +38,12 @@ func (s *Service) ID() string   { return s.id }
 func (s *Service) Type() string { return "temperature" }
 
 func (s *Service) Read() (models.Reading, error) {
-	value := s.min + rand.Float64()*(s.max-s.min)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now().UTC()
 	return models.Reading{
-		Value:     value,
-		Timestamp: time.Now().UTC(),
+		Value:     math.Round(s.signal.next(now)*10) / 10, // Округляем до 1 десятичного знака
+		Timestamp: now,
 	}, nil
 }
@@ -0,0 +1,132 @@
+package temperature
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+)
+
+// Модели генерации температуры
+const (
+	// ModelUniform - независимые равномерно распределенные значения в [min, max]
+	ModelUniform = "uniform"
+	// ModelRandomWalk - ограниченное случайное блуждание: каждое чтение
+	// отличается от предыдущего не более чем на walk_step
+	ModelRandomWalk = "random_walk"
+	// ModelDiurnal - суточный цикл (синусоида с максимумом в peak_hour) с шумом
+	ModelDiurnal = "diurnal"
+)
+
+// Значения по умолчанию
+const (
+	defaultWalkStep      = 0.1
+	defaultPeakHour      = 15
+	defaultEventDuration = 10 * time.Minute
+	// eventTimeConstant - постоянная времени изменения температуры при событии
+	eventTimeConstant = 3 * time.Minute
+)
+
+// signal генерирует последовательность показаний по выбранной модели
+type signal struct {
+	model    string
+	min, max float64
+	walkStep float64
+	noise    float64
+	peakHour float64
+	events   EventsConfig
+	rng      *rand.Rand
+
+	value float64 // Текущее значение случайного блуждания
+
+	eventStart time.Time
+	eventEnd   time.Time
+	lastOffset float64 // Отклонение в момент окончания события
+}
+
+func newSignal(cfg Config) *signal {
+	s := &signal{
+		model:    cfg.Model,
+		min:      cfg.Min,
+		max:      cfg.Max,
+		walkStep: cfg.walkStep(),
+		noise:    cfg.Noise,
+		peakHour: cfg.peakHour(),
+		events:   cfg.Events,
+		rng:      sim.NewRand(cfg.Seed, cfg.ID),
+		value:    (cfg.Min + cfg.Max) / 2,
+	}
+	if s.model == "" {
+		s.model = ModelUniform
+	}
+	if s.events.Duration == 0 {
+		s.events.Duration = defaultEventDuration
+	}
+	return s
+}
+
+// next возвращает значение на момент now; шум и события не выводят
+// показание за пределы [min, max]
+func (s *signal) next(now time.Time) float64 {
+	var value float64
+
+	switch s.model {
+	case ModelRandomWalk:
+		s.value += (s.rng.Float64()*2 - 1) * s.walkStep
+		// Отражение от границ диапазона
+		if s.value > s.max {
+			s.value = 2*s.max - s.value
+		}
+		if s.value < s.min {
+			s.value = 2*s.min - s.value
+		}
+		value = s.value + s.rng.NormFloat64()*s.noise
+
+	case ModelDiurnal:
+		mid, amplitude := (s.min+s.max)/2, (s.max-s.min)/2
+		local := now.Local()
+		hour := float64(local.Hour()) + float64(local.Minute())/60 + float64(local.Second())/3600
+		value = mid + amplitude*math.Cos(2*math.Pi*(hour-s.peakHour)/24) + s.rng.NormFloat64()*s.noise
+
+	default:
+		value = s.min + s.rng.Float64()*(s.max-s.min)
+	}
+
+	return math.Max(s.min, math.Min(s.max, value+s.eventOffset(now)))
+}
+
+// eventOffset возвращает отклонение от скачкообразного события: во время
+// события температура экспоненциально приближается к value+delta, после -
+// экспоненциально возвращается
+func (s *signal) eventOffset(now time.Time) float64 {
+	if s.events.Probability == 0 || s.events.Delta == 0 {
+		return 0
+	}
+
+	tau := eventTimeConstant.Seconds()
+
+	if !s.eventEnd.IsZero() && now.Before(s.eventEnd) {
+		elapsed := now.Sub(s.eventStart).Seconds()
+		return s.events.Delta * (1 - math.Exp(-elapsed/tau))
+	}
+
+	if !s.eventEnd.IsZero() && s.lastOffset == 0 {
+		elapsed := s.eventEnd.Sub(s.eventStart).Seconds()
+		s.lastOffset = s.events.Delta * (1 - math.Exp(-elapsed/tau))
+	}
+
+	recovery := 0.0
+	if !s.eventEnd.IsZero() {
+		recovery = s.lastOffset * math.Exp(-now.Sub(s.eventEnd).Seconds()/tau)
+	}
+
+	// Новое событие начинается, когда предыдущее почти затухло
+	if math.Abs(recovery) < 0.05 && s.rng.Float64() < s.events.Probability {
+		s.eventStart = now
+		s.eventEnd = now.Add(s.events.Duration)
+		s.lastOffset = 0
+	}
+
+	return recovery
+}
@@ -0,0 +1,145 @@
+// Package server открывает датчики эмулятора по HTTP: список датчиков и
+// последнее показание каждого из них в формате пакета remote, как это делал
+// бы сетевой датчик
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/remote"
+)
+
+// Server отдает последние показания датчиков эмулятора
+type Server struct {
+	sensors    map[string]models.Sensor
+	logger     *log.Logger
+	httpServer *http.Server
+
+	mu     sync.RWMutex
+	latest map[string]result
+}
+
+// result - итог последнего опроса датчика
+type result struct {
+	reading models.Reading
+	err     error
+}
+
+// New создает сервер для датчиков, слушающий addr (например, ":9092")
+func New(addr string, sensors []models.Sensor, logger *log.Logger) *Server {
+	s := &Server{
+		sensors: make(map[string]models.Sensor, len(sensors)),
+		logger:  logger,
+		latest:  make(map[string]result, len(sensors)),
+	}
+	for _, sensor := range sensors {
+		s.sensors[sensor.ID()] = sensor
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(remote.SensorsPath, s.listSensors)
+	mux.HandleFunc(remote.SensorsPath+"/", s.getReading)
+	s.httpServer = &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start запускает HTTP-сервер
+func (s *Server) Start() {
+	go func() {
+		s.logger.Printf("Serving %d sensors on %s", len(s.sensors), s.httpServer.Addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Printf("Sensor server error: %v", err)
+		}
+	}()
+}
+
+// Stop останавливает HTTP-сервер
+func (s *Server) Stop(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Record сохраняет результат опроса датчика
+func (s *Server) Record(sensorID string, reading models.Reading, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest[sensorID] = result{reading: reading, err: err}
+}
+
+// listSensors возвращает список датчиков
+func (s *Server) listSensors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, remote.Reading{Error: "method not allowed"})
+		return
+	}
+
+	list := make([]remote.SensorInfo, 0, len(s.sensors))
+	for _, sensor := range s.sensors {
+		list = append(list, remote.NewSensorInfo(sensor))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getReading возвращает последнее показание датчика: 503, если датчик еще не
+// готов, 500, если последний опрос завершился ошибкой
+func (s *Server) getReading(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, remote.Reading{Error: "method not allowed"})
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, remote.SensorsPath+"/")
+	sensor, ok := s.sensors[id]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, remote.Reading{ID: id, Error: "sensor not found"})
+		return
+	}
+
+	s.mu.RLock()
+	last, ok := s.latest[id]
+	s.mu.RUnlock()
+
+	response := ToRemote(sensor, last.reading, last.err)
+	switch {
+	case !ok || errors.Is(last.err, models.ErrNotReady):
+		response.Error = models.ErrNotReady.Error()
+		writeJSON(w, http.StatusServiceUnavailable, response)
+	case last.err != nil:
+		writeJSON(w, http.StatusInternalServerError, response)
+	default:
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// ToRemote переводит результат опроса датчика в сетевой формат
+func ToRemote(sensor models.Sensor, reading models.Reading, err error) remote.Reading {
+	r := remote.Reading{ID: sensor.ID(), Type: sensor.Type()}
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Value = reading.Value
+	r.Timestamp = reading.Timestamp
+	return r
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
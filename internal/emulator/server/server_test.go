@@ -0,0 +1,90 @@
+package server
+
+import (
+	"io"
+	"log"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/actuators/hvac"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/remote"
+)
+
+// savedStates заменяет состояния устройств, которые API сохраняет в БД
+type savedStates map[string]models.ActuatorState
+
+func (s savedStates) DeviceStates() (map[string]models.ActuatorState, error) {
+	return s, nil
+}
+
+func TestCommandedHeaterChangesPolledTemperature(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	start := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	clock := sim.NewManualClock(start)
+
+	// Обогреватель принадлежит API; эмулятор видит его состояние через БД,
+	// а своих устройств у него нет
+	heater, err := hvac.New(hvac.Config{ID: "heater_kitchen", Heating: true, PowerW: 3000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := savedStates{"heater_kitchen": heater.State()}
+
+	env, err := environment.New(config.EnvironmentConfig{
+		Rooms: []config.RoomPhysicsConfig{{Room: "kitchen", Devices: []string{"heater_kitchen"}}},
+	}, environment.OverlayStates{environment.ActuatorStates(nil), saved}, clock, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.Reset(start)
+
+	sensor, err := environment.NewTemperatureSensor("temp_kitchen", time.Minute, 1, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := New("", []models.Sensor{sensor}, logger)
+	ts := httptest.NewServer(srv.httpServer.Handler)
+	defer ts.Close()
+	poll := remote.NewPollSensor("temp_kitchen", "temperature", ts.URL+remote.SensorsPath+"/temp_kitchen", time.Minute, 0)
+
+	// read продвигает модель на полчаса и возвращает показание, полученное сборщиком
+	read := func() float64 {
+		t.Helper()
+		clock.Advance(30 * time.Minute)
+		env.AdvanceTo(clock.Now())
+		reading, err := sensor.Read()
+		srv.Record(sensor.ID(), reading, err)
+
+		polled, err := poll.Read()
+		if err != nil {
+			t.Fatalf("poll failed: %v", err)
+		}
+		value, ok := polled.Value.(float64)
+		if !ok {
+			t.Fatalf("polled value %#v is not a number", polled.Value)
+		}
+		return value
+	}
+
+	idle := read()
+	if idle >= 21 {
+		t.Fatalf("room without heating did not cool down: %.1f°C", idle)
+	}
+
+	state, err := heater.Execute(models.Command{Name: models.CommandSetMode, Value: hvac.ModeHeat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved["heater_kitchen"] = state
+
+	heated := read()
+	if heated <= idle+0.5 {
+		t.Errorf("temperature after turning the heater on = %.1f°C, want it to rise above %.1f°C", heated, idle)
+	}
+}
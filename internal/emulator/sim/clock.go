@@ -0,0 +1,114 @@
+// Package sim содержит общие средства воспроизводимой эмуляции: подменяемые
+// часы (реальные, ускоренные, ручные) и генераторы случайных чисел с
+// фиксированным зерном
+package sim
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock - источник текущего времени эмулятора
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock возвращает реальное время
+type RealClock struct{}
+
+// Now реализует Clock
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// ScaledClock - ускоренное время: с момента создания модельное время
+// идет в Factor раз быстрее реального, начиная со Start
+type ScaledClock struct {
+	start     time.Time
+	realStart time.Time
+	factor    float64
+}
+
+// NewScaledClock создает ускоренные часы; нулевой start означает текущий момент
+func NewScaledClock(start time.Time, factor float64) *ScaledClock {
+	now := time.Now()
+	if start.IsZero() {
+		start = now
+	}
+	if factor <= 0 {
+		factor = 1
+	}
+	return &ScaledClock{start: start, realStart: now, factor: factor}
+}
+
+// Now реализует Clock
+func (c *ScaledClock) Now() time.Time {
+	elapsed := time.Since(c.realStart)
+	return c.start.Add(time.Duration(float64(elapsed) * c.factor))
+}
+
+// Factor возвращает коэффициент ускорения
+func (c *ScaledClock) Factor() float64 {
+	return c.factor
+}
+
+// RealDuration переводит модельный интервал в реальный
+func (c *ScaledClock) RealDuration(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / c.factor)
+}
+
+// ManualClock - часы, которые продвигаются только явно (пакетная генерация, тесты)
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock создает ручные часы, показывающие start
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now реализует Clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set устанавливает текущее время
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance продвигает часы на d
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Or возвращает clock или реальные часы, если clock не задан
+func Or(clock Clock) Clock {
+	if clock == nil {
+		return RealClock{}
+	}
+	return clock
+}
+
+// Sleep ждет модельный интервал d: реальные часы ждут d, ускоренные -
+// соответственно меньше, ручные не ждут, а продвигаются на d
+func Sleep(clock Clock, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	switch c := Or(clock).(type) {
+	case *ScaledClock:
+		time.Sleep(c.RealDuration(d))
+	case *ManualClock:
+		c.Advance(d)
+	default:
+		time.Sleep(d)
+	}
+}
@@ -0,0 +1,23 @@
+package sim
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// NewRand создает генератор случайных чисел для компонента key. При ненулевом
+// seed последовательность определяется только seed и key, поэтому не зависит
+// от порядка создания датчиков; нулевой seed означает случайное зерно
+func NewRand(seed int64, key string) *rand.Rand {
+	if seed == 0 {
+		return rand.New(rand.NewSource(time.Now().UnixNano() ^ int64(hash(key))))
+	}
+	return rand.New(rand.NewSource(seed ^ int64(hash(key))))
+}
+
+func hash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
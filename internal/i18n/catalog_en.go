@@ -0,0 +1,109 @@
+package i18n
+
+// en - каталог сообщений на английском языке
+var en = map[string]string{
+	"start.welcome": "Welcome to the smart house control system!\n\n" +
+		"Available commands:\n" +
+		"/status - current sensor readings\n" +
+		"/history [sensor_id] [period] - sensor readings chart (e.g. 24h or 7d)\n" +
+		"/report [daily|weekly] - daily or weekly summary\n" +
+		"/lang [ru|en] - interface language\n" +
+		"/units [°C|°F] [ppm|mg/m³] - units of measure\n\n" +
+		"Alert rules:\n" +
+		"/thresholds - effective thresholds\n" +
+		"/rules - list of rules\n" +
+		"/setthreshold temp_kitchen > 27 for 5m - create or change a rule\n" +
+		"/deleterule [number] - delete a rule",
+	"command.unknown":    "Unknown command. Use /start to see the list of available commands.",
+	"error.unauthorized": "You are not allowed to use this command.",
+	"error.readings":     "Failed to get data.",
+
+	"status.empty": "No readings available.",
+	"status.title": "📊 *Current sensor readings:*\n\n",
+	"status.item":  "🔹 *%s* (%s):\n%s\n\n",
+
+	"history.usage":          "Please specify a sensor ID and, optionally, a period.\nExample: /history temp_sensor_1 24h",
+	"history.invalid_period": "Invalid period. Examples: 30m, 6h, 7d (at most 365d)",
+	"history.error":          "Failed to get reading history.",
+	"history.not_found":      "No readings found for sensor %s in the last %s.",
+	"history.caption":        "📈 Readings of sensor %s for the last %s\n\nLatest value (%s):\n%s",
+	"history.title":          "📈 *Reading history of sensor %s:*\n\n",
+	"history.item":           "%d. *%s*\n%s\n\n",
+
+	"report.unknown_kind": "Unknown report type. Use /report daily or /report weekly",
+	"report.error":        "Failed to build the report.",
+
+	"lang.current": "Current language: %s\nAvailable languages: %s\nExample: /lang ru",
+	"lang.unknown": "Unknown language. Available languages: %s",
+	"lang.changed": "Interface language changed: %s",
+	"lang.error":   "Failed to save the language.",
+	"lang.name.ru": "Russian",
+	"lang.name.en": "English",
+
+	"units.current": "Units: temperature %s, gas concentration %s\nAvailable units: %s\nExample: /units °F mg/m³ or /units default\nRule thresholds are set in °C and ppm.",
+	"units.unknown": "Unknown units: %s\nAvailable units: %s",
+	"units.changed": "Units changed: temperature %s, gas concentration %s",
+	"units.error":   "Failed to save the units.",
+
+	"alert.high_temperature": "🔥 *WARNING!* High temperature (%s) on sensor %s",
+	"alert.motion":           "👤 *Motion detected* on sensor %s",
+	"alert.high_co2":         "⚠️ *WARNING!* High CO₂ level (%s) on sensor %s",
+
+	"value.temperature":     "🌡 Temperature: *%s*",
+	"value.motion_detected": "🔴 *Motion detected*",
+	"value.motion_clear":    "🟢 *No motion*",
+	"value.air_quality":     "💨 CO₂: *%s*\n💨 NH₃: *%s*",
+
+	"sensor.temperature": "Temperature",
+	"sensor.motion":      "Motion",
+	"sensor.air_quality": "Air quality",
+
+	"unit.days":    "%d d",
+	"unit.hours":   "%d h",
+	"unit.minutes": "%d min",
+
+	"report.daily":            "Daily report",
+	"report.weekly":           "Weekly report",
+	"report.header":           "📋 *%s*\n%s — %s\n\n",
+	"report.no_data":          "no data\n",
+	"report.temperature":      "🌡 *Temperature:*\n",
+	"report.temperature_item": "• %s: min %s, max %s, avg %s\n",
+	"report.motion":           "\n👤 *Motion:*\n",
+	"report.motion_item":      "• %s: %s h\n",
+	"report.peak_co2":         "\n💨 *Peak CO₂:* ",
+	"report.peak_co2_value":   "%s (%s, %s)\n",
+	"report.alerts":           "\n🔔 *Alerts:* %d\n",
+	"report.alert_item":       "• %s: %d\n",
+
+	"rules.admin_only":     "Only administrators can change alert rules.",
+	"rules.usage":          "Format: /setthreshold <sensor> [field] <operator> <value> [for <duration>]\nExamples:\n/setthreshold temp_kitchen > 27 for 5m\n/setthreshold air_kitchen co2 > 1200 for 10m\n/setthreshold motion_hallway = true",
+	"rules.invalid":        "Invalid rule: %s",
+	"rules.unknown_sensor": "Sensor %s not found.",
+	"rules.created":        "✅ Rule #%d created:\n%s",
+	"rules.updated":        "✅ Rule #%d updated:\n%s",
+	"rules.error":          "Failed to process alert rules.",
+	"rules.empty":          "No alert rules configured. Add one with /setthreshold.",
+	"rules.title":          "📏 Alert rules:\n\n",
+	"rules.item":           "#%d %s\n",
+	"rules.delete_usage":   "Specify the rule number. Example: /deleterule 3",
+	"rules.deleted":        "🗑 Rule #%d deleted.",
+	"rules.not_found":      "Rule #%d not found.",
+
+	"thresholds.title":               "📏 Alert thresholds:\n\n",
+	"thresholds.item":                "• %s\n",
+	"thresholds.default_temperature": "• Other temperature sensors: > %s\n",
+	"thresholds.default_co2":         "• Other CO₂ sensors: > %s\n",
+
+	"rule.describe":        "%s: %s %s %s",
+	"rule.for":             " for %s",
+	"rule.motion_detected": "%s: motion detected",
+	"rule.motion_clear":    "%s: no motion",
+	"rule.unknown_type":    "%s: sensor type unknown, no readings yet",
+
+	"quantity.temperature": "temperature",
+	"quantity.co2":         "CO₂",
+	"quantity.nh3":         "NH₃",
+
+	"alert.rule":       "🔔 *Rule #%d fired:* %s\n%s",
+	"alert.automation": "🤖 *Automation %s:* %s",
+}
@@ -0,0 +1,109 @@
+package i18n
+
+// ru - каталог сообщений на русском языке
+var ru = map[string]string{
+	"start.welcome": "Добро пожаловать в систему управления умным домом!\n\n" +
+		"Доступные команды:\n" +
+		"/status - текущие показания датчиков\n" +
+		"/history [sensor_id] [период] - график показаний датчика (например, 24h или 7d)\n" +
+		"/report [daily|weekly] - сводный отчет за сутки или неделю\n" +
+		"/lang [ru|en] - язык интерфейса\n" +
+		"/units [°C|°F] [ppm|mg/m³] - единицы измерения\n\n" +
+		"Правила оповещений:\n" +
+		"/thresholds - действующие пороги\n" +
+		"/rules - список правил\n" +
+		"/setthreshold temp_kitchen > 27 for 5m - создать или изменить правило\n" +
+		"/deleterule [номер] - удалить правило",
+	"command.unknown":    "Неизвестная команда. Используйте /start для получения списка доступных команд.",
+	"error.unauthorized": "У вас нет прав для использования этой команды.",
+	"error.readings":     "Ошибка при получении данных.",
+
+	"status.empty": "Нет доступных показаний.",
+	"status.title": "📊 *Текущие показания датчиков:*\n\n",
+	"status.item":  "🔹 *%s* (%s):\n%s\n\n",
+
+	"history.usage":          "Пожалуйста, укажите ID датчика и, при необходимости, период.\nПример: /history temp_sensor_1 24h",
+	"history.invalid_period": "Некорректный период. Примеры: 30m, 6h, 7d (не больше 365d)",
+	"history.error":          "Ошибка при получении истории показаний.",
+	"history.not_found":      "История показаний для датчика %s за %s не найдена.",
+	"history.caption":        "📈 История показаний датчика %s за %s\n\nПоследнее значение (%s):\n%s",
+	"history.title":          "📈 *История показаний датчика %s:*\n\n",
+	"history.item":           "%d. *%s*\n%s\n\n",
+
+	"report.unknown_kind": "Неизвестный тип отчета. Используйте /report daily или /report weekly",
+	"report.error":        "Ошибка при формировании отчета.",
+
+	"lang.current": "Текущий язык: %s\nДоступные языки: %s\nПример: /lang en",
+	"lang.unknown": "Неизвестный язык. Доступные языки: %s",
+	"lang.changed": "Язык интерфейса изменен: %s",
+	"lang.error":   "Ошибка при сохранении языка.",
+	"lang.name.ru": "русский",
+	"lang.name.en": "английский",
+
+	"units.current": "Единицы: температура %s, концентрация газов %s\nДоступные единицы: %s\nПример: /units °F mg/m³ или /units default\nПороги правил задаются в °C и ppm.",
+	"units.unknown": "Неизвестные единицы: %s\nДоступные единицы: %s",
+	"units.changed": "Единицы изменены: температура %s, концентрация газов %s",
+	"units.error":   "Ошибка при сохранении единиц измерения.",
+
+	"alert.high_temperature": "🔥 *ВНИМАНИЕ!* Высокая температура (%s) на датчике %s",
+	"alert.motion":           "👤 *Обнаружено движение* на датчике %s",
+	"alert.high_co2":         "⚠️ *ВНИМАНИЕ!* Высокий уровень CO₂ (%s) на датчике %s",
+
+	"value.temperature":     "🌡 Температура: *%s*",
+	"value.motion_detected": "🔴 *Обнаружено движение*",
+	"value.motion_clear":    "🟢 *Движение не обнаружено*",
+	"value.air_quality":     "💨 CO₂: *%s*\n💨 NH₃: *%s*",
+
+	"sensor.temperature": "Температура",
+	"sensor.motion":      "Движение",
+	"sensor.air_quality": "Качество воздуха",
+
+	"unit.days":    "%d д",
+	"unit.hours":   "%d ч",
+	"unit.minutes": "%d мин",
+
+	"report.daily":            "Ежедневный отчет",
+	"report.weekly":           "Еженедельный отчет",
+	"report.header":           "📋 *%s*\n%s — %s\n\n",
+	"report.no_data":          "нет данных\n",
+	"report.temperature":      "🌡 *Температура:*\n",
+	"report.temperature_item": "• %s: мин %s, макс %s, сред %s\n",
+	"report.motion":           "\n👤 *Движение:*\n",
+	"report.motion_item":      "• %s: %s ч\n",
+	"report.peak_co2":         "\n💨 *Пиковый CO₂:* ",
+	"report.peak_co2_value":   "%s (%s, %s)\n",
+	"report.alerts":           "\n🔔 *Оповещения:* %d\n",
+	"report.alert_item":       "• %s: %d\n",
+
+	"rules.admin_only":     "Изменять правила оповещений могут только администраторы.",
+	"rules.usage":          "Формат: /setthreshold <датчик> [поле] <оператор> <значение> [for <длительность>]\nПримеры:\n/setthreshold temp_kitchen > 27 for 5m\n/setthreshold air_kitchen co2 > 1200 for 10m\n/setthreshold motion_hallway = true",
+	"rules.invalid":        "Некорректное правило: %s",
+	"rules.unknown_sensor": "Датчик %s не найден.",
+	"rules.created":        "✅ Правило #%d создано:\n%s",
+	"rules.updated":        "✅ Правило #%d обновлено:\n%s",
+	"rules.error":          "Ошибка при работе с правилами оповещений.",
+	"rules.empty":          "Правила оповещений не настроены. Добавьте правило командой /setthreshold.",
+	"rules.title":          "📏 Правила оповещений:\n\n",
+	"rules.item":           "#%d %s\n",
+	"rules.delete_usage":   "Укажите номер правила. Пример: /deleterule 3",
+	"rules.deleted":        "🗑 Правило #%d удалено.",
+	"rules.not_found":      "Правило #%d не найдено.",
+
+	"thresholds.title":               "📏 Пороги оповещений:\n\n",
+	"thresholds.item":                "• %s\n",
+	"thresholds.default_temperature": "• Остальные датчики температуры: > %s\n",
+	"thresholds.default_co2":         "• Остальные датчики CO₂: > %s\n",
+
+	"rule.describe":        "%s: %s %s %s",
+	"rule.for":             " в течение %s",
+	"rule.motion_detected": "%s: обнаружено движение",
+	"rule.motion_clear":    "%s: нет движения",
+	"rule.unknown_type":    "%s: тип датчика неизвестен, показаний еще нет",
+
+	"quantity.temperature": "температура",
+	"quantity.co2":         "CO₂",
+	"quantity.nh3":         "NH₃",
+
+	"alert.rule":       "🔔 *Сработало правило #%d:* %s\n%s",
+	"alert.automation": "🤖 *Автоматизация %s:* %s",
+}
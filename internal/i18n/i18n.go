@@ -0,0 +1,152 @@
+// Package i18n содержит каталоги сообщений бота и форматирование
+// чисел и дат с учетом языка пользователя
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+// Lang - код языка интерфейса
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+
+	// Default - язык по умолчанию
+	Default = Russian
+)
+
+var catalogs = map[Lang]map[string]string{
+	Russian: ru,
+	English: en,
+}
+
+// Supported возвращает список поддерживаемых языков
+func Supported() []Lang {
+	return []Lang{Russian, English}
+}
+
+// Parse разбирает код языка ("ru", "en")
+func Parse(s string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(s)))
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// FromLanguageCode выбирает язык по language_code из профиля Telegram
+// (например, "ru", "en-US"); для неизвестных языков используется английский
+func FromLanguageCode(code string) Lang {
+	if code == "" {
+		return Default
+	}
+
+	base := strings.ToLower(strings.SplitN(code, "-", 2)[0])
+	if lang, ok := Parse(base); ok {
+		return lang
+	}
+	return English
+}
+
+// Localizer переводит сообщения и форматирует значения для одного языка и
+// предпочтительных единиц измерения
+type Localizer struct {
+	lang  Lang
+	units units.Preference
+}
+
+// New создает локализатор; неизвестный язык заменяется языком по умолчанию
+func New(lang Lang) *Localizer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = Default
+	}
+	return &Localizer{lang: lang}
+}
+
+// Lang возвращает язык локализатора
+func (l *Localizer) Lang() Lang {
+	return l.lang
+}
+
+// WithUnits возвращает локализатор, который выводит величины в
+// предпочтительных единицах
+func (l *Localizer) WithUnits(p units.Preference) *Localizer {
+	return &Localizer{lang: l.lang, units: p}
+}
+
+// Units возвращает предпочтительные единицы
+func (l *Localizer) Units() units.Preference {
+	return l.units
+}
+
+// T возвращает перевод сообщения по ключу, подставляя аргументы как в fmt.Sprintf.
+// Если перевода нет, используется каталог по умолчанию, а затем сам ключ
+func (l *Localizer) T(key string, args ...interface{}) string {
+	template, ok := catalogs[l.lang][key]
+	if !ok {
+		template, ok = catalogs[Default][key]
+	}
+	if !ok {
+		template = key
+	}
+
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// Float форматирует число с заданным количеством знаков после запятой
+// и десятичным разделителем языка
+func (l *Localizer) Float(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if l.lang == Russian {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// Quantity форматирует величину, заданную в канонической единице unit, в
+// предпочтительной единице с ее обозначением; substance - газ для перевода
+// концентраций (co2, nh3)
+func (l *Localizer) Quantity(v float64, unit units.Unit, substance string) string {
+	to := l.units.For(unit)
+	converted, err := units.Convert(v, unit, to, substance)
+	if err != nil {
+		converted, to = v, unit
+	}
+	return to.Format(l.Float(converted, to.Precision()))
+}
+
+// DateTime форматирует дату и время
+func (l *Localizer) DateTime(t time.Time) string {
+	if l.lang == English {
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return t.Format("02.01.2006 15:04:05")
+}
+
+// ShortDateTime форматирует дату и время без года и секунд
+func (l *Localizer) ShortDateTime(t time.Time) string {
+	if l.lang == English {
+		return t.Format("Jan 2 15:04")
+	}
+	return t.Format("02.01 15:04")
+}
+
+// Period форматирует длительность периода в днях, часах или минутах
+func (l *Localizer) Period(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return l.T("unit.days", int(d/(24*time.Hour)))
+	case d >= time.Hour && d%time.Hour == 0:
+		return l.T("unit.hours", int(d/time.Hour))
+	default:
+		return l.T("unit.minutes", int(d.Round(time.Minute)/time.Minute))
+	}
+}
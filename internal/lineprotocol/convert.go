@@ -0,0 +1,111 @@
+package lineprotocol
+
+import (
+	"fmt"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Соответствие точек и показаний: измерение - тип датчика, тег SensorTag -
+// ID датчика, тег unit - единица измерения, остальные теги - метаданные.
+// Единственное поле value - скалярное показание, несколько полей - составное
+const (
+	SensorTag  = "sensor_id"
+	UnitTag    = "unit"
+	ValueField = "value"
+)
+
+// ToSensorData переводит точку в показание датчика; sensorTag - тег с ID
+// датчика (по умолчанию SensorTag)
+func ToSensorData(p Point, sensorTag string) (models.SensorData, error) {
+	if sensorTag == "" {
+		sensorTag = SensorTag
+	}
+
+	sensorID := p.Tags[sensorTag]
+	if sensorID == "" {
+		return models.SensorData{}, fmt.Errorf("point %s has no %s tag", p.Measurement, sensorTag)
+	}
+
+	data := models.SensorData{
+		SensorID:   sensorID,
+		SensorType: p.Measurement,
+		Timestamp:  p.Time,
+		Unit:       p.Tags[UnitTag],
+	}
+
+	if value, ok := p.Fields[ValueField]; ok && len(p.Fields) == 1 {
+		data.Value.Data = normalize(value)
+	} else {
+		fields := make(map[string]interface{}, len(p.Fields))
+		for key, value := range p.Fields {
+			fields[key] = normalize(value)
+		}
+		data.Value.Data = fields
+	}
+
+	metadata := make(map[string]interface{}, len(p.Tags))
+	for key, value := range p.Tags {
+		if key != sensorTag && key != UnitTag {
+			metadata[key] = value
+		}
+	}
+	if len(metadata) > 0 {
+		data.Metadata.Data = metadata
+	}
+
+	return data, nil
+}
+
+// FromSensorData переводит показание в точку. Метаданные не выгружаются:
+// теги с большим числом значений плохо сказываются на хранилищах Influx
+func FromSensorData(data models.SensorData) (Point, error) {
+	p := Point{
+		Measurement: data.SensorType,
+		Tags:        map[string]string{SensorTag: data.SensorID},
+		Fields:      make(map[string]interface{}),
+		Time:        data.Timestamp,
+	}
+	if data.Unit != "" {
+		p.Tags[UnitTag] = data.Unit
+	}
+
+	switch v := data.Value.Data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if !supported(value) {
+				return p, fmt.Errorf("field %s of sensor %s has unsupported value %T", key, data.SensorID, value)
+			}
+			p.Fields[key] = value
+		}
+	default:
+		if !supported(v) {
+			return p, fmt.Errorf("sensor %s has unsupported value %T", data.SensorID, v)
+		}
+		p.Fields[ValueField] = v
+	}
+
+	if len(p.Fields) == 0 {
+		return p, fmt.Errorf("sensor %s has an empty value", data.SensorID)
+	}
+	return p, nil
+}
+
+// normalize приводит целые числа к float64, как у показаний из JSON
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	}
+	return value
+}
+
+func supported(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, int, int64, uint64, bool, string:
+		return true
+	}
+	return false
+}
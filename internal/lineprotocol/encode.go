@@ -0,0 +1,102 @@
+package lineprotocol
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Экранирование имен и строковых значений при записи
+var (
+	measurementEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, " ", `\ `, "\n", " ")
+	keyEscaper         = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `, "\n", " ")
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// Encode записывает точку одной строкой; теги и поля упорядочиваются по
+// имени, время - в наносекундах. Время не пишется, если оно нулевое
+func Encode(w io.Writer, p Point) error {
+	if p.Measurement == "" {
+		return fmt.Errorf("missing measurement")
+	}
+	if len(p.Fields) == 0 {
+		return fmt.Errorf("point %s has no fields", p.Measurement)
+	}
+
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(p.Measurement))
+
+	for _, key := range sortedKeys(p.Tags) {
+		if p.Tags[key] == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(keyEscaper.Replace(key))
+		b.WriteByte('=')
+		b.WriteString(keyEscaper.Replace(p.Tags[key]))
+	}
+
+	fields := make([]string, 0, len(p.Fields))
+	for key := range p.Fields {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	for i, key := range fields {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(keyEscaper.Replace(key))
+		b.WriteByte('=')
+		if err := writeValue(&b, p.Fields[key]); err != nil {
+			return fmt.Errorf("field %s: %w", key, err)
+		}
+	}
+
+	if !p.Time.IsZero() {
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(p.Time.UnixNano(), 10))
+	}
+	b.WriteByte('\n')
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeValue записывает значение поля с суффиксом типа
+func writeValue(b *strings.Builder, value interface{}) error {
+	switch v := value.(type) {
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		b.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case int:
+		b.WriteString(strconv.Itoa(v) + "i")
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10) + "i")
+	case uint64:
+		b.WriteString(strconv.FormatUint(v, 10) + "u")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case string:
+		b.WriteByte('"')
+		b.WriteString(stringEscaper.Replace(v))
+		b.WriteByte('"')
+	default:
+		return fmt.Errorf("unsupported value %T", value)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,236 @@
+// Package lineprotocol читает и записывает точки в текстовом формате InfluxDB
+// (line protocol), чтобы принимать данные от Telegraf и отдавать историю в
+// экосистему Influx:
+//
+//	measurement,tag=value field=1.5,other="text" 1700000000000000000
+package lineprotocol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point - одна точка: измерение, теги, поля и время
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{} // float64, int64, uint64, string или bool
+	Time        time.Time              // Нулевое, если время в строке не указано
+}
+
+// ParseError - ошибка разбора строки
+type ParseError struct {
+	Line int
+	Err  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Precision возвращает длительность единицы времени: n/ns, u/us, ms, s, m, h.
+// Пустая строка означает наносекунды
+func Precision(value string) (time.Duration, error) {
+	switch value {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u", "us", "µ":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return 0, fmt.Errorf("unknown precision %q", value)
+}
+
+// Parse читает все точки из r. Ошибочные строки не прерывают разбор: точки
+// из корректных строк возвращаются вместе со списком ошибок
+func Parse(r io.Reader, precision time.Duration) ([]Point, []error) {
+	var (
+		points []Point
+		errs   []error
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		point, err := ParseLine(line, precision)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: n, Err: err.Error()})
+			continue
+		}
+		points = append(points, point)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return points, errs
+}
+
+// ParseLine разбирает одну строку
+func ParseLine(line string, precision time.Duration) (Point, error) {
+	point := Point{Tags: make(map[string]string), Fields: make(map[string]interface{})}
+
+	// Измерение: до неэкранированной запятой или пробела
+	measurement, i := scan(line, 0, ", ", measurementEscapes)
+	if measurement == "" {
+		return point, fmt.Errorf("missing measurement")
+	}
+	point.Measurement = measurement
+
+	// Теги: ,key=value
+	for i < len(line) && line[i] == ',' {
+		key, next := scan(line, i+1, ",= ", keyEscapes)
+		if next >= len(line) || line[next] != '=' || key == "" {
+			return point, fmt.Errorf("invalid tag")
+		}
+		value, end := scan(line, next+1, ", ", keyEscapes)
+		if value == "" {
+			return point, fmt.Errorf("missing value of tag %s", key)
+		}
+		point.Tags[key] = value
+		i = end
+	}
+
+	// Поля: key=value[,key=value]
+	i = skipSpaces(line, i)
+	for {
+		key, next := scan(line, i, ",= ", keyEscapes)
+		if next >= len(line) || line[next] != '=' || key == "" {
+			return point, fmt.Errorf("invalid field")
+		}
+
+		value, end, err := fieldValue(line, next+1)
+		if err != nil {
+			return point, fmt.Errorf("field %s: %w", key, err)
+		}
+		point.Fields[key] = value
+
+		i = end
+		if i >= len(line) || line[i] != ',' {
+			break
+		}
+		i++
+	}
+	if len(point.Fields) == 0 {
+		return point, fmt.Errorf("missing fields")
+	}
+
+	// Время в единицах precision
+	if rest := strings.TrimSpace(line[i:]); rest != "" {
+		ts, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			return point, fmt.Errorf("invalid timestamp %q", rest)
+		}
+		// Время в наносекундах должно поместиться в int64
+		if p := int64(precision); ts > math.MaxInt64/p || ts < math.MinInt64/p {
+			return point, fmt.Errorf("timestamp %d is out of range", ts)
+		}
+		point.Time = time.Unix(0, ts*int64(precision)).UTC()
+	}
+
+	return point, nil
+}
+
+// Символы, которые экранируются обратной косой чертой: в имени измерения -
+// запятая и пробел, в тегах и именах полей - еще и знак равенства
+const (
+	measurementEscapes = `\, `
+	keyEscapes         = `\,= `
+)
+
+// scan читает токен с позиции i до первого неэкранированного символа из
+// stops, снимая экранирование символов из escapes
+func scan(line string, i int, stops, escapes string) (string, int) {
+	var buf strings.Builder
+	for i < len(line) {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && strings.IndexByte(escapes, line[i+1]) >= 0 {
+			buf.WriteByte(line[i+1])
+			i += 2
+			continue
+		}
+		if strings.IndexByte(stops, c) >= 0 {
+			break
+		}
+		buf.WriteByte(c)
+		i++
+	}
+	return buf.String(), i
+}
+
+// fieldValue разбирает значение поля: "строка", 1i, 1u, t/false, 1.5
+func fieldValue(line string, i int) (interface{}, int, error) {
+	if i < len(line) && line[i] == '"' {
+		var buf strings.Builder
+		for j := i + 1; j < len(line); j++ {
+			switch c := line[j]; {
+			case c == '\\' && j+1 < len(line) && (line[j+1] == '"' || line[j+1] == '\\'):
+				buf.WriteByte(line[j+1])
+				j++
+			case c == '"':
+				return buf.String(), j + 1, nil
+			default:
+				buf.WriteByte(c)
+			}
+		}
+		return nil, 0, fmt.Errorf("unterminated string")
+	}
+
+	raw, end := scan(line, i, ", ", "")
+	if raw == "" {
+		return nil, 0, fmt.Errorf("missing value")
+	}
+
+	switch last := raw[len(raw)-1]; {
+	case last == 'i':
+		v, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid integer %q", raw)
+		}
+		return v, end, nil
+	case last == 'u':
+		v, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		return v, end, nil
+	}
+
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return true, end, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, end, nil
+	}
+
+	// В line protocol нет литералов NaN и Inf, а ParseFloat их понимает
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, 0, fmt.Errorf("invalid value %q", raw)
+	}
+	return v, end, nil
+}
+
+func skipSpaces(line string, i int) int {
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return i
+}
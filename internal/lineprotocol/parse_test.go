@@ -0,0 +1,200 @@
+package lineprotocol
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	at := time.Date(2026, 10, 13, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		line    string
+		want    Point
+		wantErr bool
+	}{
+		{
+			name: "escaped names",
+			line: `air\ quality\,v2,sensor\ id=air\,kit\=chen,room=kitchen co\=2=650,nh\ 3=12 ` + strconv.FormatInt(at.UnixNano(), 10),
+			want: Point{
+				Measurement: "air quality,v2",
+				Tags:        map[string]string{"sensor id": "air,kit=chen", "room": "kitchen"},
+				Fields:      map[string]interface{}{"co=2": 650.0, "nh 3": 12.0},
+				Time:        at,
+			},
+		},
+		{
+			name: "escaped backslash",
+			line: `path\\,dir=C:\\temp v=1`,
+			want: Point{
+				Measurement: `path\`,
+				Tags:        map[string]string{"dir": `C:\temp`},
+				Fields:      map[string]interface{}{"v": 1.0},
+			},
+		},
+		{
+			name: "quoted string",
+			line: `status,sensor_id=door msg="said \"hi\", a=b\\c" `,
+			want: Point{
+				Measurement: "status",
+				Tags:        map[string]string{"sensor_id": "door"},
+				Fields:      map[string]interface{}{"msg": `said "hi", a=b\c`},
+			},
+		},
+		{
+			name: "typed fields",
+			line: "m i=-5i,u=5u,t=t,T=TRUE,f=false,x=1.5e1",
+			want: Point{
+				Measurement: "m",
+				Tags:        map[string]string{},
+				Fields: map[string]interface{}{
+					"i": int64(-5), "u": uint64(5), "t": true, "T": true, "f": false, "x": 15.0,
+				},
+			},
+		},
+		{
+			name: "spaces before fields",
+			line: "m,a=1   v=2",
+			want: Point{
+				Measurement: "m",
+				Tags:        map[string]string{"a": "1"},
+				Fields:      map[string]interface{}{"v": 2.0},
+			},
+		},
+		{name: "empty", line: "", wantErr: true},
+		{name: "no fields", line: "m", wantErr: true},
+		{name: "field without value", line: "m v=", wantErr: true},
+		{name: "field without equals", line: "m v", wantErr: true},
+		{name: "empty tag key", line: "m,=1 v=1", wantErr: true},
+		{name: "empty tag value", line: "m,t= v=1", wantErr: true},
+		{name: "missing measurement", line: ",t=1 v=1", wantErr: true},
+		{name: "unterminated string", line: `m s="open`, wantErr: true},
+		{name: "bad float", line: "m v=1x", wantErr: true},
+		{name: "NaN", line: "m v=NaN", wantErr: true},
+		{name: "bad integer", line: "m v=1.5i", wantErr: true},
+		{name: "negative unsigned", line: "m v=-1u", wantErr: true},
+		{name: "trailing comma", line: "m v=1,", wantErr: true},
+		{name: "bad timestamp", line: "m v=1 soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLine(tt.line, time.Nanosecond)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLinePrecision(t *testing.T) {
+	// Время кратно часу, чтобы его можно было записать в любых единицах
+	at := time.Date(2026, 10, 13, 18, 0, 0, 0, time.UTC)
+
+	for _, precision := range []string{"", "n", "ns", "u", "us", "µ", "ms", "s", "m", "h"} {
+		t.Run(precision, func(t *testing.T) {
+			unit, err := Precision(precision)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			line := "m v=1 " + strconv.FormatInt(at.UnixNano()/int64(unit), 10)
+			got, err := ParseLine(line, unit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Time.Equal(at) || got.Time.Location() != time.UTC {
+				t.Errorf("time = %v, want %v", got.Time, at)
+			}
+
+			// Время, которое не помещается в наносекунды int64, отклоняется
+			if unit > time.Nanosecond {
+				overflow := "m v=1 " + strconv.FormatInt(math.MaxInt64/int64(unit)+1, 10)
+				if got, err := ParseLine(overflow, unit); err == nil {
+					t.Errorf("%q: got %v, want error", overflow, got.Time)
+				}
+			}
+		})
+	}
+
+	if _, err := Precision("d"); err == nil {
+		t.Error("unknown precision accepted")
+	}
+}
+
+func TestParse(t *testing.T) {
+	body := strings.Join([]string{
+		"# comment",
+		"temperature,sensor_id=temp_hall value=21.5",
+		"",
+		"temperature,sensor_id=temp_hall value=",
+		"  humidity,sensor_id=hum_hall value=48i  ",
+	}, "\n")
+
+	points, errs := Parse(strings.NewReader(body), time.Nanosecond)
+	if len(points) != 2 {
+		t.Fatalf("got %d points, want 2", len(points))
+	}
+	if points[0].Measurement != "temperature" || points[1].Measurement != "humidity" {
+		t.Errorf("measurements = %s, %s", points[0].Measurement, points[1].Measurement)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("got errors %v, want one", errs)
+	}
+	if perr, ok := errs[0].(*ParseError); !ok || perr.Line != 4 {
+		t.Errorf("error = %v, want a parse error on line 4", errs[0])
+	}
+}
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	points := []Point{
+		{
+			Measurement: "air quality,v2",
+			Tags:        map[string]string{"sensor_id": "air,kit=chen", "note": `C:\temp dir`},
+			Fields: map[string]interface{}{
+				"co2":    650.25,
+				"count":  int64(-3),
+				"total":  uint64(7),
+				"ok":     true,
+				"status": `said "hi", a=b\c`,
+				"a b=c":  0.5,
+			},
+			Time: time.Date(2026, 10, 13, 18, 0, 0, 123456789, time.UTC),
+		},
+		{
+			Measurement: "motion",
+			Tags:        map[string]string{"sensor_id": "motion_hall"},
+			Fields:      map[string]interface{}{"value": false},
+		},
+	}
+
+	for _, want := range points {
+		var buf bytes.Buffer
+		if err := Encode(&buf, want); err != nil {
+			t.Fatalf("encode %s: %v", want.Measurement, err)
+		}
+
+		got, errs := Parse(&buf, time.Nanosecond)
+		if len(errs) > 0 || len(got) != 1 {
+			t.Fatalf("parse %q: points %v, errors %v", buf.String(), got, errs)
+		}
+		if !reflect.DeepEqual(got[0], want) {
+			t.Errorf("round trip of %s:\n got %+v\nwant %+v", want.Measurement, got[0], want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -23,6 +24,54 @@ type Reading struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+// Actuator представляет интерфейс исполнительного устройства
+type Actuator interface {
+	ID() string
+	Type() string
+	Capabilities() []string
+	Execute(cmd Command) (ActuatorState, error)
+	State() ActuatorState
+}
+
+// Command представляет команду для исполнительного устройства
+type Command struct {
+	Name  string      `json:"command"`         // Название команды (turn_on, set_brightness, ...)
+	Value interface{} `json:"value,omitempty"` // Аргумент команды
+}
+
+// Команды исполнительных устройств
+const (
+	CommandTurnOn        = "turn_on"
+	CommandTurnOff       = "turn_off"
+	CommandToggle        = "toggle"
+	CommandSetBrightness = "set_brightness"
+	CommandSetSetpoint   = "set_setpoint"
+	CommandSetSpeed      = "set_speed"
+	CommandSetMode       = "set_mode"
+	CommandSetPower      = "set_power"
+)
+
+// ActuatorState представляет состояние исполнительного устройства
+type ActuatorState map[string]interface{}
+
+// DeviceEvent представляет команду или изменение состояния устройства для сохранения в БД
+type DeviceEvent struct {
+	ID         int64         `json:"id" db:"id"`                     // Уникальный идентификатор записи
+	DeviceID   string        `json:"deviceId" db:"device_id"`        // Идентификатор устройства
+	DeviceType string        `json:"deviceType" db:"device_type"`    // Тип устройства
+	EventType  string        `json:"eventType" db:"event_type"`      // Тип события: command или state
+	Command    *Command      `json:"command,omitempty" db:"command"` // Команда (для событий command)
+	State      ActuatorState `json:"state,omitempty" db:"state"`     // Состояние (для событий state)
+	Source     string        `json:"source" db:"source"`             // Источник команды (api, automation, ...)
+	CreatedAt  time.Time     `json:"createdAt" db:"created_at"`      // Время события
+}
+
+// Типы событий устройств
+const (
+	DeviceEventCommand = "command"
+	DeviceEventState   = "state"
+)
+
 // SensorConfig представляет интерфейс для конфигурации датчика
 type SensorConfig interface {
 	Validate() error
@@ -40,6 +89,16 @@ type SensorData struct {
 	CreatedAt  time.Time   `json:"createdAt" db:"created_at"`        // Время создания записи
 }
 
+// SensorInfo представляет запись реестра датчиков
+type SensorInfo struct {
+	ID        string    `json:"id" db:"id"`                       // Идентификатор датчика
+	Type      string    `json:"type" db:"type"`                   // Тип датчика
+	Name      string    `json:"name,omitempty" db:"name"`         // Название
+	Location  string    `json:"location,omitempty" db:"location"` // Расположение
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`        // Время регистрации
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`        // Время последнего изменения
+}
+
 // SensorValue - JSON-тип для значения датчика
 type SensorValue struct {
 	Data interface{} `json:"data"`
@@ -108,7 +167,70 @@ func (m *Metadata) Scan(value interface{}) error {
 	return nil
 }
 
+// Alert представляет сработавшее оповещение
+type Alert struct {
+	ID        int64     `json:"id" db:"id"`                // Уникальный идентификатор записи
+	SensorID  string    `json:"sensorId" db:"sensor_id"`   // Идентификатор датчика
+	AlertType string    `json:"alertType" db:"alert_type"` // Тип оповещения
+	Message   string    `json:"message" db:"message"`      // Текст оповещения
+	CreatedAt time.Time `json:"createdAt" db:"created_at"` // Время срабатывания
+}
+
+// AutomationRun представляет запись о выполнении правила автоматизации
+type AutomationRun struct {
+	ID        int64          `json:"id" db:"id"`                 // Уникальный идентификатор записи
+	RuleID    string         `json:"ruleId" db:"rule_id"`        // Идентификатор правила
+	Trigger   string         `json:"trigger" db:"trigger"`       // Описание сработавшего триггера
+	DryRun    bool           `json:"dryRun" db:"dry_run"`        // Действия не выполнялись (пробный режим)
+	Success   bool           `json:"success" db:"success"`       // Все действия выполнены успешно
+	Actions   []ActionResult `json:"actions" db:"actions"`       // Результаты действий
+	Error     string         `json:"error,omitempty" db:"error"` // Ошибка выполнения
+	CreatedAt time.Time      `json:"createdAt" db:"created_at"`  // Время выполнения
+}
+
+// ActionResult представляет результат одного действия автоматизации
+type ActionResult struct {
+	Type    string      `json:"type"`              // Тип действия (device, notify)
+	Target  string      `json:"target"`            // Устройство или текст уведомления
+	Command string      `json:"command,omitempty"` // Команда устройства
+	Value   interface{} `json:"value,omitempty"`   // Аргумент команды
+	Error   string      `json:"error,omitempty"`   // Ошибка выполнения действия
+}
+
+// AlertRule представляет правило оповещения: "sensor_id [field] op threshold [for duration]"
+type AlertRule struct {
+	ID        int64         `json:"id" db:"id"`                // Уникальный идентификатор правила
+	SensorID  string        `json:"sensorId" db:"sensor_id"`   // Идентификатор датчика
+	Field     string        `json:"field" db:"field"`          // Поле значения (например, co2), пусто для скалярных значений
+	Operator  string        `json:"operator" db:"operator"`    // Оператор сравнения: >, >=, <, <=, =
+	Threshold float64       `json:"threshold" db:"threshold"`  // Пороговое значение
+	Duration  time.Duration `json:"duration" db:"duration"`    // Сколько условие должно выполняться до срабатывания
+	CreatedBy int64         `json:"createdBy" db:"created_by"` // Пользователь, создавший правило
+	CreatedAt time.Time     `json:"createdAt" db:"created_at"` // Время создания
+	UpdatedAt time.Time     `json:"updatedAt" db:"updated_at"` // Время последнего изменения
+}
+
+// UserPreferences представляет настройки пользователя бота
+type UserPreferences struct {
+	UserID    int64     `json:"userId" db:"user_id"`       // Идентификатор пользователя Telegram
+	Language  string    `json:"language" db:"language"`    // Язык интерфейса
+	Units     string    `json:"units" db:"units"`          // Предпочтительные единицы измерения, пусто - канонические
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"` // Время последнего изменения
+}
+
+// SensorLocation возвращает расположение датчика по его ID
+// (например, temp_living_room => living_room)
+func SensorLocation(sensorID string) string {
+	parts := strings.Split(sensorID, "_")
+	if len(parts) > 1 {
+		return strings.Join(parts[1:], "_")
+	}
+	return "unknown"
+}
+
 // Errors
 var (
-	ErrNotReady = errors.New("sensor is not ready to read")
+	ErrNotReady            = errors.New("sensor is not ready to read")
+	ErrUnsupportedCommand  = errors.New("command is not supported by the device")
+	ErrInvalidCommandValue = errors.New("invalid command value")
 )
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ValueKind - вид значения показания
+type ValueKind string
+
+const (
+	KindNumber ValueKind = "number" // Число в единице измерения показания
+	KindBool   ValueKind = "bool"   // Логическое значение
+	KindObject ValueKind = "object" // Измерение из нескольких числовых полей
+)
+
+// Value - типизированное значение показания. В JSON и в БД значение хранится
+// как число, логическое значение или объект с числовыми полями
+type Value struct {
+	Kind   ValueKind
+	Number float64            // KindNumber
+	Bool   bool               // KindBool
+	Fields map[string]float64 // KindObject
+}
+
+// NumberValue создает числовое значение
+func NumberValue(v float64) Value {
+	return Value{Kind: KindNumber, Number: v}
+}
+
+// BoolValue создает логическое значение
+func BoolValue(v bool) Value {
+	return Value{Kind: KindBool, Bool: v}
+}
+
+// ObjectValue создает составное значение
+func ObjectValue(fields map[string]float64) Value {
+	return Value{Kind: KindObject, Fields: fields}
+}
+
+// DecodeValue приводит значение показания (из JSON, БД или датчика) к
+// типизированному. Целые числа приводятся к float64
+func DecodeValue(data interface{}) (Value, error) {
+	if data == nil {
+		return Value{}, fmt.Errorf("value is required")
+	}
+	if v, ok := data.(bool); ok {
+		return BoolValue(v), nil
+	}
+	if v, ok := toFloat(data); ok {
+		if !finite(v) {
+			return Value{}, fmt.Errorf("value must be a finite number, got %v", v)
+		}
+		return NumberValue(v), nil
+	}
+
+	switch v := data.(type) {
+	case map[string]float64:
+		for key, f := range v {
+			if !finite(f) {
+				return Value{}, fmt.Errorf("field %s must be a finite number, got %v", key, f)
+			}
+		}
+		return ObjectValue(v), nil
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return Value{}, fmt.Errorf("value has no fields")
+		}
+		fields := make(map[string]float64, len(v))
+		for key, raw := range v {
+			f, ok := toFloat(raw)
+			if !ok {
+				return Value{}, fmt.Errorf("field %s must be a number, got %s", key, describe(raw))
+			}
+			if !finite(f) {
+				return Value{}, fmt.Errorf("field %s must be a finite number, got %v", key, f)
+			}
+			fields[key] = f
+		}
+		return ObjectValue(fields), nil
+	}
+
+	return Value{}, fmt.Errorf("value must be a number, a boolean or an object, got %s", describe(data))
+}
+
+// Decode разбирает значение показания
+func (sv SensorValue) Decode() (Value, error) {
+	return DecodeValue(sv.Data)
+}
+
+// Data возвращает значение в виде для JSON и БД
+func (v Value) Data() interface{} {
+	switch v.Kind {
+	case KindNumber:
+		return v.Number
+	case KindBool:
+		return v.Bool
+	case KindObject:
+		fields := make(map[string]interface{}, len(v.Fields))
+		for key, f := range v.Fields {
+			fields[key] = f
+		}
+		return fields
+	}
+	return nil
+}
+
+// Float возвращает скалярное значение как число: логическое значение - 1 или 0
+func (v Value) Float() (float64, bool) {
+	switch v.Kind {
+	case KindNumber:
+		return v.Number, true
+	case KindBool:
+		if v.Bool {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// Field возвращает поле составного значения; пустое имя означает само
+// скалярное значение
+func (v Value) Field(name string) (float64, bool) {
+	if name == "" {
+		return v.Float()
+	}
+	f, ok := v.Fields[name]
+	return f, ok
+}
+
+// FieldNames возвращает имена полей составного значения по порядку
+func (v Value) FieldNames() []string {
+	names := make([]string, 0, len(v.Fields))
+	for name := range v.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (v Value) String() string {
+	switch v.Kind {
+	case KindNumber:
+		return fmt.Sprintf("%g", v.Number)
+	case KindBool:
+		return fmt.Sprintf("%t", v.Bool)
+	case KindObject:
+		return fmt.Sprintf("%v", v.Fields)
+	}
+	return "<nil>"
+}
+
+func toFloat(data interface{}) (float64, bool) {
+	switch v := data.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// finite сообщает, что число не NaN и не бесконечность: такие значения нельзя
+// сохранить в JSON
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// describe называет вид значения в сообщении об ошибке
+func describe(data interface{}) string {
+	switch data.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	if _, ok := toFloat(data); ok {
+		return "a number"
+	}
+	return fmt.Sprintf("%T", data)
+}
@@ -0,0 +1,215 @@
+// Package processing обрабатывает показания датчиков перед сохранением:
+// калибрует, ограничивает, округляет, сглаживает и отбраковывает выбросы.
+// Конвейер шагов задается для каждого датчика (и поля составного значения)
+// в конфигурации; исходное значение сохраняется в метаданных показания
+package processing
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+)
+
+// RawValueKey - ключ метаданных с исходным значением показания
+const RawValueKey = "raw_value"
+
+// ErrRejected - показание отбраковано как выброс
+var ErrRejected = errors.New("reading rejected as an outlier")
+
+// pipeline - шаги обработки одного значения датчика
+type pipeline struct {
+	field string
+	steps []step
+}
+
+// Processor применяет конвейеры обработки к показаниям. Безопасен для
+// одновременного использования: шаги сглаживания хранят состояние
+type Processor struct {
+	mu        sync.Mutex
+	pipelines map[string][]*pipeline
+}
+
+// New создает обработчик по конфигурации. sensorTypes - типы датчиков из
+// конфигурации (ID -> тип): конвейер для датчика с логическим значением
+// отклоняется при запуске, а не на каждом показании
+func New(cfgs []config.ProcessingConfig, sensorTypes map[string]string) (*Processor, error) {
+	p := &Processor{pipelines: make(map[string][]*pipeline)}
+
+	for _, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		if name, ok := sensorTypes[cfg.SensorID]; ok {
+			if t, ok := sensortype.Lookup(name); ok && t.Value.Kind == models.KindBool {
+				return nil, fmt.Errorf("%s: sensor type %s has boolean values that cannot be processed", cfg.SensorID, name)
+			}
+		}
+
+		pl := &pipeline{field: cfg.Field}
+		for i, stepCfg := range cfg.Steps {
+			s, err := newStep(stepCfg)
+			if err != nil {
+				return nil, fmt.Errorf("%s: step %d: %w", cfg.SensorID, i+1, err)
+			}
+			pl.steps = append(pl.steps, s)
+		}
+		p.pipelines[cfg.SensorID] = append(p.pipelines[cfg.SensorID], pl)
+	}
+
+	return p, nil
+}
+
+// Len возвращает число датчиков с конвейером обработки
+func (p *Processor) Len() int {
+	return len(p.pipelines)
+}
+
+// Process применяет конвейеры датчика к показанию. Показания датчиков без
+// конвейера возвращаются без изменений. Отбракованное показание возвращает
+// ошибку ErrRejected
+func (p *Processor) Process(data models.SensorData) (models.SensorData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.process(data, func(s step) step { return s })
+}
+
+// Batch обрабатывает пакет показаний, который сохраняется целиком или не
+// сохраняется вовсе. Показания обрабатываются по порядку на копиях шагов со
+// состоянием: каждое видит сглаживание после предыдущих, а состояние
+// обработчика меняется только в Commit, после сохранения пакета
+type Batch struct {
+	p      *Processor
+	copies map[statefulStep]statefulStep // Шаг обработчика -> рабочая копия
+}
+
+// Batch начинает обработку пакета показаний
+func (p *Processor) Batch() *Batch {
+	return &Batch{p: p, copies: make(map[statefulStep]statefulStep)}
+}
+
+// Process обрабатывает показание пакета так же, как Processor.Process
+func (b *Batch) Process(data models.SensorData) (models.SensorData, error) {
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+	return b.p.process(data, func(s step) step {
+		st, ok := s.(statefulStep)
+		if !ok {
+			return s
+		}
+		c, ok := b.copies[st]
+		if !ok {
+			c = st.clone()
+			b.copies[st] = c
+		}
+		return c
+	})
+}
+
+// Commit переносит состояние шагов, накопленное пакетом, в обработчик.
+// Если пакет не сохранен, Commit не вызывается и состояние не меняется
+func (b *Batch) Commit() {
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+
+	for _, pipelines := range b.p.pipelines {
+		for _, pl := range pipelines {
+			for i, s := range pl.steps {
+				if st, ok := s.(statefulStep); ok {
+					if c, ok := b.copies[st]; ok {
+						pl.steps[i] = c
+					}
+				}
+			}
+		}
+	}
+	b.copies = make(map[statefulStep]statefulStep)
+}
+
+// process применяет конвейеры к показанию; resolve выбирает экземпляр шага,
+// состояние которого используется и обновляется. Вызывается под p.mu
+func (p *Processor) process(data models.SensorData, resolve func(step) step) (models.SensorData, error) {
+	pipelines := p.pipelines[data.SensorID]
+	if len(pipelines) == 0 {
+		return data, nil
+	}
+
+	value, err := data.Value.Decode()
+	if err != nil {
+		return data, fmt.Errorf("sensor %s: %w", data.SensorID, err)
+	}
+	if value.Kind == models.KindBool {
+		return data, fmt.Errorf("sensor %s: boolean values cannot be processed", data.SensorID)
+	}
+
+	processed := value
+	if value.Kind == models.KindObject {
+		fields := make(map[string]float64, len(value.Fields))
+		for name, f := range value.Fields {
+			fields[name] = f
+		}
+		processed = models.ObjectValue(fields)
+	}
+
+	// Состояние шагов меняется, только когда показание приняли все конвейеры:
+	// иначе среднее одного поля впитало бы показание, отбракованное по другому.
+	// Поэтому сначала каждый шаг вычисляется, а входные значения запоминаются
+	inputs := make([][]float64, len(pipelines))
+	rejected := false
+	for i, pl := range pipelines {
+		v, ok := value.Field(pl.field)
+		if !ok || (pl.field == "" && value.Kind != models.KindNumber) {
+			return data, fmt.Errorf("sensor %s has no numeric field %q to process", data.SensorID, pl.field)
+		}
+
+		inputs[i] = make([]float64, 0, len(pl.steps))
+		for _, s := range pl.steps {
+			inputs[i] = append(inputs[i], v)
+			if v, err = resolve(s).apply(v); err != nil {
+				break
+			}
+		}
+		if errors.Is(err, ErrRejected) {
+			// Шаг отбраковки засчитывает отказ сразу, а остальные конвейеры
+			// проверяются дальше, чтобы их отбраковка тоже была учтена
+			if o, ok := resolve(pl.steps[len(inputs[i])-1]).(*outlier); ok {
+				o.reject()
+			}
+			rejected = true
+			continue
+		}
+		if err != nil {
+			return data, fmt.Errorf("sensor %s: %w", data.SensorID, err)
+		}
+
+		if pl.field == "" {
+			processed.Number = v
+		} else {
+			processed.Fields[pl.field] = v
+		}
+	}
+	if rejected {
+		return data, fmt.Errorf("sensor %s: %w", data.SensorID, ErrRejected)
+	}
+
+	for i, pl := range pipelines {
+		for j, s := range pl.steps {
+			if st, ok := resolve(s).(statefulStep); ok {
+				st.commit(inputs[i][j])
+			}
+		}
+	}
+
+	metadata := make(map[string]interface{}, len(data.Metadata.Data)+1)
+	for key, v := range data.Metadata.Data {
+		metadata[key] = v
+	}
+	metadata[RawValueKey] = value.Data()
+
+	data.Value.Data = processed.Data()
+	data.Metadata.Data = metadata
+	return data, nil
+}
@@ -0,0 +1,203 @@
+package processing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+)
+
+// defaultOutlierWindow - окно отбраковки выбросов по умолчанию
+const defaultOutlierWindow = 5
+
+// step - шаг обработки значения. apply не меняет состояние шага: показание
+// может отбраковать другой шаг или конвейер
+type step interface {
+	apply(v float64) (float64, error)
+}
+
+// statefulStep - шаг сглаживания или отбраковки, который помнит прошлые
+// значения. commit запоминает входное значение шага, когда показание принято
+// всеми конвейерами датчика; clone возвращает независимую копию шага
+type statefulStep interface {
+	step
+	commit(v float64)
+	clone() statefulStep
+}
+
+type stepFunc func(v float64) (float64, error)
+
+func (f stepFunc) apply(v float64) (float64, error) {
+	return f(v)
+}
+
+// newStep создает шаг по проверенной конфигурации
+func newStep(c config.ProcessingStepConfig) (step, error) {
+	switch c.Type {
+	case "offset":
+		return stepFunc(func(v float64) (float64, error) {
+			return v + c.Offset, nil
+		}), nil
+
+	case "linear":
+		return stepFunc(func(v float64) (float64, error) {
+			return v*c.Scale + c.Offset, nil
+		}), nil
+
+	case "two_point":
+		// Прямая через точки (raw[0], reference[0]) и (raw[1], reference[1])
+		slope := (c.Reference[1] - c.Reference[0]) / (c.Raw[1] - c.Raw[0])
+		raw0, ref0 := c.Raw[0], c.Reference[0]
+		return stepFunc(func(v float64) (float64, error) {
+			return ref0 + (v-raw0)*slope, nil
+		}), nil
+
+	case "clamp":
+		return stepFunc(func(v float64) (float64, error) {
+			if c.Min != nil && v < *c.Min {
+				v = *c.Min
+			}
+			if c.Max != nil && v > *c.Max {
+				v = *c.Max
+			}
+			return v, nil
+		}), nil
+
+	case "round":
+		scale := math.Pow(10, float64(c.Digits))
+		return stepFunc(func(v float64) (float64, error) {
+			return math.Round(v*scale) / scale, nil
+		}), nil
+
+	case "moving_average":
+		return &movingAverage{window: c.Window}, nil
+
+	case "ema":
+		return &ema{alpha: c.Alpha}, nil
+
+	case "outlier":
+		window := c.Window
+		if window == 0 {
+			window = defaultOutlierWindow
+		}
+		return &outlier{window: window, maxDelta: c.MaxDelta}, nil
+	}
+
+	return nil, fmt.Errorf("unknown step type %q", c.Type)
+}
+
+// movingAverage - среднее последних window значений
+type movingAverage struct {
+	window int
+	values []float64
+}
+
+func (m *movingAverage) apply(v float64) (float64, error) {
+	values := m.values
+	if len(values) == m.window {
+		values = values[1:]
+	}
+
+	sum := v
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values)+1), nil
+}
+
+func (m *movingAverage) commit(v float64) {
+	m.values = append(m.values, v)
+	if len(m.values) > m.window {
+		m.values = m.values[1:]
+	}
+}
+
+func (m *movingAverage) clone() statefulStep {
+	c := *m
+	c.values = append([]float64(nil), m.values...)
+	return &c
+}
+
+// ema - экспоненциальное скользящее среднее; первое значение берется как есть
+type ema struct {
+	alpha   float64
+	value   float64
+	started bool
+}
+
+func (e *ema) apply(v float64) (float64, error) {
+	if !e.started {
+		return v, nil
+	}
+	return e.alpha*v + (1-e.alpha)*e.value, nil
+}
+
+func (e *ema) commit(v float64) {
+	e.value, _ = e.apply(v)
+	e.started = true
+}
+
+func (e *ema) clone() statefulStep {
+	c := *e
+	return &c
+}
+
+// outlier отбраковывает значение, которое отличается от медианы последних
+// принятых значений больше чем на maxDelta. Пока значений меньше трех,
+// принимается все. После window отбракованных подряд значений считается, что
+// уровень действительно изменился: история начинается заново
+type outlier struct {
+	window   int
+	maxDelta float64
+	values   []float64
+	rejected int
+}
+
+func (o *outlier) apply(v float64) (float64, error) {
+	if o.deviates(v) && o.rejected+1 < o.window {
+		return v, ErrRejected
+	}
+	return v, nil
+}
+
+// reject засчитывает отбракованное значение
+func (o *outlier) reject() {
+	o.rejected++
+}
+
+func (o *outlier) commit(v float64) {
+	if o.deviates(v) {
+		o.values = o.values[:0]
+	}
+	o.rejected = 0
+	o.values = append(o.values, v)
+	if len(o.values) > o.window {
+		o.values = o.values[1:]
+	}
+}
+
+func (o *outlier) clone() statefulStep {
+	c := *o
+	c.values = append([]float64(nil), o.values...)
+	return &c
+}
+
+// deviates сообщает, что значение отличается от медианы принятых больше
+// допустимого
+func (o *outlier) deviates(v float64) bool {
+	if len(o.values) < 3 && len(o.values) < o.window {
+		return false
+	}
+	return math.Abs(v-median(o.values)) > o.maxDelta
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
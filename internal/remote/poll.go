@@ -0,0 +1,106 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// defaultTimeout - таймаут HTTP-запроса к датчику по умолчанию
+const defaultTimeout = 5 * time.Second
+
+// PollSensor - датчик, показания которого сборщик запрашивает по HTTP
+type PollSensor struct {
+	id       string
+	typ      string
+	url      string
+	interval time.Duration
+	client   *http.Client
+	last     time.Time // Время последнего возвращенного показания
+}
+
+// NewPollSensor создает опрашиваемый датчик; url - адрес последнего показания
+// датчика (например, http://emulator:9092/sensors/temp_kitchen)
+func NewPollSensor(id, sensorType, url string, interval, timeout time.Duration) *PollSensor {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &PollSensor{
+		id:       id,
+		typ:      sensorType,
+		url:      url,
+		interval: interval,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *PollSensor) ID() string              { return s.id }
+func (s *PollSensor) Type() string            { return s.typ }
+func (s *PollSensor) Interval() time.Duration { return s.interval }
+
+// Read запрашивает последнее показание. Ответ 503 означает, что датчик еще не
+// готов (models.ErrNotReady); ошибка опроса датчика на стороне эмулятора
+// возвращается как ошибка чтения. Показание, которое уже было прочитано (время
+// не изменилось), тоже возвращается как models.ErrNotReady, чтобы сборщик не
+// сохранял его повторно
+func (s *PollSensor) Read() (models.Reading, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return models.Reading{}, fmt.Errorf("request to %s failed: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	var reading Reading
+	if err := json.NewDecoder(resp.Body).Decode(&reading); err != nil {
+		return models.Reading{}, fmt.Errorf("invalid response from %s (status %d): %w", s.url, resp.StatusCode, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		return models.Reading{}, models.ErrNotReady
+	case resp.StatusCode != http.StatusOK:
+		return models.Reading{}, fmt.Errorf("sensor %s returned status %d: %s", s.id, resp.StatusCode, reading.Error)
+	}
+
+	if !reading.Timestamp.After(s.last) {
+		return models.Reading{}, models.ErrNotReady
+	}
+	s.last = reading.Timestamp
+
+	return models.Reading{Value: reading.Value, Timestamp: reading.Timestamp}, nil
+}
+
+// Discover запрашивает список датчиков по адресу baseURL (например,
+// http://emulator:9092) и заполняет адреса их показаний
+func Discover(baseURL string, timeout time.Duration) ([]SensorInfo, error) {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(baseURL + SensorsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote sensors: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list remote sensors: status %d", resp.StatusCode)
+	}
+
+	var sensors []SensorInfo
+	if err := json.NewDecoder(resp.Body).Decode(&sensors); err != nil {
+		return nil, fmt.Errorf("invalid remote sensor list: %w", err)
+	}
+	for i := range sensors {
+		if sensors[i].URL == "" {
+			sensors[i].URL = baseURL + SensorsPath + "/" + sensors[i].ID
+		}
+	}
+	return sensors, nil
+}
@@ -0,0 +1,50 @@
+// Package remote связывает сборщик с датчиками, работающими в отдельном
+// процессе (эмулятор или реальное устройство), по HTTP с JSON: сборщик либо
+// опрашивает адрес датчика, либо принимает показания, которые датчик
+// отправляет сам
+package remote
+
+import (
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Пути HTTP-интерфейса эмулятора и приемника показаний
+const (
+	SensorsPath  = "/sensors"         // Список датчиков; /sensors/{id} - последнее показание
+	ReadingsPath = "/remote/readings" // Прием показаний в режиме push
+)
+
+// Режимы получения показаний
+const (
+	ModePoll = "poll"
+	ModePush = "push"
+)
+
+// Reading - показание датчика в сетевом формате
+type Reading struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Value     interface{} `json:"value,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Error     string      `json:"error,omitempty"` // Ошибка последнего опроса датчика
+}
+
+// SensorInfo описывает датчик, доступный по сети
+type SensorInfo struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	IntervalMS int64  `json:"intervalMs"` // Период опроса датчика, мс
+	URL        string `json:"url,omitempty"`
+}
+
+// Interval возвращает период опроса датчика
+func (i SensorInfo) Interval() time.Duration {
+	return time.Duration(i.IntervalMS) * time.Millisecond
+}
+
+// NewSensorInfo описывает датчик
+func NewSensorInfo(s models.Sensor) SensorInfo {
+	return SensorInfo{ID: s.ID(), Type: s.Type(), IntervalMS: s.Interval().Milliseconds()}
+}
@@ -0,0 +1,219 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// pushQueueSize - сколько непрочитанных показаний хранит push-датчик;
+// при переполнении отбрасываются самые старые
+const pushQueueSize = 64
+
+// PushSensor - датчик, который сам отправляет показания в Receiver. Сборщик
+// опрашивает его как обычный датчик: Read возвращает очередное полученное
+// показание или models.ErrNotReady, если новых показаний нет
+type PushSensor struct {
+	id       string
+	typ      string
+	interval time.Duration
+
+	mu      sync.Mutex
+	queue   []models.Reading
+	lastErr error
+}
+
+// NewPushSensor создает push-датчик. interval - период опроса очереди; чтобы
+// показания не копились, он должен быть меньше периода отправки
+func NewPushSensor(id, sensorType string, interval time.Duration) *PushSensor {
+	return &PushSensor{id: id, typ: sensorType, interval: interval}
+}
+
+func (s *PushSensor) ID() string              { return s.id }
+func (s *PushSensor) Type() string            { return s.typ }
+func (s *PushSensor) Interval() time.Duration { return s.interval }
+
+// Read возвращает самое старое непрочитанное показание
+func (s *PushSensor) Read() (models.Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastErr != nil {
+		err := s.lastErr
+		s.lastErr = nil
+		return models.Reading{}, err
+	}
+	if len(s.queue) == 0 {
+		return models.Reading{}, models.ErrNotReady
+	}
+
+	reading := s.queue[0]
+	s.queue = s.queue[1:]
+	return reading, nil
+}
+
+// push добавляет полученное показание в очередь
+func (s *PushSensor) push(r Reading) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Error != "" {
+		s.lastErr = fmt.Errorf("sensor %s reported: %s", s.id, r.Error)
+		return
+	}
+
+	if len(s.queue) >= pushQueueSize {
+		s.queue = s.queue[1:]
+	}
+	s.queue = append(s.queue, models.Reading{Value: r.Value, Timestamp: r.Timestamp})
+}
+
+// Receiver принимает показания push-датчиков: POST с объектом Reading или
+// массивом таких объектов и заголовком Authorization: Bearer <token>
+type Receiver struct {
+	token   []byte
+	mu      sync.RWMutex
+	sensors map[string]*PushSensor
+}
+
+// NewReceiver создает приемник показаний. Без токена приемник отклоняет
+// все показания
+func NewReceiver(token string) *Receiver {
+	return &Receiver{token: []byte(token), sensors: make(map[string]*PushSensor)}
+}
+
+// Register подключает датчик к приемнику
+func (r *Receiver) Register(s *PushSensor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sensors[s.ID()] = s
+}
+
+// ServeHTTP реализует http.Handler
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !r.authorized(req) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+
+	var readings []Reading
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &readings)
+	} else {
+		var reading Reading
+		err = json.Unmarshal(body, &reading)
+		readings = []Reading{reading}
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid reading"})
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accepted := 0
+	for _, reading := range readings {
+		s, ok := r.sensors[reading.ID]
+		if !ok {
+			continue
+		}
+		if reading.Timestamp.IsZero() {
+			reading.Timestamp = time.Now().UTC()
+		}
+		s.push(reading)
+		accepted++
+	}
+
+	if accepted == 0 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown sensor"})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]int{"accepted": accepted})
+}
+
+// authorized проверяет токен запроса
+func (r *Receiver) authorized(req *http.Request) bool {
+	auth := req.Header.Get("Authorization")
+	if len(r.token) == 0 || !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare(r.token, []byte(strings.TrimPrefix(auth, "Bearer "))) == 1
+}
+
+// Pusher отправляет показания на адрес приемника
+type Pusher struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewPusher создает отправителя показаний на url (например,
+// http://collector:9090/remote/readings) с общим с приемником токеном
+func NewPusher(url, token string, timeout time.Duration) *Pusher {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Pusher{url: url, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+// Push отправляет показание
+func (p *Pusher) Push(reading Reading) error {
+	body, err := json.Marshal(reading)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push to %s failed: %w", p.url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUnknownSensor
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("push to %s failed: invalid token", p.url)
+	}
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("push to %s failed: status %d", p.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// ErrUnknownSensor - приемник не знает датчик
+var ErrUnknownSensor = errors.New("receiver does not know the sensor")
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
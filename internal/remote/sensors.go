@@ -0,0 +1,50 @@
+package remote
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// NewSensors создает сетевые датчики по конфигурации; push-датчики
+// подключаются к receiver
+func NewSensors(cfgs []config.RemoteSensorConfig, receiver *Receiver) ([]models.Sensor, error) {
+	sensors := make([]models.Sensor, 0, len(cfgs))
+	for _, c := range cfgs {
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("remote sensor error: %w", err)
+		}
+
+		if c.Mode == ModePush {
+			s := NewPushSensor(c.ID, c.Type, c.Interval)
+			receiver.Register(s)
+			sensors = append(sensors, s)
+			continue
+		}
+		sensors = append(sensors, NewPollSensor(c.ID, c.Type, c.URL, c.Interval, c.Timeout))
+	}
+	return sensors, nil
+}
+
+// FromDiscovery создает датчики по списку, полученному от эмулятора. В режиме
+// push очередь опрашивается вдвое чаще, чем эмулятор отправляет показания
+func FromDiscovery(infos []SensorInfo, mode string, receiver *Receiver) []models.Sensor {
+	sensors := make([]models.Sensor, 0, len(infos))
+	for _, info := range infos {
+		interval := info.Interval()
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+
+		if mode == ModePush {
+			s := NewPushSensor(info.ID, info.Type, interval/2)
+			receiver.Register(s)
+			sensors = append(sensors, s)
+			continue
+		}
+		sensors = append(sensors, NewPollSensor(info.ID, info.Type, info.URL, interval, 0))
+	}
+	return sensors
+}
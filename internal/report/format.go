@@ -0,0 +1,89 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+// Format представляет отчет в виде сообщения с разметкой Markdown
+// на языке локализатора
+func Format(r *Report, loc *time.Location, l *i18n.Localizer) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var sb strings.Builder
+
+	title := l.T("report.daily")
+	if r.Kind == Weekly {
+		title = l.T("report.weekly")
+	}
+	sb.WriteString(l.T("report.header",
+		title,
+		l.DateTime(r.From.In(loc)),
+		l.DateTime(r.To.In(loc)),
+	))
+
+	sb.WriteString(l.T("report.temperature"))
+	if len(r.Temperatures) == 0 {
+		sb.WriteString(l.T("report.no_data"))
+	}
+	for _, t := range r.Temperatures {
+		sb.WriteString(l.T("report.temperature_item", t.Room,
+			l.Quantity(t.Min, units.Celsius, ""), l.Quantity(t.Max, units.Celsius, ""), l.Quantity(t.Avg, units.Celsius, "")))
+	}
+
+	sb.WriteString(l.T("report.motion"))
+	if len(r.Motion) == 0 {
+		sb.WriteString(l.T("report.no_data"))
+	}
+	for _, m := range r.Motion {
+		sb.WriteString(l.T("report.motion_item", m.Room, l.Float(m.Duration.Hours(), 1)))
+	}
+
+	sb.WriteString(l.T("report.peak_co2"))
+	if r.PeakCO2 == nil {
+		sb.WriteString(l.T("report.no_data"))
+	} else {
+		sb.WriteString(l.T("report.peak_co2_value",
+			l.Quantity(r.PeakCO2.Value, units.PPM, "co2"), r.PeakCO2.SensorID, l.ShortDateTime(r.PeakCO2.Timestamp.In(loc))))
+	}
+
+	sb.WriteString(l.T("report.alerts", len(r.Alerts)))
+	for _, key := range alertKeys(r) {
+		sb.WriteString(l.T("report.alert_item", key.name, key.count))
+	}
+
+	return sb.String()
+}
+
+type alertKey struct {
+	name  string
+	count int
+}
+
+// alertKeys группирует оповещения по датчику и типу
+func alertKeys(r *Report) []alertKey {
+	counts := make(map[string]int)
+	for _, alert := range r.Alerts {
+		counts[fmt.Sprintf("%s (%s)", alert.SensorID, alert.AlertType)]++
+	}
+
+	keys := make([]alertKey, 0, len(counts))
+	for name, count := range counts {
+		keys = append(keys, alertKey{name: name, count: count})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+
+	return keys
+}
+
+// PlainText убирает разметку Markdown для отправки по почте
+func PlainText(text string) string {
+	return strings.ReplaceAll(text, "*", "")
+}
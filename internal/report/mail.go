@@ -0,0 +1,44 @@
+package report
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+)
+
+// SMTPMailer отправляет отчеты через SMTP-сервер
+type SMTPMailer struct {
+	cfg config.SMTPConfig
+}
+
+// NewSMTPMailer создает отправителя почты
+func NewSMTPMailer(cfg config.SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+// SendMail отправляет письмо в кодировке UTF-8
+func (m *SMTPMailer) SendMail(to, subject, body string) error {
+	addr := fmt.Sprintf("%s:%d", m.cfg.Host, m.cfg.Port)
+
+	var auth smtp.Auth
+	if m.cfg.User != "" {
+		auth = smtp.PlainAuth("", m.cfg.User, m.cfg.Password, m.cfg.Host)
+	}
+
+	var msg strings.Builder
+	msg.WriteString("From: " + m.cfg.From + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + subject + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(addr, auth, m.cfg.From, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
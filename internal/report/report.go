@@ -0,0 +1,205 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/sensortype"
+)
+
+// Kind определяет тип сводного отчета
+type Kind string
+
+const (
+	Daily  Kind = "daily"
+	Weekly Kind = "weekly"
+)
+
+// Period возвращает длительность периода, который охватывает отчет
+func (k Kind) Period() time.Duration {
+	if k == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// ParseKind разбирает тип отчета
+func ParseKind(s string) (Kind, error) {
+	switch Kind(s) {
+	case Daily, Weekly:
+		return Kind(s), nil
+	default:
+		return "", fmt.Errorf("unknown report kind %q", s)
+	}
+}
+
+// maxMotionSpan - максимальная длительность, которую засчитывает одно показание
+// датчика движения (защищает от пропусков в данных)
+const maxMotionSpan = 5 * time.Minute
+
+// RoomTemperature содержит статистику температуры в комнате
+type RoomTemperature struct {
+	Room  string
+	Min   float64
+	Max   float64
+	Avg   float64
+	Count int
+}
+
+// RoomMotion содержит суммарное время обнаруженного движения в комнате
+type RoomMotion struct {
+	Room     string
+	Duration time.Duration
+}
+
+// PeakCO2 содержит максимальный уровень CO2 за период
+type PeakCO2 struct {
+	SensorID  string
+	Value     float64
+	Timestamp time.Time
+}
+
+// Report представляет сводный отчет за период
+type Report struct {
+	Kind         Kind
+	From         time.Time
+	To           time.Time
+	Temperatures []RoomTemperature
+	Motion       []RoomMotion
+	PeakCO2      *PeakCO2
+	Alerts       []models.Alert
+}
+
+// Source - история показаний и оповещений, по которой строится отчет;
+// реализуется *database.Repository
+type Source interface {
+	GetReadingsByTypeInRange(sensorType string, from, to time.Time) ([]models.SensorData, error)
+	GetAlertsInRange(from, to time.Time) ([]models.Alert, error)
+}
+
+// Build собирает отчет по истории показаний за период, заканчивающийся в to
+func Build(repo Source, kind Kind, to time.Time) (*Report, error) {
+	from := to.Add(-kind.Period())
+	r := &Report{Kind: kind, From: from, To: to}
+
+	temperatures, err := repo.GetReadingsByTypeInRange("temperature", from, to)
+	if err != nil {
+		return nil, err
+	}
+	r.Temperatures = temperatureStats(temperatures)
+
+	motion, err := repo.GetReadingsByTypeInRange("motion", from, to)
+	if err != nil {
+		return nil, err
+	}
+	r.Motion = motionStats(motion, to)
+
+	airQuality, err := repo.GetReadingsByTypeInRange("air_quality", from, to)
+	if err != nil {
+		return nil, err
+	}
+	r.PeakCO2 = peakCO2(airQuality)
+
+	r.Alerts, err = repo.GetAlertsInRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func temperatureStats(readings []models.SensorData) []RoomTemperature {
+	stats := make(map[string]*RoomTemperature)
+	sums := make(map[string]float64)
+
+	for _, reading := range readings {
+		decoded, err := sensortype.Decode(reading.SensorType, reading.Value.Data)
+		if err != nil || decoded.Kind != models.KindNumber {
+			continue
+		}
+		value := decoded.Number
+
+		room := models.SensorLocation(reading.SensorID)
+		st, exists := stats[room]
+		if !exists {
+			st = &RoomTemperature{Room: room, Min: math.Inf(1), Max: math.Inf(-1)}
+			stats[room] = st
+		}
+
+		st.Min = math.Min(st.Min, value)
+		st.Max = math.Max(st.Max, value)
+		st.Count++
+		sums[room] += value
+	}
+
+	result := make([]RoomTemperature, 0, len(stats))
+	for room, st := range stats {
+		st.Avg = sums[room] / float64(st.Count)
+		result = append(result, *st)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Room < result[j].Room })
+
+	return result
+}
+
+// motionStats считает время активного движения: каждое показание "да"
+// засчитывается до следующего показания того же датчика
+func motionStats(readings []models.SensorData, to time.Time) []RoomMotion {
+	durations := make(map[string]time.Duration)
+
+	for i, reading := range readings {
+		room := models.SensorLocation(reading.SensorID)
+		if _, exists := durations[room]; !exists {
+			durations[room] = 0
+		}
+
+		value, err := sensortype.Decode(reading.SensorType, reading.Value.Data)
+		if err != nil || !value.Bool {
+			continue
+		}
+
+		end := to
+		if i+1 < len(readings) && readings[i+1].SensorID == reading.SensorID {
+			end = readings[i+1].Timestamp
+		}
+
+		span := end.Sub(reading.Timestamp)
+		if span > maxMotionSpan {
+			span = maxMotionSpan
+		}
+		if span > 0 {
+			durations[room] += span
+		}
+	}
+
+	result := make([]RoomMotion, 0, len(durations))
+	for room, d := range durations {
+		result = append(result, RoomMotion{Room: room, Duration: d})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Room < result[j].Room })
+
+	return result
+}
+
+func peakCO2(readings []models.SensorData) *PeakCO2 {
+	var peak *PeakCO2
+
+	for _, reading := range readings {
+		value, err := sensortype.Decode(reading.SensorType, reading.Value.Data)
+		if err != nil {
+			continue
+		}
+		co2, ok := value.Fields["co2"]
+		if !ok {
+			continue
+		}
+		if peak == nil || co2 > peak.Value {
+			peak = &PeakCO2{SensorID: reading.SensorID, Value: co2, Timestamp: reading.Timestamp}
+		}
+	}
+
+	return peak
+}
@@ -0,0 +1,194 @@
+package report
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+)
+
+// Sender доставляет отчет пользователю (например, через Telegram-бота)
+type Sender interface {
+	SendReport(userID int64, text string) error
+	Localizer(userID int64) *i18n.Localizer // Язык и единицы измерения пользователя
+}
+
+// Mailer отправляет отчет по электронной почте
+type Mailer interface {
+	SendMail(to, subject, body string) error
+}
+
+// schedule описывает одно расписание отчета
+type schedule struct {
+	userID  int64
+	kind    Kind
+	weekday time.Weekday
+	hour    int
+	minute  int
+	loc     *time.Location
+	email   string
+	next    time.Time
+}
+
+// Scheduler рассылает сводные отчеты по расписанию
+type Scheduler struct {
+	repo      Source
+	sender    Sender
+	mailer    Mailer
+	schedules []*schedule
+	logger    *log.Logger
+	stopChan  chan struct{}
+}
+
+// NewScheduler создает планировщик отчетов; mailer может быть nil
+func NewScheduler(cfgs []config.ReportConfig, repo Source, sender Sender, mailer Mailer, logger *log.Logger) (*Scheduler, error) {
+	s := &Scheduler{
+		repo:     repo,
+		sender:   sender,
+		mailer:   mailer,
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+
+	now := time.Now()
+	for _, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid report config: %w", err)
+		}
+
+		loc := time.UTC
+		if cfg.Timezone != "" {
+			loc, _ = time.LoadLocation(cfg.Timezone)
+		}
+
+		if cfg.Daily != "" {
+			sch, err := parseSchedule(Daily, cfg.Daily, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid daily report schedule for user %d: %w", cfg.UserID, err)
+			}
+			sch.userID, sch.email = cfg.UserID, cfg.Email
+			sch.next = sch.nextAfter(now)
+			s.schedules = append(s.schedules, sch)
+		}
+
+		if cfg.Weekly != "" {
+			sch, err := parseSchedule(Weekly, cfg.Weekly, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weekly report schedule for user %d: %w", cfg.UserID, err)
+			}
+			sch.userID, sch.email = cfg.UserID, cfg.Email
+			sch.next = sch.nextAfter(now)
+			s.schedules = append(s.schedules, sch)
+		}
+	}
+
+	return s, nil
+}
+
+// Start запускает цикл рассылки и блокируется до вызова Stop
+func (s *Scheduler) Start() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Printf("Starting report scheduler with %d schedules", len(s.schedules))
+	for _, sch := range s.schedules {
+		s.logger.Printf("Next %s report for user %d at %s", sch.kind, sch.userID, sch.next.Format(time.RFC3339))
+	}
+
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, sch := range s.schedules {
+				if now.Before(sch.next) {
+					continue
+				}
+				s.deliver(sch, now)
+				sch.next = sch.nextAfter(now)
+			}
+		case <-s.stopChan:
+			s.logger.Println("Stopping report scheduler")
+			return
+		}
+	}
+}
+
+// Stop останавливает планировщик
+func (s *Scheduler) Stop() {
+	close(s.stopChan)
+}
+
+func (s *Scheduler) deliver(sch *schedule, now time.Time) {
+	r, err := Build(s.repo, sch.kind, now.UTC())
+	if err != nil {
+		s.logger.Printf("Error building %s report for user %d: %v", sch.kind, sch.userID, err)
+		return
+	}
+
+	text := Format(r, sch.loc, s.sender.Localizer(sch.userID))
+
+	if err := s.sender.SendReport(sch.userID, text); err != nil {
+		s.logger.Printf("Error sending %s report to user %d: %v", sch.kind, sch.userID, err)
+	}
+
+	if sch.email != "" && s.mailer != nil {
+		subject := fmt.Sprintf("Smart House: %s report %s", sch.kind, now.In(sch.loc).Format("02.01.2006"))
+		if err := s.mailer.SendMail(sch.email, subject, PlainText(text)); err != nil {
+			s.logger.Printf("Error mailing %s report to %s: %v", sch.kind, sch.email, err)
+		}
+	}
+
+	s.logger.Printf("Delivered %s report to user %d", sch.kind, sch.userID)
+}
+
+// parseSchedule разбирает расписание вида "08:00" (daily) или "mon 08:00" (weekly)
+func parseSchedule(kind Kind, spec string, loc *time.Location) (*schedule, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	sch := &schedule{kind: kind, loc: loc}
+
+	clock := ""
+	switch {
+	case kind == Daily && len(fields) == 1:
+		clock = fields[0]
+	case kind == Weekly && len(fields) == 2:
+		weekday, ok := config.ParseWeekday(fields[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", fields[0])
+		}
+		sch.weekday = weekday
+		clock = fields[1]
+	default:
+		return nil, fmt.Errorf("invalid schedule %q", spec)
+	}
+
+	offset, err := config.ParseClock(clock)
+	if err != nil {
+		return nil, err
+	}
+	sch.hour, sch.minute = int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+
+	return sch, nil
+}
+
+// nextAfter возвращает ближайшее время срабатывания строго после t
+func (s *schedule) nextAfter(t time.Time) time.Time {
+	local := t.In(s.loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, s.loc)
+
+	if s.kind == Weekly {
+		days := (int(s.weekday) - int(next.Weekday()) + 7) % 7
+		next = next.AddDate(0, 0, days)
+	}
+
+	for !next.After(t) {
+		if s.kind == Weekly {
+			next = next.AddDate(0, 0, 7)
+		} else {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+
+	return next
+}
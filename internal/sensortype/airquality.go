@@ -0,0 +1,66 @@
+package sensortype
+
+import (
+	"fmt"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/airquality"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+func init() {
+	Register(Type{
+		Name:   "air_quality",
+		Unit:   units.PPM,
+		Value:  ValueSchema{Kind: models.KindObject, Fields: []string{"co2", "nh3"}},
+		Params: func() models.SensorConfig { return &airQualityParams{} },
+		New:    newAirQualitySensor,
+		Metrics: []Metric{
+			{Name: "air_co2_ppm", Help: "Уровень CO2 в частях на миллион", Field: "co2"},
+			{Name: "air_nh3_ppm", Help: "Уровень NH3 в частях на миллион", Field: "nh3"},
+		},
+		Format: func(value models.Value, l *i18n.Localizer) string {
+			return l.T("value.air_quality", l.Quantity(value.Fields["co2"], units.PPM, "co2"), l.Quantity(value.Fields["nh3"], units.PPM, "nh3"))
+		},
+	})
+}
+
+// airQualityParams - параметры датчика качества воздуха, ppm
+type airQualityParams struct {
+	MinCO2 float64 `yaml:"min_co2"`
+	MaxCO2 float64 `yaml:"max_co2"`
+	MinNH3 float64 `yaml:"min_nh3"`
+	MaxNH3 float64 `yaml:"max_nh3"`
+}
+
+func (p *airQualityParams) Validate() error {
+	if p.MinCO2 < 0 || p.MinNH3 < 0 {
+		return fmt.Errorf("gas levels cannot be negative")
+	}
+	return nil
+}
+
+// newAirQualitySensor создает датчик качества воздуха: в комнате, которую
+// моделирует окружение, он показывает ее состояние, иначе - случайные
+// значения в заданных диапазонах
+func newAirQualitySensor(entry config.SensorEntry, params models.SensorConfig, opts Options) (models.Sensor, error) {
+	p := params.(*airQualityParams)
+
+	if opts.Environment != nil && opts.Environment.HasRoom(models.SensorLocation(entry.ID)) {
+		return environment.NewAirQualitySensor(entry.ID, entry.Interval, opts.Seed, opts.Environment)
+	}
+
+	return airquality.New(airquality.Config{
+		ID:       entry.ID,
+		MinCO2:   p.MinCO2,
+		MaxCO2:   p.MaxCO2,
+		MinNH3:   p.MinNH3,
+		MaxNH3:   p.MaxNH3,
+		Interval: entry.Interval,
+		Seed:     opts.Seed,
+		Clock:    opts.Clock,
+	})
+}
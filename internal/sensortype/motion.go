@@ -0,0 +1,80 @@
+package sensortype
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/occupancy"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/motion"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+func init() {
+	Register(Type{
+		Name:   "motion",
+		Value:  ValueSchema{Kind: models.KindBool},
+		Params: func() models.SensorConfig { return &motionParams{} },
+		New:    newMotionSensor,
+		Metrics: []Metric{
+			{Name: "motion_detected", Help: "Обнаружено ли движение (1 - да, 0 - нет)"},
+		},
+		Format: func(value models.Value, l *i18n.Localizer) string {
+			if value.Bool {
+				return l.T("value.motion_detected")
+			}
+			return l.T("value.motion_clear")
+		},
+	})
+}
+
+// motionParams - параметры датчика движения
+type motionParams struct {
+	DetectionInterval time.Duration `yaml:"detection_interval"` // Устаревший синоним latch
+	Probability       float64       `yaml:"probability"`        // Вероятность срабатывания за один опрос при активном человеке в комнате (по умолчанию 0.3)
+	IdleProbability   float64       `yaml:"idle_probability"`   // Вероятность ложного срабатывания в пустой комнате
+	Latch             time.Duration `yaml:"latch"`              // Сколько датчик удерживает состояние "движение" после срабатывания
+}
+
+// latch возвращает время удержания срабатывания
+func (p *motionParams) latch() time.Duration {
+	if p.Latch > 0 {
+		return p.Latch
+	}
+	return p.DetectionInterval
+}
+
+func (p *motionParams) Validate() error {
+	if p.latch() <= 0 {
+		return fmt.Errorf("latch (detection interval) must be positive")
+	}
+	if p.Probability < 0 || p.Probability > 1 || p.IdleProbability < 0 || p.IdleProbability > 1 {
+		return fmt.Errorf("probabilities must be between 0 and 1")
+	}
+	return nil
+}
+
+// newMotionSensor создает датчик движения: в комнате графа дома он
+// срабатывает от жильцов, иначе - случайно с заданной вероятностью
+func newMotionSensor(entry config.SensorEntry, params models.SensorConfig, opts Options) (models.Sensor, error) {
+	p := params.(*motionParams)
+
+	probability := p.Probability
+	if probability == 0 {
+		probability = motion.DefaultProbability
+	}
+
+	if opts.Occupancy != nil && opts.Occupancy.HasRoom(models.SensorLocation(entry.ID)) {
+		return occupancy.NewMotionSensor(entry.ID, entry.Interval, p.latch(), probability, p.IdleProbability, opts.Seed, opts.Occupancy)
+	}
+
+	return motion.New(motion.Config{
+		ID:                entry.ID,
+		DetectionInterval: p.latch(),
+		Interval:          entry.Interval,
+		Probability:       probability,
+		Seed:              opts.Seed,
+		Clock:             opts.Clock,
+	})
+}
@@ -0,0 +1,253 @@
+// Package sensortype - реестр типов датчиков. Каждый тип регистрируется один
+// раз и описывает все, что о нем нужно остальной системе: параметры
+// конфигурации и конструктор эмулируемого датчика, схему значения, единицу
+// измерения, метрики Prometheus и форматирование для бота. Чтобы добавить
+// новый тип датчика, достаточно одного файла с вызовом Register
+package sensortype
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/occupancy"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sim"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+// Type описывает тип датчика
+type Type struct {
+	Name  string      // Имя типа: temperature, motion, air_quality
+	Unit  units.Unit  // Каноническая единица: в ней показания хранятся в БД
+	Value ValueSchema // Схема значения показания
+
+	// Params создает структуру параметров датчика со значениями по умолчанию;
+	// в нее разбирается блок params из конфигурации
+	Params func() models.SensorConfig
+	// New создает эмулируемый датчик по записи конфигурации и разобранным параметрам
+	New func(entry config.SensorEntry, params models.SensorConfig, opts Options) (models.Sensor, error)
+
+	Metrics []Metric // Метрики Prometheus, которые обновляет сборщик
+
+	// Format возвращает значение показания, проверенное по схеме, для
+	// сообщения бота
+	Format func(value models.Value, l *i18n.Localizer) string
+}
+
+// ValueSchema описывает значение показания
+type ValueSchema struct {
+	Kind   models.ValueKind
+	Fields []string // Обязательные поля составного значения (KindObject)
+}
+
+// FieldNames возвращает поля, к которым можно обращаться в правилах: пустая
+// строка означает само скалярное значение
+func (s ValueSchema) FieldNames() []string {
+	if s.Kind == models.KindObject {
+		return s.Fields
+	}
+	return []string{""}
+}
+
+// Decode проверяет значение по схеме и возвращает типизированное значение
+func (s ValueSchema) Decode(data interface{}) (models.Value, error) {
+	value, err := models.DecodeValue(data)
+	if err != nil {
+		return models.Value{}, err
+	}
+	if value.Kind != s.Kind {
+		return models.Value{}, fmt.Errorf("value must be %s, got %s", article(s.Kind), article(value.Kind))
+	}
+
+	if s.Kind == models.KindObject {
+		for _, field := range s.Fields {
+			if _, ok := value.Fields[field]; !ok {
+				return models.Value{}, fmt.Errorf("missing field %s", field)
+			}
+		}
+		for _, field := range value.FieldNames() {
+			if !s.hasField(field) {
+				return models.Value{}, fmt.Errorf("unknown field %s (expected %s)", field, strings.Join(s.Fields, ", "))
+			}
+		}
+	}
+	return value, nil
+}
+
+func (s ValueSchema) hasField(name string) bool {
+	for _, field := range s.Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// article называет вид значения в сообщении об ошибке
+func article(kind models.ValueKind) string {
+	switch kind {
+	case models.KindNumber:
+		return "a number"
+	case models.KindBool:
+		return "a boolean"
+	case models.KindObject:
+		return "an object"
+	}
+	return string(kind)
+}
+
+// Metric связывает значение показания с метрикой Prometheus с метками
+// sensor_id и location
+type Metric struct {
+	Name  string
+	Help  string
+	Field string // Поле объекта, пусто - значение целиком
+}
+
+// Options - модели эмулятора, к которым подключаются датчики (любая может
+// быть nil), часы и зерно генератора датчика
+type Options struct {
+	Environment *environment.Environment
+	Occupancy   *occupancy.Simulation
+	Clock       sim.Clock // nil - реальное время
+	Seed        int64     // Зерно датчика: собственное или общее зерно эмулятора
+}
+
+var registry = make(map[string]Type)
+
+// Register добавляет тип датчика в реестр. Вызывается из init; повторная
+// регистрация типа - ошибка программы
+func Register(t Type) {
+	if t.Name == "" {
+		panic("sensortype: type name is required")
+	}
+	if _, exists := registry[t.Name]; exists {
+		panic(fmt.Sprintf("sensortype: type %s is already registered", t.Name))
+	}
+	registry[t.Name] = t
+}
+
+// Lookup возвращает тип датчика по имени
+func Lookup(name string) (Type, bool) {
+	t, ok := registry[name]
+	return t, ok
+}
+
+// All возвращает все зарегистрированные типы в порядке имен
+func All() []Type {
+	types := make([]Type, 0, len(registry))
+	for _, t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// NewSensor создает эмулируемый датчик по записи конфигурации
+func NewSensor(entry config.SensorEntry, opts Options) (models.Sensor, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	t, ok := Lookup(entry.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown sensor type %q of sensor %s", entry.Type, entry.ID)
+	}
+	if t.New == nil {
+		return nil, fmt.Errorf("sensor type %s cannot be emulated", t.Name)
+	}
+
+	var params models.SensorConfig
+	if t.Params != nil {
+		params = t.Params()
+		if err := entry.DecodeParams(params); err != nil {
+			return nil, err
+		}
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid params of sensor %s: %w", entry.ID, err)
+		}
+	}
+
+	return t.New(entry, params, opts)
+}
+
+// Decode разбирает значение показания датчика типа sensorType: значения
+// зарегистрированных типов проверяются по схеме, остальные - только на
+// допустимый вид значения
+func Decode(sensorType string, data interface{}) (models.Value, error) {
+	if t, ok := Lookup(sensorType); ok {
+		return t.Value.Decode(data)
+	}
+	return models.DecodeValue(data)
+}
+
+// Normalize проверяет показание по схеме его типа и переводит значение в
+// каноническую единицу типа: показание без единицы считается заданным в
+// канонической. Показания незарегистрированных типов (например, метрики
+// Telegraf) сохраняются как есть
+func Normalize(data models.SensorData) (models.SensorData, error) {
+	t, ok := Lookup(data.SensorType)
+	if !ok {
+		return data, nil
+	}
+
+	value, err := t.Value.Decode(data.Value.Data)
+	if err != nil {
+		return data, fmt.Errorf("invalid %s value of sensor %s: %w", t.Name, data.SensorID, err)
+	}
+
+	if t.Unit != "" && data.Unit != "" && data.Unit != string(t.Unit) {
+		from, err := units.Parse(data.Unit)
+		if err != nil {
+			return data, fmt.Errorf("invalid unit of sensor %s: %w", data.SensorID, err)
+		}
+		if value, err = units.ConvertValue(value, from, t.Unit); err != nil {
+			return data, fmt.Errorf("invalid unit of sensor %s: %w", data.SensorID, err)
+		}
+	}
+
+	data.Value.Data = value.Data()
+	data.Unit = string(t.Unit)
+	return data, nil
+}
+
+// Convert переводит сохраненное показание в предпочтительные единицы.
+// Показания незарегистрированных типов и типов без единицы не меняются
+func Convert(data models.SensorData, p units.Preference) models.SensorData {
+	t, ok := Lookup(data.SensorType)
+	if !ok || t.Unit == "" {
+		return data
+	}
+	to := p.For(t.Unit)
+	if to == t.Unit {
+		return data
+	}
+
+	value, err := t.Value.Decode(data.Value.Data)
+	if err != nil {
+		return data
+	}
+	converted, err := units.ConvertValue(value, t.Unit, to)
+	if err != nil {
+		return data
+	}
+	data.Value.Data = converted.Data()
+	data.Unit = string(to)
+	return data
+}
+
+// ConvertReadings переводит показания в предпочтительные единицы
+func ConvertReadings(readings []models.SensorData, p units.Preference) []models.SensorData {
+	if len(p) == 0 {
+		return readings
+	}
+	converted := make([]models.SensorData, len(readings))
+	for i, reading := range readings {
+		converted[i] = Convert(reading, p)
+	}
+	return converted
+}
@@ -0,0 +1,67 @@
+package sensortype
+
+import (
+	"fmt"
+
+	"github.com/4Amangel1/smart-house-automate/internal/config"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/environment"
+	"github.com/4Amangel1/smart-house-automate/internal/emulator/sensors/temperature"
+	"github.com/4Amangel1/smart-house-automate/internal/i18n"
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+	"github.com/4Amangel1/smart-house-automate/internal/units"
+)
+
+func init() {
+	Register(Type{
+		Name:   "temperature",
+		Unit:   units.Celsius,
+		Value:  ValueSchema{Kind: models.KindNumber},
+		Params: func() models.SensorConfig { return &temperatureParams{} },
+		New:    newTemperatureSensor,
+		Metrics: []Metric{
+			{Name: "temperature_celsius", Help: "Температура в градусах Цельсия"},
+		},
+		Format: func(value models.Value, l *i18n.Localizer) string {
+			return l.T("value.temperature", l.Quantity(value.Number, units.Celsius, ""))
+		},
+	})
+}
+
+// temperatureParams - параметры датчика температуры: параметры модели
+// температуры и дополнительно модель environment (по умолчанию environment,
+// если комната моделируется, иначе uniform)
+type temperatureParams struct {
+	temperature.Params `yaml:",inline"`
+}
+
+func (p *temperatureParams) Validate() error {
+	// Датчик комнаты показывает температуру окружения, параметры модели не используются
+	if p.Model == "environment" {
+		return nil
+	}
+	return p.Params.Validate()
+}
+
+// newTemperatureSensor создает датчик температуры: в комнате, которую
+// моделирует окружение, он показывает ее температуру (если не выбрана другая
+// модель), иначе генерирует показания по выбранной модели
+func newTemperatureSensor(entry config.SensorEntry, params models.SensorConfig, opts Options) (models.Sensor, error) {
+	p := params.(*temperatureParams)
+	env := opts.Environment
+
+	useEnvironment := p.Model == "environment" || (p.Model == "" && env != nil && env.HasRoom(models.SensorLocation(entry.ID)))
+	if useEnvironment {
+		if env == nil {
+			return nil, fmt.Errorf("%s requires the environment model", entry.ID)
+		}
+		return environment.NewTemperatureSensor(entry.ID, entry.Interval, opts.Seed, env)
+	}
+
+	return temperature.New(temperature.Config{
+		ID:       entry.ID,
+		Params:   p.Params,
+		Interval: entry.Interval,
+		Seed:     opts.Seed,
+		Clock:    opts.Clock,
+	})
+}
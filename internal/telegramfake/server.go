@@ -0,0 +1,335 @@
+// Package telegramfake реализует локальную заглушку Telegram Bot API
+// (getMe, getUpdates, sendMessage, sendPhoto и управление вебхуком)
+// для тестов и офлайн-разработки
+package telegramfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxPollTimeout ограничивает время ожидания getUpdates
+const maxPollTimeout = 30 * time.Second
+
+// SentMessage представляет сообщение, отправленное ботом
+type SentMessage struct {
+	Method    string            `json:"method"`
+	MessageID int               `json:"messageId"`
+	ChatID    int64             `json:"chatId"`
+	Text      string            `json:"text"`
+	Params    map[string]string `json:"params"`
+	Time      time.Time         `json:"time"`
+}
+
+// IncomingMessage описывает сообщение пользователя, которое нужно доставить боту
+type IncomingMessage struct {
+	ChatID       int64  `json:"chat_id"`
+	UserID       int64  `json:"user_id"`
+	Text         string `json:"text"`
+	LanguageCode string `json:"language_code"`
+}
+
+// Server - фейковый сервер Telegram Bot API
+type Server struct {
+	token string
+	self  tgbotapi.User
+
+	mu            sync.Mutex
+	updates       []tgbotapi.Update
+	nextUpdateID  int
+	nextMessageID int
+	sent          []SentMessage
+	webhookURL    string
+	wake          chan struct{}
+}
+
+// NewServer создает фейковый сервер; пустой token разрешает любой токен
+func NewServer(token string) *Server {
+	return &Server{
+		token: token,
+		self: tgbotapi.User{
+			ID:        1,
+			IsBot:     true,
+			FirstName: "Smart House",
+			UserName:  "smart_house_fake_bot",
+		},
+		nextUpdateID:  1,
+		nextMessageID: 1,
+		wake:          make(chan struct{}),
+	}
+}
+
+// Endpoint возвращает шаблон адреса API для tgbotapi.NewBotAPIWithAPIEndpoint
+func Endpoint(baseURL string) string {
+	return strings.TrimRight(baseURL, "/") + "/bot%s/%s"
+}
+
+// PushUpdate ставит обновление в очередь getUpdates
+func (s *Server) PushUpdate(update tgbotapi.Update) {
+	s.mu.Lock()
+	if update.UpdateID == 0 {
+		update.UpdateID = s.nextUpdateID
+	}
+	s.nextUpdateID = update.UpdateID + 1
+	s.updates = append(s.updates, update)
+
+	close(s.wake)
+	s.wake = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// PushMessage ставит в очередь текстовое сообщение пользователя
+func (s *Server) PushMessage(msg IncomingMessage) {
+	s.PushUpdate(tgbotapi.Update{Message: NewMessage(msg)})
+}
+
+// NewMessage строит сообщение Telegram; текст, начинающийся с "/",
+// размечается как команда
+func NewMessage(msg IncomingMessage) *tgbotapi.Message {
+	chatID := msg.ChatID
+	if chatID == 0 {
+		chatID = msg.UserID
+	}
+
+	message := &tgbotapi.Message{
+		Date: int(time.Now().Unix()),
+		From: &tgbotapi.User{
+			ID:           msg.UserID,
+			FirstName:    "User",
+			LanguageCode: msg.LanguageCode,
+		},
+		Chat: &tgbotapi.Chat{ID: chatID, Type: "private"},
+		Text: msg.Text,
+	}
+
+	if strings.HasPrefix(msg.Text, "/") {
+		command := strings.SplitN(msg.Text, " ", 2)[0]
+		message.Entities = []tgbotapi.MessageEntity{
+			{Type: "bot_command", Offset: 0, Length: len(command)},
+		}
+	}
+
+	return message
+}
+
+// Sent возвращает копию отправленных ботом сообщений
+func (s *Server) Sent() []SentMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sent := make([]SentMessage, len(s.sent))
+	copy(sent, s.sent)
+	return sent
+}
+
+// Reset очищает очередь обновлений и отправленные сообщения
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updates = nil
+	s.sent = nil
+}
+
+// ServeHTTP обрабатывает запросы Bot API (/bot<token>/<method>)
+// и управляющие запросы (/fake/messages, /fake/sent)
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/fake/messages":
+		s.handlePushMessage(w, r)
+		return
+	case "/fake/sent":
+		writeJSON(w, http.StatusOK, s.Sent())
+		return
+	}
+
+	token, method, ok := parseAPIPath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if s.token != "" && token != s.token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := parseForm(r); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	switch method {
+	case "getMe":
+		writeResult(w, s.self)
+	case "getUpdates":
+		s.handleGetUpdates(w, r)
+	case "sendMessage":
+		s.handleSend(w, r, method, r.FormValue("text"))
+	case "sendPhoto":
+		s.handleSend(w, r, method, r.FormValue("caption"))
+	case "setWebhook":
+		s.mu.Lock()
+		s.webhookURL = r.FormValue("url")
+		s.mu.Unlock()
+		writeResult(w, true)
+	case "deleteWebhook":
+		s.mu.Lock()
+		s.webhookURL = ""
+		s.mu.Unlock()
+		writeResult(w, true)
+	case "getWebhookInfo":
+		s.mu.Lock()
+		info := tgbotapi.WebhookInfo{URL: s.webhookURL}
+		s.mu.Unlock()
+		writeResult(w, info)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found: method "+method+" is not supported")
+	}
+}
+
+func (s *Server) handlePushMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	var msg IncomingMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+	if msg.UserID == 0 || msg.Text == "" {
+		writeError(w, http.StatusBadRequest, "Bad Request: user_id and text are required")
+		return
+	}
+
+	s.PushMessage(msg)
+	writeResult(w, true)
+}
+
+func (s *Server) handleGetUpdates(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.FormValue("offset"))
+	limit, _ := strconv.Atoi(r.FormValue("limit"))
+	timeout, _ := strconv.Atoi(r.FormValue("timeout"))
+
+	wait := time.Duration(timeout) * time.Second
+	if wait > maxPollTimeout {
+		wait = maxPollTimeout
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		// Как и в Telegram, offset подтверждает все предыдущие обновления
+		pending := s.updates[:0]
+		for _, u := range s.updates {
+			if u.UpdateID >= offset {
+				pending = append(pending, u)
+			}
+		}
+		s.updates = pending
+
+		result := make([]tgbotapi.Update, len(pending))
+		copy(result, pending)
+		wake := s.wake
+		s.mu.Unlock()
+
+		if limit > 0 && len(result) > limit {
+			result = result[:limit]
+		}
+		if len(result) > 0 || wait == 0 {
+			writeResult(w, result)
+			return
+		}
+
+		select {
+		case <-wake:
+		case <-deadline.C:
+			writeResult(w, []tgbotapi.Update{})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request, method, text string) {
+	chatID, err := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: chat_id is invalid")
+		return
+	}
+
+	params := make(map[string]string)
+	for key := range r.Form {
+		params[key] = r.Form.Get(key)
+	}
+
+	s.mu.Lock()
+	sent := SentMessage{
+		Method:    method,
+		MessageID: s.nextMessageID,
+		ChatID:    chatID,
+		Text:      text,
+		Params:    params,
+		Time:      time.Now().UTC(),
+	}
+	s.nextMessageID++
+	s.sent = append(s.sent, sent)
+	s.mu.Unlock()
+
+	writeResult(w, tgbotapi.Message{
+		MessageID: sent.MessageID,
+		From:      &s.self,
+		Date:      int(sent.Time.Unix()),
+		Chat:      &tgbotapi.Chat{ID: chatID, Type: "private"},
+		Text:      text,
+	})
+}
+
+// parseAPIPath разбирает путь вида /bot<token>/<method>
+func parseAPIPath(path string) (string, string, bool) {
+	path = strings.TrimPrefix(path, "/")
+	if !strings.HasPrefix(path, "bot") {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(path, "bot"), "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func parseForm(r *http.Request) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.ParseMultipartForm(32 << 20)
+	}
+	return r.ParseForm()
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tgbotapi.APIResponse{Ok: true, Result: data})
+}
+
+func writeError(w http.ResponseWriter, status int, description string) {
+	writeJSON(w, status, tgbotapi.APIResponse{Ok: false, ErrorCode: status, Description: description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,237 @@
+// Package units описывает единицы измерения показаний и переводит значения
+// между ними. Показания хранятся в канонической единице типа датчика, а API и
+// бот выводят их в единицах, которые предпочитает пользователь
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+// Unit - единица измерения
+type Unit string
+
+const (
+	Celsius    Unit = "°C"
+	Fahrenheit Unit = "°F"
+	PPM        Unit = "ppm"   // Частей на миллион по объему
+	MgPerM3    Unit = "mg/m³" // Миллиграммов на кубический метр
+)
+
+// Dimension - физическая величина, в которой единицы взаимозаменяемы
+type Dimension string
+
+const (
+	Temperature   Dimension = "temperature"
+	Concentration Dimension = "concentration" // Концентрация газа
+)
+
+// unit описывает единицу: величину, перевод в базовую единицу величины и
+// обратно и число знаков после запятой при выводе
+type unit struct {
+	dimension Dimension
+	toBase    func(v, molarMass float64) float64
+	fromBase  func(v, molarMass float64) float64
+	precision int
+}
+
+// molarVolume - молярный объем газа при 25 °C и 1 атм, л/моль; по нему
+// пересчитываются ppm и mg/m³
+const molarVolume = 24.45
+
+var known = map[Unit]unit{
+	Celsius: {
+		dimension: Temperature,
+		toBase:    func(v, _ float64) float64 { return v },
+		fromBase:  func(v, _ float64) float64 { return v },
+		precision: 1,
+	},
+	Fahrenheit: {
+		dimension: Temperature,
+		toBase:    func(v, _ float64) float64 { return (v - 32) * 5 / 9 },
+		fromBase:  func(v, _ float64) float64 { return v*9/5 + 32 },
+		precision: 1,
+	},
+	PPM: {
+		dimension: Concentration,
+		toBase:    func(v, _ float64) float64 { return v },
+		fromBase:  func(v, _ float64) float64 { return v },
+		precision: 0,
+	},
+	MgPerM3: {
+		dimension: Concentration,
+		toBase:    func(v, m float64) float64 { return v * molarVolume / m },
+		fromBase:  func(v, m float64) float64 { return v * m / molarVolume },
+		precision: 2,
+	},
+}
+
+// aliases - написания единиц, которые присылают устройства и пользователи
+var aliases = map[string]Unit{
+	"°c": Celsius, "c": Celsius, "degc": Celsius, "celsius": Celsius,
+	"°f": Fahrenheit, "f": Fahrenheit, "degf": Fahrenheit, "fahrenheit": Fahrenheit,
+	"ppm": PPM, "mg/m³": MgPerM3, "mg/m3": MgPerM3, "mg/m^3": MgPerM3, "mgm3": MgPerM3,
+}
+
+// molarMasses - молярные массы газов, г/моль; ключ - поле показания
+var molarMasses = map[string]float64{
+	"co2": 44.01,
+	"nh3": 17.031,
+	"co":  28.01,
+}
+
+// All возвращает известные единицы
+func All() []Unit {
+	return []Unit{Celsius, Fahrenheit, PPM, MgPerM3}
+}
+
+// Parse разбирает единицу с учетом распространенных написаний ("C", "degF", "mg/m3")
+func Parse(s string) (Unit, error) {
+	if u, ok := aliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown unit %q", s)
+}
+
+// Dimension возвращает величину единицы; пусто - единица неизвестна
+func (u Unit) Dimension() Dimension {
+	return known[u].dimension
+}
+
+// Precision возвращает число знаков после запятой при выводе
+func (u Unit) Precision() int {
+	return known[u].precision
+}
+
+// Format добавляет единицу к отформатированному числу: градусы пишутся
+// слитно, остальные единицы - через пробел
+func (u Unit) Format(value string) string {
+	switch u {
+	case "":
+		return value
+	case Celsius, Fahrenheit:
+		return value + string(u)
+	}
+	return value + " " + string(u)
+}
+
+// Convert переводит значение из единицы from в to. Для концентраций нужен
+// газ (substance - поле показания: co2, nh3), чтобы учесть его молярную массу
+func Convert(value float64, from, to Unit, substance string) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+
+	src, ok := known[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	dst, ok := known[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if src.dimension != dst.dimension {
+		return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+
+	var molarMass float64
+	if src.dimension == Concentration {
+		molarMass, ok = molarMasses[strings.ToLower(substance)]
+		if !ok {
+			return 0, fmt.Errorf("cannot convert %s to %s: unknown molar mass of %q", from, to, substance)
+		}
+	}
+
+	return dst.fromBase(src.toBase(value, molarMass), molarMass), nil
+}
+
+// ConvertValue переводит значение показания из единицы from в to: число
+// целиком, у составного значения - каждое поле как отдельный газ.
+// Логические значения не имеют единицы и не меняются
+func ConvertValue(value models.Value, from, to Unit) (models.Value, error) {
+	if from == to {
+		return value, nil
+	}
+
+	switch value.Kind {
+	case models.KindNumber:
+		v, err := Convert(value.Number, from, to, "")
+		if err != nil {
+			return value, err
+		}
+		return models.NumberValue(v), nil
+
+	case models.KindObject:
+		fields := make(map[string]float64, len(value.Fields))
+		for name, f := range value.Fields {
+			v, err := Convert(f, from, to, name)
+			if err != nil {
+				return value, err
+			}
+			fields[name] = v
+		}
+		return models.ObjectValue(fields), nil
+	}
+
+	return value, nil
+}
+
+// Preference - единицы, в которых пользователь хочет видеть величины; для
+// величин без предпочтения используется каноническая единица типа датчика
+type Preference map[Dimension]Unit
+
+// ParsePreference разбирает список единиц через запятую или пробел, например
+// "°F, mg/m³". Пустая строка - канонические единицы
+func ParsePreference(s string) (Preference, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	p := make(Preference, len(fields))
+	for _, field := range fields {
+		u, err := Parse(field)
+		if err != nil {
+			return nil, err
+		}
+		dimension := u.Dimension()
+		if other, ok := p[dimension]; ok && other != u {
+			return nil, fmt.Errorf("units %s and %s measure the same quantity", other, u)
+		}
+		p[dimension] = u
+	}
+	return p, nil
+}
+
+// For возвращает предпочтительную единицу для значений в канонической единице
+func (p Preference) For(canonical Unit) Unit {
+	if u, ok := p[canonical.Dimension()]; ok {
+		return u
+	}
+	return canonical
+}
+
+// Merge возвращает предпочтения p, дополненные и переопределенные other
+func (p Preference) Merge(other Preference) Preference {
+	merged := make(Preference, len(p)+len(other))
+	for dimension, u := range p {
+		merged[dimension] = u
+	}
+	for dimension, u := range other {
+		merged[dimension] = u
+	}
+	return merged
+}
+
+// String возвращает предпочтения в виде, который понимает ParsePreference
+func (p Preference) String() string {
+	list := make([]string, 0, len(p))
+	for _, u := range p {
+		list = append(list, string(u))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
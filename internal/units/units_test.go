@@ -0,0 +1,203 @@
+package units
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/4Amangel1/smart-house-automate/internal/models"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     float64
+		from, to  Unit
+		substance string
+		want      float64
+		wantErr   bool
+	}{
+		{name: "freezing to °F", value: 0, from: Celsius, to: Fahrenheit, want: 32},
+		{name: "boiling to °F", value: 100, from: Celsius, to: Fahrenheit, want: 212},
+		{name: "°F to °C", value: 71.6, from: Fahrenheit, to: Celsius, want: 22},
+		{name: "negative °F to °C", value: -40, from: Fahrenheit, to: Celsius, want: -40},
+		{name: "same unit", value: 21.5, from: Celsius, to: Celsius, want: 21.5},
+		{name: "co2 to mg/m³", value: 1000, from: PPM, to: MgPerM3, substance: "co2", want: 1800},
+		{name: "co2 to ppm", value: 1800, from: MgPerM3, to: PPM, substance: "CO2", want: 1000},
+		{name: "nh3 to mg/m³", value: 24.45, from: PPM, to: MgPerM3, substance: "nh3", want: 17.031},
+		{name: "nh3 to ppm", value: 17.031, from: MgPerM3, to: PPM, substance: "nh3", want: 24.45},
+		{name: "co to mg/m³", value: 24.45, from: PPM, to: MgPerM3, substance: "co", want: 28.01},
+		{name: "co to ppm", value: 28.01, from: MgPerM3, to: PPM, substance: "co", want: 24.45},
+		{name: "unknown substance", value: 1, from: PPM, to: MgPerM3, substance: "o3", wantErr: true},
+		{name: "concentration without substance", value: 1, from: PPM, to: MgPerM3, wantErr: true},
+		{name: "dimension mismatch", value: 1, from: Celsius, to: PPM, wantErr: true},
+		{name: "unknown source unit", value: 1, from: "K", to: Celsius, wantErr: true},
+		{name: "unknown target unit", value: 1, from: Celsius, to: "K", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.value, tt.from, tt.to, tt.substance)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Unit
+		wantErr bool
+	}{
+		{input: "°C", want: Celsius},
+		{input: " c ", want: Celsius},
+		{input: "degC", want: Celsius},
+		{input: "Celsius", want: Celsius},
+		{input: "F", want: Fahrenheit},
+		{input: "degF", want: Fahrenheit},
+		{input: "PPM", want: PPM},
+		{input: "mg/m3", want: MgPerM3},
+		{input: "mg/m^3", want: MgPerM3},
+		{input: "mg/m³", want: MgPerM3},
+		{input: "K", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %q, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParsePreference(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Preference
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: " , ", want: nil},
+		{input: "°F", want: Preference{Temperature: Fahrenheit}},
+		{input: "F, mg/m3", want: Preference{Temperature: Fahrenheit, Concentration: MgPerM3}},
+		{input: "degF F", want: Preference{Temperature: Fahrenheit}},
+		{input: "°C,°F", wantErr: true},
+		{input: "ppm mg/m3", wantErr: true},
+		{input: "F,K", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePreference(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePreference(%q) = %v, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePreference(%q) error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePreference(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    models.Value
+		from, to Unit
+		want     models.Value
+		wantErr  bool
+	}{
+		{
+			name:  "number",
+			value: models.NumberValue(20), from: Celsius, to: Fahrenheit,
+			want: models.NumberValue(68),
+		},
+		{
+			name:  "object fields as gases",
+			value: models.ObjectValue(map[string]float64{"co2": 1000, "co": 24.45}), from: PPM, to: MgPerM3,
+			want: models.ObjectValue(map[string]float64{"co2": 1800, "co": 28.01}),
+		},
+		{
+			name:  "bool is unchanged",
+			value: models.BoolValue(true), from: Celsius, to: Fahrenheit,
+			want: models.BoolValue(true),
+		},
+		{
+			name:  "same unit",
+			value: models.NumberValue(5), from: PPM, to: PPM,
+			want: models.NumberValue(5),
+		},
+		{
+			name:  "number concentration has no gas",
+			value: models.NumberValue(400), from: PPM, to: MgPerM3,
+			wantErr: true,
+		},
+		{
+			name:  "object with unknown gas",
+			value: models.ObjectValue(map[string]float64{"co2": 400, "pm25": 12}), from: PPM, to: MgPerM3,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertValue(tt.value, tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !valuesClose(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// valuesClose сравнивает значения с точностью до ошибок округления
+func valuesClose(a, b models.Value) bool {
+	if a.Kind != b.Kind || a.Bool != b.Bool || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	if math.Abs(a.Number-b.Number) > 1e-9 {
+		return false
+	}
+	for name, v := range a.Fields {
+		w, ok := b.Fields[name]
+		if !ok || math.Abs(v-w) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,10 @@
+-- Таблица для хранения сработавших оповещений
+CREATE TABLE IF NOT EXISTS alerts (
+    id SERIAL PRIMARY KEY,
+    sensor_id VARCHAR(100) NOT NULL,
+    alert_type VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_created_at ON alerts(created_at);
@@ -0,0 +1,6 @@
+-- Таблица для хранения настроек пользователей бота
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id BIGINT PRIMARY KEY,
+    language VARCHAR(10) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
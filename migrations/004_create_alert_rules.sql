@@ -0,0 +1,14 @@
+-- Таблица для хранения правил оповещений, настраиваемых через бота
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    sensor_id VARCHAR(100) NOT NULL,
+    field VARCHAR(50) NOT NULL DEFAULT '',
+    operator VARCHAR(2) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Для датчика, поля и оператора действует одно правило
+    CONSTRAINT alert_rules_sensor_field_operator_key UNIQUE (sensor_id, field, operator)
+);
@@ -0,0 +1,14 @@
+-- Таблица для хранения команд и изменений состояния исполнительных устройств
+CREATE TABLE IF NOT EXISTS device_events (
+    id SERIAL PRIMARY KEY,
+    device_id VARCHAR(100) NOT NULL,
+    device_type VARCHAR(50) NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    command JSONB,
+    state JSONB,
+    source VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_events_device_id ON device_events(device_id);
+CREATE INDEX IF NOT EXISTS idx_device_events_created_at ON device_events(created_at);
@@ -0,0 +1,14 @@
+-- Таблица для хранения истории выполнения правил автоматизации
+CREATE TABLE IF NOT EXISTS automation_runs (
+    id SERIAL PRIMARY KEY,
+    rule_id VARCHAR(100) NOT NULL,
+    trigger TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL,
+    success BOOLEAN NOT NULL,
+    actions JSONB NOT NULL,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_automation_runs_rule_id ON automation_runs(rule_id);
+CREATE INDEX IF NOT EXISTS idx_automation_runs_created_at ON automation_runs(created_at);
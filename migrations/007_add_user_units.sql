@@ -0,0 +1,3 @@
+-- Предпочтительные единицы измерения пользователя (например, "°F,mg/m³");
+-- пустая строка - канонические единицы типов датчиков
+ALTER TABLE user_preferences ADD COLUMN IF NOT EXISTS units VARCHAR(50) NOT NULL DEFAULT '';